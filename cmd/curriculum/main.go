@@ -0,0 +1,157 @@
+package main
+
+// curriculum exports the curriculum of a database to a bundle file and
+// imports a bundle file into a database, e.g. to move a course from the
+// develop database to production:
+//
+//	curriculum export -db develop -o course.yaml
+//	curriculum import -db production -dry-run course.yaml
+//	curriculum import -db production course.yaml
+
+import (
+	_ "database-camp/internal/activities"
+	"database-camp/internal/infrastructure/cache"
+	"database-camp/internal/infrastructure/database"
+	"database-camp/internal/infrastructure/environment"
+	"database-camp/internal/models/entities/curriculum"
+	"database-camp/internal/repositories"
+	"database-camp/internal/services"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const usage = `usage:
+  curriculum export [-db develop|production] [-format yaml|json] [-o file]
+  curriculum import [-db develop|production] [-dry-run] file`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "export":
+		err = export(os.Args[2:])
+	case "import":
+		err = _import(os.Args[2:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func export(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	db := flags.String("db", "", "database to export from, develop or production (default from MODE)")
+	format := flags.String("format", "", "bundle format, yaml or json (default from -o, else yaml)")
+	output := flags.String("o", "", "file to write the bundle to (default stdout)")
+	flags.Parse(args)
+
+	if *format == "" {
+		*format = formatOf(*output)
+	}
+
+	service, closeDB, err := setup(*db)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	data, err := service.Export(*format)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	return ioutil.WriteFile(*output, data, 0644)
+}
+
+func _import(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	db := flags.String("db", "", "database to import into, develop or production (default from MODE)")
+	dryRun := flags.Bool("dry-run", false, "validate and print the changes without writing them")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return errors.New(usage)
+	}
+
+	data, err := ioutil.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	service, closeDB, err := setup(*db)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	diff, err := service.Import(data, formatOf(flags.Arg(0)), *dryRun)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(diff)
+
+	if *dryRun {
+		fmt.Println("dry run, nothing was written")
+	}
+
+	return nil
+}
+
+// setup connects to the database the flag names, the connection settings are
+// read from .env like the server does.
+func setup(db string) (services.CurriculumService, func() error, error) {
+	err := environment.New().Load(".env")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch db {
+	case "":
+	case "develop", "production":
+		os.Setenv("MODE", db)
+	default:
+		return nil, nil, fmt.Errorf("unknown database %q, use develop or production", db)
+	}
+
+	mysql := database.GetMySqlDBInstance()
+	err = mysql.OpenConnection()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	curriculumRepo := repositories.NewCurriculumRepository(mysql, cache.NewRedisClient())
+
+	return services.NewCurriculumService(curriculumRepo), mysql.CloseConnection, nil
+}
+
+func formatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return curriculum.FORMAT_JSON
+	case ".yaml", ".yml", "":
+		return curriculum.FORMAT_YAML
+	default:
+		return strings.TrimPrefix(filepath.Ext(path), ".")
+	}
+}
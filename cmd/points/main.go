@@ -0,0 +1,91 @@
+package main
+
+// points keeps the point of the users in line with the point ledger, e.g. to
+// see which users are off and then set their point to the sum of the ledger:
+//
+//	points reconcile -db production -dry-run
+//	points reconcile -db production
+
+import (
+	"database-camp/internal/infrastructure/cache"
+	"database-camp/internal/infrastructure/database"
+	"database-camp/internal/infrastructure/environment"
+	"database-camp/internal/repositories"
+	"database-camp/internal/services"
+	"flag"
+	"fmt"
+	"os"
+)
+
+const usage = `usage:
+  points reconcile [-db develop|production] [-dry-run]`
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "reconcile" {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err := reconcile(os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func reconcile(args []string) error {
+	flags := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	db := flags.String("db", "", "database to reconcile, develop or production (default from MODE)")
+	dryRun := flags.Bool("dry-run", false, "print the users that are off without changing them")
+	flags.Parse(args)
+
+	service, closeDB, err := setup(*db)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	discrepancies, err := service.Reconcile(*dryRun)
+	if err != nil {
+		return err
+	}
+
+	for _, discrepancy := range discrepancies {
+		fmt.Printf("user %d: point %d, ledger %d\n", discrepancy.UserID, discrepancy.Point, discrepancy.LedgerPoint)
+	}
+	fmt.Printf("%d users off the ledger\n", len(discrepancies))
+
+	if *dryRun {
+		fmt.Println("dry run, nothing was written")
+	}
+
+	return nil
+}
+
+// setup connects to the database the flag names, the connection settings are
+// read from .env like the server does.
+func setup(db string) (services.PointService, func() error, error) {
+	err := environment.New().Load(".env")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch db {
+	case "":
+	case "develop", "production":
+		os.Setenv("MODE", db)
+	default:
+		return nil, nil, fmt.Errorf("unknown database %q, use develop or production", db)
+	}
+
+	mysql := database.GetMySqlDBInstance()
+	err = mysql.OpenConnection()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cache := cache.NewRedisClient()
+	pointRepo := repositories.NewPointRepository(mysql, cache)
+	userRepo := repositories.NewUserRepository(mysql, cache)
+
+	return services.NewPointService(pointRepo, userRepo), mysql.CloseConnection, nil
+}
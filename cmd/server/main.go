@@ -15,6 +15,8 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+
+	"github.com/gofiber/fiber/v2"
 )
 
 func setupTimeZone() error {
@@ -49,7 +51,11 @@ func main() {
 	defer db.CloseConnection()
 
 	app := application.NewFiberApp()
-	regis := registry.Regis()
+	regis, err := registry.Regis()
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return
+	}
 
 	routes.NewRouter(app, regis)
 
@@ -64,6 +70,11 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	// Every prefork child runs this too, only the parent runs the jobs
+	if !fiber.IsChild() {
+		regis.StartJobs(ctx)
+	}
+
 	go func() {
 		if err := app.Listen(":" + os.Getenv("PORT")); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("listen: %s\n", err)
@@ -0,0 +1,12 @@
+package activities
+
+// activities links the activity types that are not built in, importing it
+// registers every type listed here with the activity and repositories
+// packages. A new type is a package of its own with an init registering its
+// activity.ActivityType, choice loader and choice writer.
+
+import (
+	_ "database-camp/internal/activities/normalization"
+	_ "database-camp/internal/activities/relationalalgebra"
+	_ "database-camp/internal/activities/sqlquery"
+)
@@ -0,0 +1,356 @@
+package normalization
+
+// normalization is the normalization exercise, the learner decomposes a
+// relation with functional dependencies into sub-relations. The decomposition
+// is graded on being lossless, preserving the dependencies and putting every
+// sub-relation in the normal form the activity targets.
+
+import (
+	"database-camp/internal/errs"
+	"database-camp/internal/infrastructure/cache"
+	"database-camp/internal/infrastructure/database"
+	"database-camp/internal/models/entities/activity"
+	"database-camp/internal/repositories"
+	"database-camp/internal/utils"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	TYPE_ID    = 9
+	TABLE_NAME = "NormalizationChoice"
+)
+
+// Choice is the relation to decompose, its functional dependencies are kept
+// in the dependency tables like the dependencies of a dependency activity
+type Choice struct {
+	ActivityID          int                   `gorm:"primaryKey;column:activity_id" json:"-"`
+	Relation            string                `gorm:"column:relation" json:"relation"`
+	AttributeList       string                `gorm:"column:attributes" json:"-"`
+	Attributes          []string              `gorm:"-" json:"attributes"`
+	Dependencies        []activity.Dependency `gorm:"-" json:"dependencies"`
+	TargetForm          string                `gorm:"column:target_form" json:"target_form"`
+	AllowDependencyLoss bool                  `gorm:"column:allow_dependency_loss" json:"allow_dependency_loss"`
+}
+
+func (choice Choice) CreatePropositionChoices() interface{} {
+	return map[string]interface{}{
+		"relation":              choice.Relation,
+		"attributes":            choice.Attributes,
+		"dependencies":          choice.Dependencies,
+		"target_form":           choice.TargetForm,
+		"allow_dependency_loss": choice.AllowDependencyLoss,
+	}
+}
+
+type Relation struct {
+	Name       string   `json:"name"`
+	Attributes []string `json:"attributes"`
+}
+
+// Answer is the decomposition of the learner
+type Answer struct {
+	Relations []Relation `json:"relations"`
+}
+
+// RelationFeedback is what the grader found about one sub-relation
+type RelationFeedback struct {
+	Name       string      `json:"name"`
+	Keys       [][]string  `json:"candidate_keys"`
+	NormalForm string      `json:"normal_form"`
+	Violations []Violation `json:"violations"`
+}
+
+// Feedback tells the learner which properties the decomposition misses
+type Feedback struct {
+	UnknownAttributes    []string           `json:"unknown_attributes"`
+	MissingAttributes    []string           `json:"missing_attributes"`
+	Lossless             bool               `json:"lossless"`
+	DependencyPreserving bool               `json:"dependency_preserving"`
+	LostDependencies     []string           `json:"lost_dependencies"`
+	InTargetForm         bool               `json:"in_target_form"`
+	Relations            []RelationFeedback `json:"relations"`
+}
+
+/**
+ * Check the decomposition of the learner
+ *
+ * @param choices 	choice of the activity
+ *
+ * @return the grade with the feedback on every property when one fails
+ */
+func (answer Answer) Grade(choices activity.Choices) (*activity.Grade, error) {
+	choice, ok := choices.(Choice)
+	if !ok {
+		return nil, errs.ErrAnswerInvalid
+	}
+
+	_schema := newSchema(choice.Attributes, choice.Dependencies)
+
+	feedback := Feedback{
+		UnknownAttributes: make([]string, 0),
+		LostDependencies:  make([]string, 0),
+		InTargetForm:      true,
+		Relations:         make([]RelationFeedback, 0, len(answer.Relations)),
+	}
+
+	var covered attributeSet
+	relations := make([]attributeSet, 0, len(answer.Relations))
+
+	for i, relation := range answer.Relations {
+		if strings.TrimSpace(relation.Name) == "" {
+			answer.Relations[i].Name = fmt.Sprintf("R%d", i+1)
+		}
+
+		set, unknown := _schema.set(relation.Attributes)
+		feedback.UnknownAttributes = append(feedback.UnknownAttributes, unknown...)
+
+		if set != 0 {
+			covered |= set
+			relations = append(relations, set)
+		}
+	}
+
+	feedback.MissingAttributes = _schema.names(_schema.all &^ covered)
+	feedback.Lossless = len(relations) > 0 && _schema.isLossless(relations)
+
+	for _, fd := range _schema.lostDependencies(relations) {
+		feedback.LostDependencies = append(feedback.LostDependencies, _schema.formatDependency(fd))
+	}
+	feedback.DependencyPreserving = len(feedback.LostDependencies) == 0
+
+	for _, relation := range answer.Relations {
+		set, _ := _schema.set(relation.Attributes)
+		if set == 0 {
+			continue
+		}
+
+		keys, normalForm, violations := _schema.normalForm(set, choice.TargetForm)
+
+		relationFeedback := RelationFeedback{
+			Name:       relation.Name,
+			Keys:       make([][]string, 0, len(keys)),
+			NormalForm: normalForm,
+			Violations: violations,
+		}
+
+		for _, key := range keys {
+			relationFeedback.Keys = append(relationFeedback.Keys, _schema.names(key))
+		}
+
+		if len(violations) > 0 {
+			feedback.InTargetForm = false
+		}
+
+		feedback.Relations = append(feedback.Relations, relationFeedback)
+	}
+
+	items := make([]activity.ItemResult, 0, len(feedback.Relations))
+	for _, relation := range feedback.Relations {
+		items = append(items, activity.ItemResult{Item: relation.Name, IsCorrect: len(relation.Violations) == 0})
+	}
+
+	// Every property the decomposition has earns its share of the score
+	properties := []bool{
+		len(relations) > 0 && len(feedback.UnknownAttributes) == 0 && len(feedback.MissingAttributes) == 0,
+		feedback.Lossless,
+		feedback.InTargetForm,
+	}
+	if !choice.AllowDependencyLoss {
+		properties = append(properties, feedback.DependencyPreserving)
+	}
+
+	kept := 0
+	for _, property := range properties {
+		if property {
+			kept++
+		}
+	}
+
+	if kept == len(properties) {
+		return &activity.Grade{IsCorrect: true, Score: 1, Items: items}, nil
+	}
+
+	message := feedback.Message(choice.TargetForm, choice.AllowDependencyLoss)
+
+	return &activity.Grade{
+		IsCorrect: false,
+		Score:     float64(kept) / float64(len(properties)),
+		Items:     items,
+		Message:   &message,
+		Feedback:  feedback,
+	}, nil
+}
+
+// Message explains in Thai every property the decomposition misses
+func (feedback Feedback) Message(targetForm string, allowDependencyLoss bool) string {
+	lines := []string{}
+
+	if len(feedback.Relations) == 0 {
+		lines = append(lines, "ต้องแยกรีเลชันออกเป็นอย่างน้อยหนึ่งรีเลชันย่อย")
+	}
+
+	if len(feedback.UnknownAttributes) > 0 {
+		lines = append(lines, fmt.Sprintf("ไม่มีแอตทริบิวต์ %s ในรีเลชันที่กำหนด", strings.Join(feedback.UnknownAttributes, ", ")))
+	}
+
+	if len(feedback.MissingAttributes) > 0 {
+		lines = append(lines, fmt.Sprintf("แอตทริบิวต์ %s ไม่อยู่ในรีเลชันย่อยใดเลย", strings.Join(feedback.MissingAttributes, ", ")))
+	}
+
+	if !feedback.Lossless {
+		lines = append(lines, "การแยกรีเลชันไม่เป็น lossless join เมื่อนำรีเลชันย่อยมา join กันจะได้ tuple ที่ไม่มีอยู่ในรีเลชันเดิม")
+	}
+
+	if !feedback.DependencyPreserving && !allowDependencyLoss {
+		lines = append(lines, fmt.Sprintf("การแยกรีเลชันไม่รักษา functional dependency %s", strings.Join(feedback.LostDependencies, ", ")))
+	}
+
+	for _, relation := range feedback.Relations {
+		for _, violation := range relation.Violations {
+			lines = append(lines, fmt.Sprintf("รีเลชัน %s ไม่อยู่ในรูป %s เพราะ %s ทำให้ %s", relation.Name, targetForm, violation.Dependency, violation.Reason))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// sortDependencies puts the dependencies in one order whether they are
+// authored or loaded, the dependency tables keep no order
+func sortDependencies(dependencies []activity.Dependency) []activity.Dependency {
+	sorted := make([]activity.Dependency, 0, len(dependencies))
+
+	for _, dependency := range dependencies {
+		determinants := make([]activity.Determinant, 0, len(dependency.Determinants))
+		for _, determinant := range dependency.Determinants {
+			determinants = append(determinants, activity.Determinant{Value: determinant.Value, Fixed: true})
+		}
+		sort.Slice(determinants, func(i, j int) bool { return determinants[i].Value < determinants[j].Value })
+
+		sorted = append(sorted, activity.Dependency{
+			Dependent:    dependency.Dependent,
+			Fixed:        true,
+			Determinants: determinants,
+		})
+	}
+
+	key := func(dependency activity.Dependency) string {
+		values := make([]string, 0, len(dependency.Determinants))
+		for _, determinant := range dependency.Determinants {
+			values = append(values, determinant.Value)
+		}
+		return dependency.Dependent + "\x00" + strings.Join(values, "\x00")
+	}
+
+	sort.Slice(sorted, func(i, j int) bool { return key(sorted[i]) < key(sorted[j]) })
+
+	return sorted
+}
+
+func parseChoices(choices interface{}) (interface{}, error) {
+	var choice Choice
+	if err := utils.StructToStruct(choices, &choice); err != nil {
+		return nil, errs.ErrChoicesInvalid
+	}
+
+	if _, ok := formRanks[choice.TargetForm]; !ok || strings.TrimSpace(choice.Relation) == "" {
+		return nil, errs.ErrChoicesInvalid
+	}
+
+	// The attributes are stored separated by commas
+	if len(choice.Attributes) == 0 || len(choice.Attributes) > MAX_ATTRIBUTES {
+		return nil, errs.ErrChoicesInvalid
+	}
+
+	attributes := map[string]bool{}
+	for _, attribute := range choice.Attributes {
+		if strings.TrimSpace(attribute) == "" || strings.Contains(attribute, ",") || attributes[attribute] {
+			return nil, errs.ErrChoicesInvalid
+		}
+		attributes[attribute] = true
+	}
+
+	if len(choice.Dependencies) == 0 {
+		return nil, errs.ErrChoicesInvalid
+	}
+
+	for _, dependency := range choice.Dependencies {
+		if !attributes[dependency.Dependent] || len(dependency.Determinants) == 0 {
+			return nil, errs.ErrChoicesInvalid
+		}
+
+		determinants := map[string]bool{}
+		for _, determinant := range dependency.Determinants {
+			if !attributes[determinant.Value] || determinants[determinant.Value] {
+				return nil, errs.ErrChoicesInvalid
+			}
+			determinants[determinant.Value] = true
+		}
+	}
+
+	choice.Dependencies = sortDependencies(choice.Dependencies)
+
+	return choice, nil
+}
+
+func init() {
+	activity.RegisterType(activity.ActivityType{
+		ID:   TYPE_ID,
+		Name: "normalization",
+		DecodeAnswer: func(answer interface{}) (activity.Answer, error) {
+			var normalizationAnswer Answer
+			err := utils.StructToStruct(answer, &normalizationAnswer)
+			return normalizationAnswer, err
+		},
+		ParseChoices: parseChoices,
+	})
+
+	repositories.RegisterChoiceLoader(TYPE_ID, func(db database.MysqlDB, cache cache.Cache, activityID int) (activity.Choices, error) {
+		var choice Choice
+
+		err := db.GetDB().
+			Table(TABLE_NAME).
+			Where("activity_id = ?", activityID).
+			Take(&choice).
+			Error
+		if err != nil {
+			return choice, err
+		}
+
+		choice.Attributes = strings.Split(choice.AttributeList, ",")
+
+		dependencyChoice, err := repositories.NewLearningRepository(db, cache).GetActivityChoices(activityID, activity.TYPE_DEPENDENCY)
+		if err != nil {
+			return choice, err
+		}
+
+		choice.Dependencies = sortDependencies(dependencyChoice.(activity.DependencyChoice).Dependencies)
+
+		return choice, nil
+	})
+
+	// The dependencies are removed with the dependency choices of the
+	// activity, only the relation is removed here
+	repositories.RegisterChoiceWriter(TYPE_ID, repositories.ChoiceWriter{
+		Insert: func(tx *gorm.DB, activityID int, choices interface{}) error {
+			choice := choices.(Choice)
+			choice.ActivityID = activityID
+			choice.AttributeList = strings.Join(choice.Attributes, ",")
+
+			err := tx.Table(TABLE_NAME).Create(&choice).Error
+			if err != nil {
+				return err
+			}
+
+			return repositories.InsertDependencyChoice(tx, activityID, activity.NewDependencyChoiceSpec(activity.DependencyChoice{
+				Dependencies: choice.Dependencies,
+			}))
+		},
+		Delete: func(tx *gorm.DB, activityID int) error {
+			return tx.Table(TABLE_NAME).Where("activity_id = ?", activityID).Delete(&Choice{}).Error
+		},
+	})
+}
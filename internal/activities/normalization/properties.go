@@ -0,0 +1,236 @@
+package normalization
+
+import (
+	"fmt"
+)
+
+// Normal forms a choice can target, in increasing strength
+const (
+	FORM_2NF  = "2NF"
+	FORM_3NF  = "3NF"
+	FORM_BCNF = "BCNF"
+)
+
+var formRanks = map[string]int{
+	FORM_2NF:  2,
+	FORM_3NF:  3,
+	FORM_BCNF: 4,
+}
+
+/**
+ * Check that joining the sub-relations gives back the relation and nothing
+ * more, with the chase of the tableau of the decomposition
+ *
+ * @param relations 	attributes of every sub-relation
+ *
+ * @return whether the decomposition is lossless
+ */
+func (s schema) isLossless(relations []attributeSet) bool {
+	// Row i is sub-relation i, 0 is the distinguished symbol of a column and
+	// i+1 the symbol only row i has
+	table := make([][]int, len(relations))
+	for i, relation := range relations {
+		table[i] = make([]int, len(s.attributes))
+		for j := range s.attributes {
+			if relation&(1<<uint(j)) == 0 {
+				table[i][j] = i + 1
+			}
+		}
+	}
+
+	agree := func(row1 []int, row2 []int, set attributeSet) bool {
+		for j := range s.attributes {
+			if set&(1<<uint(j)) != 0 && row1[j] != row2[j] {
+				return false
+			}
+		}
+		return true
+	}
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, fd := range s.fds {
+			for i := range table {
+				for k := i + 1; k < len(table); k++ {
+					if !agree(table[i], table[k], fd.from) {
+						continue
+					}
+
+					for j := range s.attributes {
+						if fd.to&(1<<uint(j)) == 0 || table[i][j] == table[k][j] {
+							continue
+						}
+
+						// Equate the symbols, the distinguished one wins
+						keep, replace := table[i][j], table[k][j]
+						if replace < keep {
+							keep, replace = replace, keep
+						}
+						for _, row := range table {
+							if row[j] == replace {
+								row[j] = keep
+							}
+						}
+						changed = true
+					}
+				}
+			}
+		}
+	}
+
+	for _, row := range table {
+		if agree(row, make([]int, len(s.attributes)), s.all) {
+			return true
+		}
+	}
+
+	return false
+}
+
+/**
+ * Find the dependencies that cannot be inferred from the dependencies holding
+ * inside the sub-relations
+ *
+ * @param relations 	attributes of every sub-relation
+ *
+ * @return the lost dependencies
+ */
+func (s schema) lostDependencies(relations []attributeSet) []functionalDependency {
+	lost := make([]functionalDependency, 0)
+
+	for _, fd := range s.fds {
+		// The closure of fd.from under the projected dependencies, computed
+		// without projecting them
+		result := fd.from
+		for changed := true; changed; {
+			changed = false
+			for _, relation := range relations {
+				added := s.closure(result&relation) & relation &^ result
+				if added != 0 {
+					result |= added
+					changed = true
+				}
+			}
+		}
+
+		if fd.to&^result != 0 {
+			lost = append(lost, fd)
+		}
+	}
+
+	return lost
+}
+
+// Violation is a dependency holding in a sub-relation that breaks a normal form
+type Violation struct {
+	Form       string `json:"form"`
+	Dependency string `json:"dependency"`
+	Reason     string `json:"reason"`
+}
+
+/**
+ * Check a sub-relation against the normal forms up to the target, using the
+ * dependencies projected on it
+ *
+ * @param relation 	attributes of the sub-relation
+ * @param target 	normal form to reach
+ *
+ * @return the candidate keys, the highest normal form the sub-relation is in
+ * and the violations of the weakest normal form it misses
+ */
+func (s schema) normalForm(relation attributeSet, target string) ([]attributeSet, string, []Violation) {
+	keys := s.candidateKeys(relation)
+
+	var prime attributeSet
+	for _, key := range keys {
+		prime |= key
+	}
+
+	violations := map[string][]Violation{}
+
+	// reported remembers the smallest determinants found for every attribute,
+	// so a dependency implied by a reported one is not reported again
+	reported := map[string]map[int][]attributeSet{}
+	report := func(form string, from attributeSet, to attributeSet, reason string) {
+		if reported[form] == nil {
+			reported[form] = map[int][]attributeSet{}
+		}
+
+		var fresh attributeSet
+		for j := range s.attributes {
+			if to&(1<<uint(j)) == 0 {
+				continue
+			}
+
+			implied := false
+			for _, smaller := range reported[form][j] {
+				if smaller&^from == 0 {
+					implied = true
+					break
+				}
+			}
+
+			if !implied {
+				fresh |= 1 << uint(j)
+				reported[form][j] = append(reported[form][j], from)
+			}
+		}
+
+		if fresh != 0 {
+			violations[form] = append(violations[form], Violation{
+				Form:       form,
+				Dependency: s.formatDependency(functionalDependency{from: from, to: fresh}),
+				Reason:     reason,
+			})
+		}
+	}
+
+	for _, from := range subsets(relation) {
+		determined := s.closure(from) & relation
+		if determined == relation {
+			continue
+		}
+
+		nontrivial := determined &^ from
+		if nontrivial == 0 {
+			continue
+		}
+
+		report(FORM_BCNF, from, nontrivial, fmt.Sprintf("%s ไม่ใช่ super key ของรีเลชัน", s.format(from)))
+
+		nonPrime := nontrivial &^ prime
+		if nonPrime == 0 {
+			continue
+		}
+
+		report(FORM_3NF, from, nonPrime, fmt.Sprintf("%s ไม่ใช่ super key และ %s ไม่ใช่ prime attribute", s.format(from), s.format(nonPrime)))
+
+		for _, key := range keys {
+			if from&^key == 0 && from != key {
+				report(FORM_2NF, from, nonPrime, fmt.Sprintf("%s ขึ้นกับบางส่วนของ candidate key %s (partial dependency)", s.format(nonPrime), s.format(key)))
+				break
+			}
+		}
+	}
+
+	highest := "1NF"
+	for _, form := range []string{FORM_2NF, FORM_3NF, FORM_BCNF} {
+		if len(violations[form]) > 0 {
+			break
+		}
+		highest = form
+	}
+
+	// Show why the weakest missed form up to the target is missed
+	for _, form := range []string{FORM_2NF, FORM_3NF, FORM_BCNF} {
+		if formRanks[form] > formRanks[target] {
+			break
+		}
+		if len(violations[form]) > 0 {
+			return keys, highest, violations[form]
+		}
+	}
+
+	return keys, highest, []Violation{}
+}
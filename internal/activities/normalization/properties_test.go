@@ -0,0 +1,165 @@
+package normalization
+
+import (
+	"database-camp/internal/models/entities/activity"
+	"reflect"
+	"testing"
+)
+
+func dependency(dependent string, determinants ...string) activity.Dependency {
+	d := activity.Dependency{Dependent: dependent}
+	for _, value := range determinants {
+		d.Determinants = append(d.Determinants, activity.Determinant{Value: value})
+	}
+	return d
+}
+
+func (s schema) sets(relations ...[]string) []attributeSet {
+	sets := make([]attributeSet, 0, len(relations))
+	for _, relation := range relations {
+		set, _ := s.set(relation)
+		sets = append(sets, set)
+	}
+	return sets
+}
+
+func (s schema) formatSets(sets []attributeSet) []string {
+	formatted := make([]string, 0, len(sets))
+	for _, set := range sets {
+		formatted = append(formatted, s.format(set))
+	}
+	return formatted
+}
+
+// Enrollment has the key {id, course} and name depends on id only
+var enrollment = newSchema(
+	[]string{"id", "course", "name", "grade"},
+	[]activity.Dependency{dependency("name", "id"), dependency("grade", "id", "course")},
+)
+
+// Employee has the key {id} and building depends on it through dept
+var employee = newSchema(
+	[]string{"id", "dept", "building"},
+	[]activity.Dependency{dependency("dept", "id"), dependency("building", "dept")},
+)
+
+// Teaching is in 3NF but not in BCNF, a teacher teaches one subject
+var teaching = newSchema(
+	[]string{"student", "subject", "teacher"},
+	[]activity.Dependency{dependency("teacher", "student", "subject"), dependency("subject", "teacher")},
+)
+
+func TestIsLossless(t *testing.T) {
+	tests := []struct {
+		name       string
+		schema     schema
+		relations  [][]string
+		isLossless bool
+	}{
+		{"whole relation", employee, [][]string{{"id", "dept", "building"}}, true},
+		{"split on a key", employee, [][]string{{"id", "dept"}, {"dept", "building"}}, true},
+		{"split on a non key", employee, [][]string{{"id", "building"}, {"dept", "building"}}, false},
+		{"split on a partial key", enrollment, [][]string{{"id", "name"}, {"id", "course", "grade"}}, true},
+		{"no common attribute", enrollment, [][]string{{"id", "name"}, {"course", "grade"}}, false},
+		{"bcnf split", teaching, [][]string{{"teacher", "subject"}, {"student", "teacher"}}, true},
+		{"chase over three", employee, [][]string{{"id", "dept"}, {"dept"}, {"dept", "building"}}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			isLossless := test.schema.isLossless(test.schema.sets(test.relations...))
+			if isLossless != test.isLossless {
+				t.Errorf("isLossless = %v, want %v", isLossless, test.isLossless)
+			}
+		})
+	}
+}
+
+func TestLostDependencies(t *testing.T) {
+	tests := []struct {
+		name      string
+		schema    schema
+		relations [][]string
+		lost      []string
+	}{
+		{"whole relation", teaching, [][]string{{"student", "subject", "teacher"}}, []string{}},
+		{"3nf split", employee, [][]string{{"id", "dept"}, {"dept", "building"}}, []string{}},
+		{"inferred through another relation", employee, [][]string{{"id", "dept"}, {"id", "building"}, {"dept", "building"}}, []string{}},
+		{"transitive split", employee, [][]string{{"id", "dept"}, {"id", "building"}}, []string{"{dept} → {building}"}},
+		{"bcnf split", teaching, [][]string{{"teacher", "subject"}, {"student", "teacher"}}, []string{"{student, subject} → {teacher}"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lost := make([]string, 0)
+			for _, fd := range test.schema.lostDependencies(test.schema.sets(test.relations...)) {
+				lost = append(lost, test.schema.formatDependency(fd))
+			}
+
+			if !reflect.DeepEqual(lost, test.lost) {
+				t.Errorf("lostDependencies = %v, want %v", lost, test.lost)
+			}
+		})
+	}
+}
+
+func TestCandidateKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   schema
+		relation []string
+		keys     []string
+	}{
+		{"composite key", enrollment, []string{"id", "course", "name", "grade"}, []string{"{id, course}"}},
+		{"projected", enrollment, []string{"id", "name"}, []string{"{id}"}},
+		{"no dependency inside", enrollment, []string{"course", "name"}, []string{"{course, name}"}},
+		{"single key", employee, []string{"id", "dept", "building"}, []string{"{id}"}},
+		{"overlapping keys", teaching, []string{"student", "subject", "teacher"}, []string{"{student, subject}", "{student, teacher}"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			keys := test.schema.formatSets(test.schema.candidateKeys(test.schema.sets(test.relation)[0]))
+			if !reflect.DeepEqual(keys, test.keys) {
+				t.Errorf("candidateKeys = %v, want %v", keys, test.keys)
+			}
+		})
+	}
+}
+
+func TestNormalForm(t *testing.T) {
+	tests := []struct {
+		name       string
+		schema     schema
+		relation   []string
+		target     string
+		highest    string
+		violations []string
+	}{
+		{"partial dependency", enrollment, []string{"id", "course", "name", "grade"}, FORM_BCNF, "1NF", []string{"{id} → {name}"}},
+		{"partial dependency removed", enrollment, []string{"id", "course", "grade"}, FORM_BCNF, FORM_BCNF, []string{}},
+		{"transitive dependency", employee, []string{"id", "dept", "building"}, FORM_3NF, FORM_2NF, []string{"{dept} → {building}"}},
+		{"transitive dependency under target", employee, []string{"id", "dept", "building"}, FORM_2NF, FORM_2NF, []string{}},
+		{"transitive dependency removed", employee, []string{"dept", "building"}, FORM_BCNF, FORM_BCNF, []string{}},
+		{"determinant not a key", teaching, []string{"student", "subject", "teacher"}, FORM_BCNF, FORM_3NF, []string{"{teacher} → {subject}"}},
+		{"determinant not a key under target", teaching, []string{"student", "subject", "teacher"}, FORM_3NF, FORM_3NF, []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, highest, violations := test.schema.normalForm(test.schema.sets(test.relation)[0], test.target)
+
+			if highest != test.highest {
+				t.Errorf("highest = %v, want %v", highest, test.highest)
+			}
+
+			dependencies := make([]string, 0)
+			for _, violation := range violations {
+				dependencies = append(dependencies, violation.Dependency)
+			}
+			if !reflect.DeepEqual(dependencies, test.violations) {
+				t.Errorf("violations = %v, want %v", dependencies, test.violations)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package normalization
+
+import (
+	"database-camp/internal/models/entities/activity"
+	"math/bits"
+	"sort"
+	"strings"
+)
+
+// MAX_ATTRIBUTES bounds the relations of the exercise, keys and normal forms
+// are found by going through every subset of the attributes
+const MAX_ATTRIBUTES = 16
+
+// attributeSet is a set of attributes of the relation, bit i is attribute i
+type attributeSet uint32
+
+type functionalDependency struct {
+	from attributeSet
+	to   attributeSet
+}
+
+// schema is the relation of a choice with its functional dependencies
+type schema struct {
+	attributes []string
+	index      map[string]int
+	all        attributeSet
+	fds        []functionalDependency
+}
+
+func newSchema(attributes []string, dependencies []activity.Dependency) schema {
+	s := schema{
+		attributes: attributes,
+		index:      map[string]int{},
+		fds:        make([]functionalDependency, 0, len(dependencies)),
+	}
+
+	for i, attribute := range attributes {
+		s.index[attribute] = i
+		s.all |= 1 << uint(i)
+	}
+
+	for _, dependency := range dependencies {
+		fd := functionalDependency{to: s.bit(dependency.Dependent)}
+		for _, determinant := range dependency.Determinants {
+			fd.from |= s.bit(determinant.Value)
+		}
+		s.fds = append(s.fds, fd)
+	}
+
+	return s
+}
+
+func (s schema) bit(attribute string) attributeSet {
+	i, ok := s.index[attribute]
+	if !ok {
+		return 0
+	}
+	return 1 << uint(i)
+}
+
+// set returns the set of the attributes and the ones not in the relation
+func (s schema) set(attributes []string) (attributeSet, []string) {
+	var set attributeSet
+	unknown := make([]string, 0)
+
+	for _, attribute := range attributes {
+		if bit := s.bit(attribute); bit != 0 {
+			set |= bit
+		} else {
+			unknown = append(unknown, attribute)
+		}
+	}
+
+	return set, unknown
+}
+
+func (s schema) names(set attributeSet) []string {
+	names := make([]string, 0, bits.OnesCount32(uint32(set)))
+	for i, attribute := range s.attributes {
+		if set&(1<<uint(i)) != 0 {
+			names = append(names, attribute)
+		}
+	}
+	return names
+}
+
+func (s schema) format(set attributeSet) string {
+	return "{" + strings.Join(s.names(set), ", ") + "}"
+}
+
+func (s schema) formatDependency(fd functionalDependency) string {
+	return s.format(fd.from) + " → " + s.format(fd.to)
+}
+
+// closure is every attribute the set determines under the dependencies
+func (s schema) closure(set attributeSet) attributeSet {
+	for changed := true; changed; {
+		changed = false
+		for _, fd := range s.fds {
+			if fd.from&^set == 0 && fd.to&^set != 0 {
+				set |= fd.to
+				changed = true
+			}
+		}
+	}
+	return set
+}
+
+// subsets lists the non empty subsets of the set, smallest first
+func subsets(set attributeSet) []attributeSet {
+	result := make([]attributeSet, 0, 1<<uint(bits.OnesCount32(uint32(set))))
+	for subset := set; subset != 0; subset = (subset - 1) & set {
+		result = append(result, subset)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		ci, cj := bits.OnesCount32(uint32(result[i])), bits.OnesCount32(uint32(result[j]))
+		if ci != cj {
+			return ci < cj
+		}
+		return result[i] < result[j]
+	})
+
+	return result
+}
+
+// candidateKeys of a sub-relation are the minimal sets determining all of it
+func (s schema) candidateKeys(relation attributeSet) []attributeSet {
+	keys := make([]attributeSet, 0)
+
+	for _, subset := range subsets(relation) {
+		isSuperKey := false
+		for _, key := range keys {
+			if key&^subset == 0 {
+				isSuperKey = true
+				break
+			}
+		}
+
+		if !isSuperKey && s.closure(subset)&relation == relation {
+			keys = append(keys, subset)
+		}
+	}
+
+	return keys
+}
@@ -0,0 +1,488 @@
+package relationalalgebra
+
+import (
+	"database-camp/internal/errs"
+	"fmt"
+)
+
+// environment is the sample relations of an activity by name
+type environment map[string]*relation
+
+type expression interface {
+	evaluate(env environment) (*relation, error)
+}
+
+type relationName struct {
+	name string
+}
+
+func (e relationName) evaluate(env environment) (*relation, error) {
+	r, ok := env[e.name]
+	if !ok {
+		return nil, errs.NewBadRequestError(
+			fmt.Sprintf(errs.EXPRESSION_RELATION_NOT_FOUND_TH, e.name),
+			fmt.Sprintf(errs.EXPRESSION_RELATION_NOT_FOUND_EN, e.name),
+		)
+	}
+	return r, nil
+}
+
+// σ[condition](input)
+type selection struct {
+	condition condition
+	input     expression
+}
+
+func (e selection) evaluate(env environment) (*relation, error) {
+	input, err := e.input.evaluate(env)
+	if err != nil {
+		return nil, err
+	}
+
+	test, err := e.condition.bind(input)
+	if err != nil {
+		return nil, err
+	}
+
+	result := newRelation(input.attributes)
+	for _, row := range input.rows {
+		if test(row) {
+			if err = result.add(row); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// π[attributes](input)
+type projection struct {
+	attributes []attribute
+	input      expression
+}
+
+func (e projection) evaluate(env environment) (*relation, error) {
+	input, err := e.input.evaluate(env)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make([]int, len(e.attributes))
+	attributes := make([]attribute, len(e.attributes))
+	for i, ref := range e.attributes {
+		if indexes[i], err = input.resolve(ref); err != nil {
+			return nil, err
+		}
+		attributes[i] = input.attributes[indexes[i]]
+	}
+
+	result := newRelation(attributes)
+	for _, row := range input.rows {
+		projected := make([]value, len(indexes))
+		for i, index := range indexes {
+			projected[i] = row[index]
+		}
+		if err = result.add(projected); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// ρ[name](input) or ρ[name(attributes)](input)
+type rename struct {
+	name       string
+	attributes []string
+	input      expression
+}
+
+func (e rename) evaluate(env environment) (*relation, error) {
+	input, err := e.input.evaluate(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.attributes != nil && len(e.attributes) != len(input.attributes) {
+		return nil, errs.NewBadRequestError(
+			fmt.Sprintf(errs.EXPRESSION_RENAME_INVALID_TH, len(input.attributes)),
+			fmt.Sprintf(errs.EXPRESSION_RENAME_INVALID_EN, len(input.attributes)),
+		)
+	}
+
+	attributes := make([]attribute, len(input.attributes))
+	for i, a := range input.attributes {
+		attributes[i] = attribute{qualifier: e.name, name: a.name}
+		if e.attributes != nil {
+			attributes[i].name = e.attributes[i]
+		}
+	}
+
+	return &relation{attributes: attributes, rows: input.rows, keys: input.keys}, nil
+}
+
+type aggregate struct {
+	function  string
+	attribute *attribute
+	alias     string
+}
+
+func (a aggregate) name() string {
+	switch {
+	case a.alias != "":
+		return a.alias
+	case a.attribute == nil:
+		return a.function
+	default:
+		return a.function + "_" + a.attribute.name
+	}
+}
+
+// compute folds the values of one group, index is -1 for count(*)
+func (a aggregate) compute(rows [][]value, index int) (value, error) {
+	if index < 0 {
+		return value{isNumber: true, number: float64(len(rows))}, nil
+	}
+
+	values := make([]value, 0, len(rows))
+	for _, row := range rows {
+		if !row[index].null {
+			values = append(values, row[index])
+		}
+	}
+
+	switch a.function {
+	case "count":
+		return value{isNumber: true, number: float64(len(values))}, nil
+
+	case "sum", "avg":
+		if len(values) == 0 {
+			return value{null: true}, nil
+		}
+
+		sum := 0.0
+		for _, v := range values {
+			if !v.isNumber {
+				return value{}, errs.NewBadRequestError(
+					fmt.Sprintf(errs.EXPRESSION_AGGREGATE_INVALID_TH, a.function),
+					fmt.Sprintf(errs.EXPRESSION_AGGREGATE_INVALID_EN, a.function),
+				)
+			}
+			sum += v.number
+		}
+
+		if a.function == "avg" {
+			sum /= float64(len(values))
+		}
+		return value{isNumber: true, number: sum}, nil
+
+	default:
+		if len(values) == 0 {
+			return value{null: true}, nil
+		}
+
+		best := values[0]
+		for _, v := range values[1:] {
+			order, _ := v.compare(best)
+			if (a.function == "min" && order < 0) || (a.function == "max" && order > 0) {
+				best = v
+			}
+		}
+		return best, nil
+	}
+}
+
+// γ[grouping attributes, aggregates](input)
+type grouping struct {
+	groupBy    []attribute
+	aggregates []aggregate
+	input      expression
+}
+
+func (e grouping) evaluate(env environment) (*relation, error) {
+	input, err := e.input.evaluate(env)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := make([]attribute, 0, len(e.groupBy)+len(e.aggregates))
+
+	groupIndexes := make([]int, len(e.groupBy))
+	for i, ref := range e.groupBy {
+		if groupIndexes[i], err = input.resolve(ref); err != nil {
+			return nil, err
+		}
+		attributes = append(attributes, input.attributes[groupIndexes[i]])
+	}
+
+	aggregateIndexes := make([]int, len(e.aggregates))
+	for i, a := range e.aggregates {
+		aggregateIndexes[i] = -1
+		if a.attribute != nil {
+			if aggregateIndexes[i], err = input.resolve(*a.attribute); err != nil {
+				return nil, err
+			}
+		}
+		attributes = append(attributes, attribute{name: a.name()})
+	}
+
+	groups := map[string][][]value{}
+	order := make([][]value, 0)
+
+	for _, row := range input.rows {
+		group := make([]value, len(groupIndexes))
+		for i, index := range groupIndexes {
+			group[i] = row[index]
+		}
+
+		key := rowKey(group)
+		if _, ok := groups[key]; !ok {
+			order = append(order, group)
+		}
+		groups[key] = append(groups[key], row)
+	}
+
+	// Without grouping attributes the whole relation is one group, even empty
+	if len(e.groupBy) == 0 && len(order) == 0 {
+		order = append(order, []value{})
+	}
+
+	result := newRelation(attributes)
+	for _, group := range order {
+		row := append([]value{}, group...)
+		for i, a := range e.aggregates {
+			v, err := a.compute(groups[rowKey(group)], aggregateIndexes[i])
+			if err != nil {
+				return nil, err
+			}
+			row = append(row, v)
+		}
+
+		if err = result.add(row); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// left ⋈ right, left ⋈[condition] right or left × right
+type join struct {
+	left      expression
+	right     expression
+	condition condition
+	natural   bool
+}
+
+func (e join) evaluate(env environment) (*relation, error) {
+	left, err := e.left.evaluate(env)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := e.right.evaluate(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(left.rows)*len(right.rows) > MAX_JOIN_PAIRS {
+		return nil, errs.ErrExpressionTooLarge
+	}
+
+	// A natural join matches the attributes of the same name and keeps them
+	// once, from the left
+	type pair struct{ left, right int }
+	common := make([]pair, 0)
+	kept := make([]int, 0, len(right.attributes))
+
+	attributes := append([]attribute{}, left.attributes...)
+	for j, b := range right.attributes {
+		matched := false
+		if e.natural {
+			for i, a := range left.attributes {
+				if a.name == b.name {
+					common = append(common, pair{i, j})
+					matched = true
+					break
+				}
+			}
+		}
+		if !matched {
+			kept = append(kept, j)
+			attributes = append(attributes, b)
+		}
+	}
+
+	result := newRelation(attributes)
+
+	test := func(row []value) bool { return true }
+	if e.condition != nil {
+		if test, err = e.condition.bind(result); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, l := range left.rows {
+	rows:
+		for _, r := range right.rows {
+			for _, p := range common {
+				if order, ok := l[p.left].compare(r[p.right]); !ok || order != 0 {
+					continue rows
+				}
+			}
+
+			row := append([]value{}, l...)
+			for _, j := range kept {
+				row = append(row, r[j])
+			}
+
+			if test(row) {
+				if err = result.add(row); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// left ∪ right, left − right or left ∩ right
+type setOperation struct {
+	operator tokenKind
+	symbol   string
+	left     expression
+	right    expression
+}
+
+func (e setOperation) evaluate(env environment) (*relation, error) {
+	left, err := e.left.evaluate(env)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := e.right.evaluate(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(left.attributes) != len(right.attributes) {
+		return nil, errs.NewBadRequestError(
+			fmt.Sprintf(errs.EXPRESSION_NOT_UNION_COMPATIBLE_TH, e.symbol),
+			fmt.Sprintf(errs.EXPRESSION_NOT_UNION_COMPATIBLE_EN, e.symbol),
+		)
+	}
+
+	result := newRelation(left.attributes)
+
+	for _, row := range left.rows {
+		if e.operator == tokenUnion || (e.operator == tokenMinus) != right.has(row) {
+			if err = result.add(row); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if e.operator == tokenUnion {
+		for _, row := range right.rows {
+			if err = result.add(row); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+type condition interface {
+	bind(r *relation) (func(row []value) bool, error)
+}
+
+// operand is an attribute or a literal value
+type operand struct {
+	attribute *attribute
+	literal   value
+}
+
+func (o operand) bind(r *relation) (func(row []value) value, error) {
+	if o.attribute == nil {
+		return func(row []value) value { return o.literal }, nil
+	}
+
+	index, err := r.resolve(*o.attribute)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(row []value) value { return row[index] }, nil
+}
+
+type comparison struct {
+	left     operand
+	operator string
+	right    operand
+}
+
+func (c comparison) bind(r *relation) (func(row []value) bool, error) {
+	left, err := c.left.bind(r)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := c.right.bind(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(row []value) bool {
+		order, ok := left(row).compare(right(row))
+		if !ok {
+			return false
+		}
+
+		switch c.operator {
+		case "=":
+			return order == 0
+		case "<>":
+			return order != 0
+		case "<":
+			return order < 0
+		case "<=":
+			return order <= 0
+		case ">":
+			return order > 0
+		default:
+			return order >= 0
+		}
+	}, nil
+}
+
+// logical is ∧ and ∨ over two conditions, or ¬ over the left one
+type logical struct {
+	operator tokenKind
+	left     condition
+	right    condition
+}
+
+func (c logical) bind(r *relation) (func(row []value) bool, error) {
+	left, err := c.left.bind(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.operator == tokenNot {
+		return func(row []value) bool { return !left(row) }, nil
+	}
+
+	right, err := c.right.bind(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.operator == tokenAnd {
+		return func(row []value) bool { return left(row) && right(row) }, nil
+	}
+
+	return func(row []value) bool { return left(row) || right(row) }, nil
+}
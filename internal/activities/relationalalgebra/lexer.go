@@ -0,0 +1,233 @@
+package relationalalgebra
+
+import (
+	"database-camp/internal/errs"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenNumber
+	tokenString
+
+	tokenSelect
+	tokenProject
+	tokenRename
+	tokenGroup
+	tokenJoin
+	tokenCross
+	tokenUnion
+	tokenMinus
+	tokenIntersect
+
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenComparison
+	tokenArrow
+
+	tokenLeftParen
+	tokenRightParen
+	tokenLeftBracket
+	tokenRightBracket
+	tokenComma
+	tokenSemicolon
+	tokenDot
+	tokenStar
+)
+
+// tokenNames describe the tokens in Thai and English, tokens written the
+// same in both languages describe themselves
+var tokenNames = map[tokenKind][2]string{
+	tokenEOF:          {"จุดสิ้นสุดของนิพจน์", "the end"},
+	tokenIdentifier:   {"ชื่อ", "a name"},
+	tokenNumber:       {"ตัวเลข", "a number"},
+	tokenString:       {"ข้อความ", "a string"},
+	tokenComparison:   {"เครื่องหมายเปรียบเทียบ", "a comparison"},
+	tokenArrow:        {"→", "→"},
+	tokenLeftParen:    {"(", "("},
+	tokenRightParen:   {")", ")"},
+	tokenLeftBracket:  {"[", "["},
+	tokenRightBracket: {"]", "]"},
+	tokenComma:        {",", ","},
+}
+
+// Every operator has a symbol and a keyword, so expressions can be typed on
+// any keyboard
+var operatorSymbols = map[rune]tokenKind{
+	'σ': tokenSelect,
+	'π': tokenProject,
+	'ρ': tokenRename,
+	'γ': tokenGroup,
+	'⋈': tokenJoin,
+	'×': tokenCross,
+	'∪': tokenUnion,
+	'−': tokenMinus,
+	'-': tokenMinus,
+	'∩': tokenIntersect,
+	'∧': tokenAnd,
+	'∨': tokenOr,
+	'¬': tokenNot,
+	'→': tokenArrow,
+	'(': tokenLeftParen,
+	')': tokenRightParen,
+	'[': tokenLeftBracket,
+	']': tokenRightBracket,
+	',': tokenComma,
+	';': tokenSemicolon,
+	'.': tokenDot,
+	'*': tokenStar,
+}
+
+var operatorKeywords = map[string]tokenKind{
+	"select":    tokenSelect,
+	"project":   tokenProject,
+	"rename":    tokenRename,
+	"group":     tokenGroup,
+	"join":      tokenJoin,
+	"cross":     tokenCross,
+	"union":     tokenUnion,
+	"minus":     tokenMinus,
+	"except":    tokenMinus,
+	"intersect": tokenIntersect,
+	"and":       tokenAnd,
+	"or":        tokenOr,
+	"not":       tokenNot,
+}
+
+var comparisons = map[string]string{
+	"=":  "=",
+	"<>": "<>",
+	"!=": "<>",
+	"≠":  "<>",
+	"<":  "<",
+	"<=": "<=",
+	"≤":  "<=",
+	">":  ">",
+	">=": ">=",
+	"≥":  ">=",
+}
+
+type token struct {
+	kind     tokenKind
+	text     string
+	position int
+}
+
+// describe tells what was found, in Thai and English
+func (t token) describe() [2]string {
+	switch t.kind {
+	case tokenEOF:
+		return tokenNames[tokenEOF]
+	case tokenString:
+		return [2]string{"'" + t.text + "'", "'" + t.text + "'"}
+	default:
+		return [2]string{t.text, t.text}
+	}
+}
+
+func newUnexpectedError(t token) error {
+	found := t.describe()
+
+	return errs.NewBadRequestError(
+		fmt.Sprintf(errs.EXPRESSION_UNEXPECTED_TH, found[0], t.position),
+		fmt.Sprintf(errs.EXPRESSION_UNEXPECTED_EN, found[1], t.position),
+	)
+}
+
+func newExpectedError(kind tokenKind, t token) error {
+	expected, found := tokenNames[kind], t.describe()
+
+	return errs.NewBadRequestError(
+		fmt.Sprintf(errs.EXPRESSION_EXPECTED_TH, expected[0], t.position, found[0]),
+		fmt.Sprintf(errs.EXPRESSION_EXPECTED_EN, expected[1], t.position, found[1]),
+	)
+}
+
+// tokenize splits the expression, positions count characters from 1
+func tokenize(expression string) ([]token, error) {
+	runes := []rune(expression)
+	tokens := make([]token, 0)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+
+		case r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != '\'' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, newUnexpectedError(token{kind: tokenEOF, position: end + 1})
+			}
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[i+1 : end]), position: start + 1})
+			i = end + 1
+			continue
+
+		case unicode.IsDigit(r):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), position: start + 1})
+			continue
+
+		case isIdentifierRune(r, true):
+			for i < len(runes) && isIdentifierRune(runes[i], false) {
+				i++
+			}
+			text := string(runes[start:i])
+			kind := tokenIdentifier
+			if keyword, ok := operatorKeywords[strings.ToLower(text)]; ok {
+				kind = keyword
+			}
+			tokens = append(tokens, token{kind: kind, text: text, position: start + 1})
+			continue
+		}
+
+		if i+1 < len(runes) {
+			pair := string(runes[i : i+2])
+			if comparison, ok := comparisons[pair]; ok {
+				tokens = append(tokens, token{kind: tokenComparison, text: comparison, position: start + 1})
+				i += 2
+				continue
+			} else if pair == "->" {
+				tokens = append(tokens, token{kind: tokenArrow, text: "→", position: start + 1})
+				i += 2
+				continue
+			}
+		}
+
+		if comparison, ok := comparisons[string(r)]; ok {
+			tokens = append(tokens, token{kind: tokenComparison, text: comparison, position: start + 1})
+		} else if kind, ok := operatorSymbols[r]; ok {
+			tokens = append(tokens, token{kind: kind, text: string(r), position: start + 1})
+		} else {
+			return nil, newUnexpectedError(token{kind: tokenIdentifier, text: string(r), position: start + 1})
+		}
+		i++
+	}
+
+	return append(tokens, token{kind: tokenEOF, position: len(runes) + 1}), nil
+}
+
+// The Greek letters of the operators never belong to a name
+func isIdentifierRune(r rune, first bool) bool {
+	if _, ok := operatorSymbols[r]; ok {
+		return false
+	}
+	if r == '_' || unicode.IsLetter(r) {
+		return true
+	}
+	return !first && (unicode.IsDigit(r) || unicode.Is(unicode.Mn, r))
+}
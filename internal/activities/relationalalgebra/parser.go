@@ -0,0 +1,476 @@
+package relationalalgebra
+
+import (
+	"database-camp/internal/errs"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MAX_EXPRESSION_LENGTH is the most characters an expression may have
+	MAX_EXPRESSION_LENGTH = 2000
+
+	// MAX_EXPRESSION_DEPTH is how deep expressions and conditions may nest,
+	// the parser recurses once per level
+	MAX_EXPRESSION_DEPTH = 64
+)
+
+// The plain-text syntax, every operator may be written with its symbol or
+// its keyword:
+//
+//	expression := term { (∪ | − | ∩) term }
+//	term       := unary { (⋈ [ '[' condition ']' ] | ×) unary }
+//	unary      := σ '[' condition ']' '(' expression ')'
+//	            | π '[' attribute { ',' attribute } ']' '(' expression ')'
+//	            | ρ '[' name [ '(' name { ',' name } ')' ] ']' '(' expression ')'
+//	            | γ '[' item { (',' | ';') item } ']' '(' expression ')'
+//	            | '(' expression ')' | name
+//	item       := attribute | function '(' ('*' | attribute) ')' [ → name ]
+//	attribute  := name [ '.' name ]
+//	condition  := conjunction { ∨ conjunction }
+//	conjunction := negation { ∧ negation }
+//	negation   := ¬ negation | '(' condition ')' | operand comparison operand
+//	operand    := attribute | number | '-' number | 'text'
+//
+// e.g. π[name](σ[credit >= 3](Course) ⋈ Enroll)
+
+var aggregateFunctions = map[string]bool{
+	"count": true,
+	"sum":   true,
+	"avg":   true,
+	"min":   true,
+	"max":   true,
+}
+
+type parser struct {
+	tokens []token
+	next   int
+	depth  int
+}
+
+/**
+ * Parse a relational algebra expression
+ *
+ * @param text 	expression in the plain-text syntax
+ *
+ * @return the expression and an AppError telling where the syntax is wrong
+ */
+func parse(text string) (expression, error) {
+	if utf8.RuneCountInString(text) > MAX_EXPRESSION_LENGTH {
+		return nil, errs.ErrExpressionTooLong
+	}
+
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser{tokens: tokens}
+
+	e, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().kind != tokenEOF {
+		return nil, newUnexpectedError(p.peek())
+	}
+
+	return e, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEOF {
+		p.next++
+	}
+	return t
+}
+
+func (p *parser) expect(kind tokenKind) (token, error) {
+	t := p.peek()
+	if t.kind != kind {
+		return t, newExpectedError(kind, t)
+	}
+	return p.advance(), nil
+}
+
+// enter counts a level of nesting, the caller leaves it with the returned func
+func (p *parser) enter() (func(), error) {
+	if p.depth >= MAX_EXPRESSION_DEPTH {
+		return nil, errs.ErrExpressionTooDeep
+	}
+	p.depth++
+
+	return func() { p.depth-- }, nil
+}
+
+func (p *parser) expression() (expression, error) {
+	leave, err := p.enter()
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		operator := p.peek()
+		if operator.kind != tokenUnion && operator.kind != tokenMinus && operator.kind != tokenIntersect {
+			return left, nil
+		}
+		p.advance()
+
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+
+		left = setOperation{operator: operator.kind, symbol: operator.text, left: left, right: right}
+	}
+}
+
+func (p *parser) term() (expression, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		operator := p.peek()
+		if operator.kind != tokenJoin && operator.kind != tokenCross {
+			return left, nil
+		}
+		p.advance()
+
+		e := join{left: left, natural: operator.kind == tokenJoin}
+
+		if operator.kind == tokenJoin && p.peek().kind == tokenLeftBracket {
+			p.advance()
+			if e.condition, err = p.condition(); err != nil {
+				return nil, err
+			}
+			if _, err = p.expect(tokenRightBracket); err != nil {
+				return nil, err
+			}
+			e.natural = false
+		}
+
+		if e.right, err = p.unary(); err != nil {
+			return nil, err
+		}
+
+		left = e
+	}
+}
+
+func (p *parser) unary() (expression, error) {
+	t := p.advance()
+
+	switch t.kind {
+	case tokenIdentifier:
+		return relationName{name: t.text}, nil
+
+	case tokenLeftParen:
+		e, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.expect(tokenRightParen)
+		return e, err
+
+	case tokenSelect:
+		if _, err := p.expect(tokenLeftBracket); err != nil {
+			return nil, err
+		}
+		c, err := p.condition()
+		if err != nil {
+			return nil, err
+		}
+		input, err := p.input()
+		return selection{condition: c, input: input}, err
+
+	case tokenProject:
+		if _, err := p.expect(tokenLeftBracket); err != nil {
+			return nil, err
+		}
+		attributes := make([]attribute, 0)
+		for {
+			a, err := p.attribute()
+			if err != nil {
+				return nil, err
+			}
+			attributes = append(attributes, a)
+
+			if p.peek().kind != tokenComma {
+				break
+			}
+			p.advance()
+		}
+		input, err := p.input()
+		return projection{attributes: attributes, input: input}, err
+
+	case tokenRename:
+		if _, err := p.expect(tokenLeftBracket); err != nil {
+			return nil, err
+		}
+		name, err := p.expect(tokenIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		e := rename{name: name.text}
+		if p.peek().kind == tokenLeftParen {
+			p.advance()
+			e.attributes = make([]string, 0)
+			for {
+				attribute, err := p.expect(tokenIdentifier)
+				if err != nil {
+					return nil, err
+				}
+				e.attributes = append(e.attributes, attribute.text)
+
+				if p.peek().kind != tokenComma {
+					break
+				}
+				p.advance()
+			}
+			if _, err = p.expect(tokenRightParen); err != nil {
+				return nil, err
+			}
+		}
+		e.input, err = p.input()
+		return e, err
+
+	case tokenGroup:
+		if _, err := p.expect(tokenLeftBracket); err != nil {
+			return nil, err
+		}
+		e := grouping{groupBy: make([]attribute, 0), aggregates: make([]aggregate, 0)}
+		for p.peek().kind != tokenRightBracket {
+			if err := p.groupItem(&e); err != nil {
+				return nil, err
+			}
+
+			if p.peek().kind != tokenComma && p.peek().kind != tokenSemicolon {
+				break
+			}
+			p.advance()
+		}
+		var err error
+		e.input, err = p.input()
+		return e, err
+
+	default:
+		return nil, newUnexpectedError(t)
+	}
+}
+
+// input reads the closing bracket of an operator and its parenthesized input
+func (p *parser) input() (expression, error) {
+	if _, err := p.expect(tokenRightBracket); err != nil {
+		return nil, err
+	}
+	if _, err := p.expect(tokenLeftParen); err != nil {
+		return nil, err
+	}
+
+	e, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.expect(tokenRightParen)
+	return e, err
+}
+
+func (p *parser) groupItem(e *grouping) error {
+	name := p.peek()
+	if name.kind != tokenIdentifier || p.tokens[p.next+1].kind != tokenLeftParen {
+		a, err := p.attribute()
+		if err != nil {
+			return err
+		}
+		e.groupBy = append(e.groupBy, a)
+		return nil
+	}
+
+	function := strings.ToLower(name.text)
+	if !aggregateFunctions[function] {
+		return newUnexpectedError(name)
+	}
+	p.advance()
+	p.advance()
+
+	a := aggregate{function: function}
+	if p.peek().kind == tokenStar && function == "count" {
+		p.advance()
+	} else {
+		attribute, err := p.attribute()
+		if err != nil {
+			return err
+		}
+		a.attribute = &attribute
+	}
+
+	if _, err := p.expect(tokenRightParen); err != nil {
+		return err
+	}
+
+	if p.peek().kind == tokenArrow {
+		p.advance()
+		alias, err := p.expect(tokenIdentifier)
+		if err != nil {
+			return err
+		}
+		a.alias = alias.text
+	}
+
+	e.aggregates = append(e.aggregates, a)
+	return nil
+}
+
+func (p *parser) attribute() (attribute, error) {
+	name, err := p.expect(tokenIdentifier)
+	if err != nil {
+		return attribute{}, err
+	}
+
+	if p.peek().kind != tokenDot {
+		return attribute{name: name.text}, nil
+	}
+	p.advance()
+
+	qualified, err := p.expect(tokenIdentifier)
+	if err != nil {
+		return attribute{}, err
+	}
+
+	return attribute{qualifier: name.text, name: qualified.text}, nil
+}
+
+func (p *parser) condition() (condition, error) {
+	left, err := p.conjunction()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOr {
+		p.advance()
+
+		right, err := p.conjunction()
+		if err != nil {
+			return nil, err
+		}
+
+		left = logical{operator: tokenOr, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) conjunction() (condition, error) {
+	left, err := p.negation()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenAnd {
+		p.advance()
+
+		right, err := p.negation()
+		if err != nil {
+			return nil, err
+		}
+
+		left = logical{operator: tokenAnd, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) negation() (condition, error) {
+	leave, err := p.enter()
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	switch p.peek().kind {
+	case tokenNot:
+		p.advance()
+		c, err := p.negation()
+		if err != nil {
+			return nil, err
+		}
+		return logical{operator: tokenNot, left: c}, nil
+
+	case tokenLeftParen:
+		p.advance()
+		c, err := p.condition()
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.expect(tokenRightParen)
+		return c, err
+	}
+
+	left, err := p.comparisonOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	operator, err := p.expect(tokenComparison)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := p.comparisonOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	return comparison{left: left, operator: operator.text, right: right}, nil
+}
+
+func (p *parser) comparisonOperand() (operand, error) {
+	t := p.peek()
+
+	switch t.kind {
+	case tokenIdentifier:
+		a, err := p.attribute()
+		return operand{attribute: &a}, err
+
+	case tokenString:
+		p.advance()
+		return operand{literal: value{text: t.text}}, nil
+
+	case tokenNumber, tokenMinus:
+		p.advance()
+		sign := 1.0
+		if t.kind == tokenMinus {
+			sign = -1
+
+			var err error
+			if t, err = p.expect(tokenNumber); err != nil {
+				return operand{}, err
+			}
+		}
+
+		number, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return operand{}, newUnexpectedError(t)
+		}
+		return operand{literal: value{isNumber: true, number: sign * number}}, nil
+
+	default:
+		return operand{}, newUnexpectedError(t)
+	}
+}
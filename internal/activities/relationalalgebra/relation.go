@@ -0,0 +1,162 @@
+package relationalalgebra
+
+import (
+	"database-camp/internal/errs"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MAX_ROWS bounds every relation an expression computes and MAX_JOIN_PAIRS
+// the rows a join goes through, a few cross products are enough to exhaust
+// the server otherwise
+const (
+	MAX_ROWS       = 10000
+	MAX_JOIN_PAIRS = 1000000
+)
+
+// value is a number, a text or null
+type value struct {
+	null     bool
+	isNumber bool
+	number   float64
+	text     string
+}
+
+func newValue(v interface{}) (value, bool) {
+	switch v := v.(type) {
+	case nil:
+		return value{null: true}, true
+	case float64:
+		return value{isNumber: true, number: v}, true
+	case int:
+		return value{isNumber: true, number: float64(v)}, true
+	case string:
+		return value{text: v}, true
+	default:
+		return value{}, false
+	}
+}
+
+func (v value) String() string {
+	switch {
+	case v.null:
+		return "NULL"
+	case v.isNumber && v.number == math.Trunc(v.number) && math.Abs(v.number) < 1e15:
+		return strconv.FormatInt(int64(v.number), 10)
+	case v.isNumber:
+		return strconv.FormatFloat(v.number, 'g', -1, 64)
+	default:
+		return v.text
+	}
+}
+
+// compare orders numbers by value and anything else by text, null compares
+// with nothing
+func (v value) compare(other value) (int, bool) {
+	if v.null || other.null {
+		return 0, false
+	}
+
+	if v.isNumber && other.isNumber {
+		switch {
+		case v.number < other.number:
+			return -1, true
+		case v.number > other.number:
+			return 1, true
+		default:
+			return 0, true
+		}
+	}
+
+	return strings.Compare(v.String(), other.String()), true
+}
+
+type attribute struct {
+	qualifier string
+	name      string
+}
+
+func (a attribute) String() string {
+	if a.qualifier == "" {
+		return a.name
+	}
+	return a.qualifier + "." + a.name
+}
+
+// relation is a set of rows, adding a row it already has does nothing
+type relation struct {
+	attributes []attribute
+	rows       [][]value
+	keys       map[string]bool
+}
+
+func newRelation(attributes []attribute) *relation {
+	return &relation{
+		attributes: attributes,
+		rows:       make([][]value, 0),
+		keys:       map[string]bool{},
+	}
+}
+
+func rowKey(row []value) string {
+	parts := make([]string, len(row))
+	for i, v := range row {
+		if v.null {
+			parts[i] = "\x01"
+		} else {
+			parts[i] = v.String()
+		}
+	}
+	return strings.Join(parts, "\x00")
+}
+
+func (r *relation) add(row []value) error {
+	key := rowKey(row)
+	if r.keys[key] {
+		return nil
+	}
+
+	if len(r.rows) == MAX_ROWS {
+		return errs.ErrExpressionTooLarge
+	}
+
+	r.keys[key] = true
+	r.rows = append(r.rows, row)
+
+	return nil
+}
+
+func (r *relation) has(row []value) bool {
+	return r.keys[rowKey(row)]
+}
+
+// resolve finds the attribute a name refers to, the qualifier may be left
+// out when the name is unique
+func (r *relation) resolve(ref attribute) (int, error) {
+	found := -1
+
+	for i, a := range r.attributes {
+		if a.name != ref.name || (ref.qualifier != "" && a.qualifier != ref.qualifier) {
+			continue
+		}
+
+		if found >= 0 {
+			return 0, errs.NewBadRequestError(
+				fmt.Sprintf(errs.EXPRESSION_ATTRIBUTE_AMBIGUOUS_TH, ref),
+				fmt.Sprintf(errs.EXPRESSION_ATTRIBUTE_AMBIGUOUS_EN, ref),
+			)
+		}
+		found = i
+	}
+
+	if found < 0 {
+		return 0, errs.NewBadRequestError(
+			fmt.Sprintf(errs.EXPRESSION_ATTRIBUTE_NOT_FOUND_TH, ref),
+			fmt.Sprintf(errs.EXPRESSION_ATTRIBUTE_NOT_FOUND_EN, ref),
+		)
+	}
+
+	return found, nil
+}
@@ -0,0 +1,297 @@
+package relationalalgebra
+
+// relationalalgebra is the relational algebra exercise, the learner writes an
+// expression over the sample relations of the activity and it is graded by
+// comparing its result with the result of the reference expression.
+
+import (
+	"database-camp/internal/activities/sqlquery"
+	"database-camp/internal/errs"
+	"database-camp/internal/infrastructure/cache"
+	"database-camp/internal/infrastructure/database"
+	"database-camp/internal/logs"
+	"database-camp/internal/models/entities/activity"
+	"database-camp/internal/repositories"
+	"database-camp/internal/utils"
+	"encoding/json"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	TYPE_ID    = 10
+	TABLE_NAME = "RelationalAlgebraChoice"
+)
+
+// Relation is a sample relation, the values are numbers, texts or null
+type Relation struct {
+	Name       string          `json:"name"`
+	Attributes []string        `json:"attributes"`
+	Rows       [][]interface{} `json:"rows"`
+}
+
+// Choice is the sample relations and the reference expression of an activity,
+// the relations are stored as JSON
+type Choice struct {
+	ActivityID          int        `gorm:"primaryKey;column:activity_id" json:"-"`
+	RelationList        string     `gorm:"column:relations" json:"-"`
+	Relations           []Relation `gorm:"-" json:"relations"`
+	ReferenceExpression string     `gorm:"column:reference_expression" json:"reference_expression"`
+}
+
+// CreatePropositionChoices shows the sample relations, the reference
+// expression stays on the server
+func (choice Choice) CreatePropositionChoices() interface{} {
+	return map[string]interface{}{
+		"relations": choice.Relations,
+	}
+}
+
+func (choice Choice) environment() (environment, error) {
+	env := environment{}
+
+	for _, r := range choice.Relations {
+		attributes := make([]attribute, len(r.Attributes))
+		for i, name := range r.Attributes {
+			attributes[i] = attribute{qualifier: r.Name, name: name}
+		}
+
+		_relation := newRelation(attributes)
+		for _, row := range r.Rows {
+			if len(row) != len(attributes) {
+				return nil, errs.ErrChoicesInvalid
+			}
+
+			values := make([]value, len(row))
+			for i, v := range row {
+				var ok bool
+				if values[i], ok = newValue(v); !ok {
+					return nil, errs.ErrChoicesInvalid
+				}
+			}
+
+			if err := _relation.add(values); err != nil {
+				return nil, err
+			}
+		}
+
+		env[r.Name] = _relation
+	}
+
+	return env, nil
+}
+
+/**
+ * Evaluate an expression over the sample relations
+ *
+ * @param text 	expression in the plain-text syntax
+ *
+ * @return the result and an AppError in Thai and English if the expression is
+ * wrong
+ */
+func (choice Choice) Evaluate(text string) (*sqlquery.Result, error) {
+	env, err := choice.environment()
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	_relation, err := e.evaluate(env)
+	if err != nil {
+		return nil, err
+	}
+
+	result := sqlquery.Result{
+		Columns: make([]string, len(_relation.attributes)),
+		Rows:    make([][]string, 0, len(_relation.rows)),
+	}
+
+	for i, a := range _relation.attributes {
+		result.Columns[i] = a.name
+	}
+
+	for _, row := range _relation.rows {
+		formatted := make([]string, len(row))
+		for i, v := range row {
+			formatted[i] = v.String()
+		}
+		result.Rows = append(result.Rows, formatted)
+	}
+
+	return &result, nil
+}
+
+// Answer is the expression the learner wrote
+type Answer struct {
+	Expression string `json:"expression"`
+}
+
+/**
+ * Evaluate the expression of the learner and the reference expression and
+ * compare their results as sets of rows
+ *
+ * @param choices 	choice of the activity
+ *
+ * @return the grade with the rows that differ or with what is wrong with
+ * the expression of the learner
+ */
+func (answer Answer) Grade(choices activity.Choices) (*activity.Grade, error) {
+	choice, ok := choices.(Choice)
+	if !ok {
+		return nil, errs.ErrAnswerInvalid
+	}
+
+	expected, err := choice.Evaluate(choice.ReferenceExpression)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrReferenceExpressionInvalid
+	}
+
+	// A wrong expression is a wrong answer, an error would fail the whole
+	// exam it is part of
+	actual, err := choice.Evaluate(answer.Expression)
+	if err != nil {
+		message := err.Error()
+		if appErr, ok := err.(errs.AppError); ok {
+			message = appErr.ThMessage
+		}
+
+		return &activity.Grade{IsCorrect: false, Message: &message}, nil
+	}
+
+	feedback := sqlquery.Compare(*expected, alignColumns(*expected, *actual), false)
+	if feedback == nil {
+		return &activity.Grade{IsCorrect: true, Score: 1}, nil
+	}
+
+	message := feedback.Message()
+
+	return &activity.Grade{IsCorrect: false, Message: &message, Feedback: feedback}, nil
+}
+
+// alignColumns orders the columns of the result like the expected ones when
+// both have the same attribute names, attributes are a set in the algebra
+func alignColumns(expected sqlquery.Result, actual sqlquery.Result) sqlquery.Result {
+	if len(expected.Columns) != len(actual.Columns) {
+		return actual
+	}
+
+	index := map[string]int{}
+	for i, column := range actual.Columns {
+		index[column] = i
+	}
+
+	order := make([]int, len(expected.Columns))
+	used := map[int]bool{}
+	for i, column := range expected.Columns {
+		j, ok := index[column]
+		if !ok || used[j] {
+			return actual
+		}
+		order[i] = j
+		used[j] = true
+	}
+
+	aligned := sqlquery.Result{Columns: expected.Columns, Rows: make([][]string, 0, len(actual.Rows))}
+	for _, row := range actual.Rows {
+		alignedRow := make([]string, len(order))
+		for i, j := range order {
+			alignedRow[i] = row[j]
+		}
+		aligned.Rows = append(aligned.Rows, alignedRow)
+	}
+
+	return aligned
+}
+
+func parseChoices(choices interface{}) (interface{}, error) {
+	var choice Choice
+	if err := utils.StructToStruct(choices, &choice); err != nil {
+		return nil, errs.ErrChoicesInvalid
+	}
+
+	if len(choice.Relations) == 0 || strings.TrimSpace(choice.ReferenceExpression) == "" {
+		return nil, errs.ErrChoicesInvalid
+	}
+
+	names := map[string]bool{}
+	for _, r := range choice.Relations {
+		if !isName(r.Name) || names[r.Name] || len(r.Attributes) == 0 {
+			return nil, errs.ErrChoicesInvalid
+		}
+		names[r.Name] = true
+
+		attributes := map[string]bool{}
+		for _, name := range r.Attributes {
+			if !isName(name) || attributes[name] {
+				return nil, errs.ErrChoicesInvalid
+			}
+			attributes[name] = true
+		}
+	}
+
+	if _, err := choice.Evaluate(choice.ReferenceExpression); err != nil {
+		return nil, err
+	}
+
+	return choice, nil
+}
+
+// isName reports whether expressions can refer to the name
+func isName(name string) bool {
+	tokens, err := tokenize(name)
+	return err == nil && len(tokens) == 2 && tokens[0].kind == tokenIdentifier
+}
+
+func init() {
+	activity.RegisterType(activity.ActivityType{
+		ID:   TYPE_ID,
+		Name: "relational_algebra",
+		DecodeAnswer: func(answer interface{}) (activity.Answer, error) {
+			var relationalAlgebraAnswer Answer
+			err := utils.StructToStruct(answer, &relationalAlgebraAnswer)
+			return relationalAlgebraAnswer, err
+		},
+		ParseChoices: parseChoices,
+	})
+
+	repositories.RegisterChoiceLoader(TYPE_ID, func(db database.MysqlDB, cache cache.Cache, activityID int) (activity.Choices, error) {
+		var choice Choice
+
+		err := db.GetDB().
+			Table(TABLE_NAME).
+			Where("activity_id = ?", activityID).
+			Take(&choice).
+			Error
+		if err != nil {
+			return choice, err
+		}
+
+		err = json.Unmarshal([]byte(choice.RelationList), &choice.Relations)
+
+		return choice, err
+	})
+
+	repositories.RegisterChoiceWriter(TYPE_ID, repositories.ChoiceWriter{
+		Insert: func(tx *gorm.DB, activityID int, choices interface{}) error {
+			choice := choices.(Choice)
+			choice.ActivityID = activityID
+
+			relations, err := json.Marshal(choice.Relations)
+			if err != nil {
+				return err
+			}
+			choice.RelationList = string(relations)
+
+			return tx.Table(TABLE_NAME).Create(&choice).Error
+		},
+		Delete: func(tx *gorm.DB, activityID int) error {
+			return tx.Table(TABLE_NAME).Where("activity_id = ?", activityID).Delete(&Choice{}).Error
+		},
+	})
+}
@@ -0,0 +1,170 @@
+package relationalalgebra
+
+import (
+	"database-camp/internal/errs"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var testChoice = Choice{
+	Relations: []Relation{
+		{
+			Name:       "Student",
+			Attributes: []string{"id", "name"},
+			Rows:       [][]interface{}{{1, "Ann"}, {2, "Bob"}, {3, "Cat"}},
+		},
+		{
+			Name:       "Enroll",
+			Attributes: []string{"id", "course"},
+			Rows:       [][]interface{}{{1, "DB"}, {1, "OS"}, {2, "DB"}},
+		},
+		{
+			Name:       "Course",
+			Attributes: []string{"course", "credit"},
+			Rows:       [][]interface{}{{"DB", 3}, {"OS", 2}},
+		},
+	},
+	ReferenceExpression: "π[name](Student ⋈ Enroll)",
+}
+
+// rows formats the rows of a result in order, a relation is a set
+func rows(t *testing.T, expression string) []string {
+	result, err := testChoice.Evaluate(expression)
+	if err != nil {
+		t.Fatalf("%s: %v", expression, err)
+	}
+
+	formatted := make([]string, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		formatted = append(formatted, strings.Join(row, "|"))
+	}
+	sort.Strings(formatted)
+
+	return formatted
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		want       []string
+	}{
+		{"relation", "Student", []string{"1|Ann", "2|Bob", "3|Cat"}},
+		{"selection", "σ[id >= 2](Student)", []string{"2|Bob", "3|Cat"}},
+		{"selection keyword", "select[name = 'Ann'](Student)", []string{"1|Ann"}},
+		{"projection", "π[course](Enroll)", []string{"DB", "OS"}},
+		{"projection keyword", "project[name](Student)", []string{"Ann", "Bob", "Cat"}},
+		{"natural join", "π[name](Student ⋈ Enroll)", []string{"Ann", "Bob"}},
+		{"theta join", "π[name, credit](Student ⋈ Enroll ⋈[Enroll.course = Course.course] Course)", []string{"Ann|2", "Ann|3", "Bob|3"}},
+		{"cross", "π[name, credit](Student × Course)", []string{"Ann|2", "Ann|3", "Bob|2", "Bob|3", "Cat|2", "Cat|3"}},
+		{"union", "π[id](Enroll) ∪ π[id](Student)", []string{"1", "2", "3"}},
+		{"difference", "π[id](Student) − π[id](Enroll)", []string{"3"}},
+		{"difference keyword", "project[id](Student) minus project[id](Enroll)", []string{"3"}},
+		{"intersection", "π[id](Student) ∩ π[id](Enroll)", []string{"1", "2"}},
+		{"rename", "π[sname](ρ[S(sid, sname)](Student))", []string{"Ann", "Bob", "Cat"}},
+		{"grouping", "γ[id; count(*) → n](Enroll)", []string{"1|2", "2|1"}},
+		{"negation", "σ[¬ id = 1](Student)", []string{"2|Bob", "3|Cat"}},
+		{"negative number", "σ[id > -1 ∧ id < 2](Student)", []string{"1|Ann"}},
+		{"nested parentheses", strings.Repeat("(", 30) + "Student" + strings.Repeat(")", 30), []string{"1|Ann", "2|Bob", "3|Cat"}},
+
+		// ∧ binds tighter than ∨
+		{"condition precedence", "σ[id = 3 ∨ id = 2 ∧ name = 'Ann'](Student)", []string{"3|Cat"}},
+		{"condition parentheses", "σ[(id = 3 ∨ id = 2) ∧ name = 'Bob'](Student)", []string{"2|Bob"}},
+
+		// Set operations are left associative
+		{"set operation associativity", "π[id](Student) − π[id](Enroll) ∪ π[id](Enroll)", []string{"1", "2", "3"}},
+
+		// Joins bind tighter than set operations
+		{"join precedence", "π[id](Student) − π[id](Student) ⋈ π[id](Enroll)", []string{"3"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := rows(t, test.expression)
+			if strings.Join(got, ",") != strings.Join(test.want, ",") {
+				t.Errorf("%s = %v, want %v", test.expression, got, test.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateMalformed(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		want       error
+	}{
+		{"empty", "", nil},
+		{"missing operand", "σ[id >](Student)", nil},
+		{"missing parenthesis", "π[name](Student", nil},
+		{"missing bracket", "π[name(Student)", nil},
+		{"dangling operator", "Student ∪", nil},
+		{"unknown relation", "Teacher", nil},
+		{"unknown attribute", "π[age](Student)", nil},
+		{"not union compatible", "Student ∪ Course ⋈ Enroll", nil},
+		{"unknown aggregate", "γ[id; median(id)](Enroll)", nil},
+		{"too long", "Student" + strings.Repeat(" ", MAX_EXPRESSION_LENGTH), errs.ErrExpressionTooLong},
+		{"too deep", strings.Repeat("(", MAX_EXPRESSION_DEPTH+1) + "Student" + strings.Repeat(")", MAX_EXPRESSION_DEPTH+1), errs.ErrExpressionTooDeep},
+		{"negation too deep", "σ[" + strings.Repeat("¬", MAX_EXPRESSION_DEPTH+1) + "id = 1](Student)", errs.ErrExpressionTooDeep},
+		{"unclosed too deep", strings.Repeat("(", MAX_EXPRESSION_LENGTH), errs.ErrExpressionTooDeep},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := testChoice.Evaluate(test.expression)
+			if err == nil {
+				t.Fatalf("%q is evaluated", test.expression)
+			}
+
+			if _, ok := err.(errs.AppError); !ok {
+				t.Errorf("%q: %v is not an AppError", test.expression, err)
+			}
+
+			if test.want != nil && err != test.want {
+				t.Errorf("%q: %v, want %v", test.expression, err, test.want)
+			}
+		})
+	}
+}
+
+func TestGrade(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		isCorrect  bool
+		hasMessage bool
+	}{
+		{"correct", "π[name](Student ⋈ Enroll)", true, false},
+		{"correct another way", "π[name](σ[Student.id = Enroll.id](Student × Enroll))", true, false},
+		{"wrong rows", "π[name](Student)", false, true},
+		{"malformed", "π[name](Student", false, true},
+		{"too deep", strings.Repeat("(", 100000), false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			grade, err := Answer{Expression: test.expression}.Grade(testChoice)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if grade.IsCorrect != test.isCorrect {
+				t.Errorf("IsCorrect = %v, want %v", grade.IsCorrect, test.isCorrect)
+			}
+			if (grade.Message != nil) != test.hasMessage {
+				t.Errorf("Message = %v, want one: %v", grade.Message, test.hasMessage)
+			}
+		})
+	}
+}
+
+func TestGradeReferenceInvalid(t *testing.T) {
+	choice := testChoice
+	choice.ReferenceExpression = "π[name](Student"
+
+	_, err := Answer{Expression: "Student"}.Grade(choice)
+	if err != errs.ErrReferenceExpressionInvalid {
+		t.Errorf("err = %v, want %v", err, errs.ErrReferenceExpressionInvalid)
+	}
+}
@@ -0,0 +1,148 @@
+package sqlquery
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Feedback tells the learner how the result of their query differs from the
+// result of the reference query
+type Feedback struct {
+	Columns        *ColumnsFeedback `json:"columns,omitempty"`
+	MissingRows    [][]string       `json:"missing_rows"`
+	UnexpectedRows [][]string       `json:"unexpected_rows"`
+	WrongOrder     bool             `json:"wrong_order"`
+}
+
+type ColumnsFeedback struct {
+	Expected int `json:"expected"`
+	Actual   int `json:"actual"`
+}
+
+// formatValue turns a value scanned from SQLite into text, an integral number
+// is the same whether SQLite stored it as INTEGER or REAL
+func formatValue(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "NULL"
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case float64:
+		if value == math.Trunc(value) && math.Abs(value) < 1e15 {
+			return strconv.FormatInt(int64(value), 10)
+		}
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	case []byte:
+		return string(value)
+	case string:
+		return value
+	case bool:
+		if value {
+			return "1"
+		}
+		return "0"
+	case time.Time:
+		return value.Format("2006-01-02 15:04:05")
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+/**
+ * Compare the result of a learner query with the result of the reference
+ * query, column names are not compared so aliases do not matter
+ *
+ * @param expected 			result of the reference query
+ * @param actual 			result of the learner query
+ * @param orderSensitive 	whether the rows must come in the same order
+ *
+ * @return nil if the results are the same, otherwise how they differ
+ */
+func Compare(expected Result, actual Result, orderSensitive bool) *Feedback {
+	if len(expected.Columns) != len(actual.Columns) {
+		return &Feedback{
+			Columns: &ColumnsFeedback{
+				Expected: len(expected.Columns),
+				Actual:   len(actual.Columns),
+			},
+			MissingRows:    [][]string{},
+			UnexpectedRows: [][]string{},
+		}
+	}
+
+	feedback := Feedback{
+		MissingRows:    [][]string{},
+		UnexpectedRows: [][]string{},
+	}
+
+	// Count the expected rows, then take away the rows the learner returned
+	counts := map[string]int{}
+	for _, row := range expected.Rows {
+		counts[rowKey(row)]++
+	}
+
+	for _, row := range actual.Rows {
+		key := rowKey(row)
+		if counts[key] > 0 {
+			counts[key]--
+		} else {
+			feedback.UnexpectedRows = append(feedback.UnexpectedRows, row)
+		}
+	}
+
+	for _, row := range expected.Rows {
+		key := rowKey(row)
+		if counts[key] > 0 {
+			counts[key]--
+			feedback.MissingRows = append(feedback.MissingRows, row)
+		}
+	}
+
+	if len(feedback.MissingRows) == 0 && len(feedback.UnexpectedRows) == 0 && orderSensitive {
+		for i := range expected.Rows {
+			if rowKey(expected.Rows[i]) != rowKey(actual.Rows[i]) {
+				feedback.WrongOrder = true
+				break
+			}
+		}
+	}
+
+	if len(feedback.MissingRows) == 0 && len(feedback.UnexpectedRows) == 0 && !feedback.WrongOrder {
+		return nil
+	}
+
+	return &feedback
+}
+
+func rowKey(row []string) string {
+	return strings.Join(row, "\x00")
+}
+
+// Message summarizes the feedback in Thai like the other messages shown to
+// learners, followed by the rows that differ
+func (feedback Feedback) Message() string {
+	if feedback.Columns != nil {
+		return fmt.Sprintf("ผลลัพธ์ควรมี %d คอลัมน์ แต่คำสั่งของคุณได้ %d คอลัมน์", feedback.Columns.Expected, feedback.Columns.Actual)
+	}
+
+	lines := []string{}
+
+	if len(feedback.MissingRows) > 0 || len(feedback.UnexpectedRows) > 0 {
+		lines = append(lines, fmt.Sprintf("ผลลัพธ์ขาดไป %d แถว และเกินมา %d แถว", len(feedback.MissingRows), len(feedback.UnexpectedRows)))
+	} else if feedback.WrongOrder {
+		lines = append(lines, "ผลลัพธ์ถูกต้องแต่ลำดับของแถวไม่ถูกต้อง")
+	}
+
+	for _, row := range feedback.MissingRows {
+		lines = append(lines, "- ("+strings.Join(row, ", ")+")")
+	}
+
+	for _, row := range feedback.UnexpectedRows {
+		lines = append(lines, "+ ("+strings.Join(row, ", ")+")")
+	}
+
+	return strings.Join(lines, "\n")
+}
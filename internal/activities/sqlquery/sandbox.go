@@ -0,0 +1,291 @@
+package sqlquery
+
+import (
+	"context"
+	"database-camp/internal/errs"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"modernc.org/libc"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	// MAX_ROWS is the most rows a query may return
+	MAX_ROWS = 1000
+
+	// MAX_QUERY_LENGTH is the longest query a learner may send
+	MAX_QUERY_LENGTH = 10000
+
+	// MAX_VALUE_LENGTH is the largest string or blob a query may make, in bytes
+	MAX_VALUE_LENGTH = 1000000
+
+	// MAX_HEAP is the most memory SQLite may hold for all sandboxes together,
+	// in bytes, SQLite has a single heap limit for the whole process
+	MAX_HEAP = 256 << 20
+
+	QUERY_TIMEOUT   = 2 * time.Second
+	DATASET_TIMEOUT = 5 * time.Second
+)
+
+// Result is the result set of a query, the values are normalized to text so
+// results of different queries compare the same way whatever their types
+type Result struct {
+	Columns []string   `json:"columns"`
+	Rows    [][]string `json:"rows"`
+}
+
+// sandbox is an in-memory SQLite database holding the dataset of one activity.
+// Every check gets its own sandbox, nothing a query does can outlive it and
+// nothing is shared between learners.
+type sandbox struct {
+	db   *sql.DB
+	conn *sql.Conn
+
+	// The driver interrupts the connection when a context is canceled, even
+	// one whose statement already finished, so the contexts are only canceled
+	// when the sandbox is closed
+	cancels []context.CancelFunc
+}
+
+/**
+ * Create a sandbox loaded with a dataset, the sandbox is read only after the
+ * dataset is loaded
+ *
+ * @param dataset 	SQL script creating and filling the tables
+ *
+ * @return the sandbox and ErrSQLDatasetInvalid if the script fails
+ */
+func newSandbox(dataset string) (*sandbox, error) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+
+	// Every connection to :memory: is a database of its own, the sandbox
+	// keeps its one connection so the dataset and the limits stay with it
+	db.SetMaxOpenConns(1)
+
+	s := &sandbox{db: db}
+
+	s.conn, err = db.Conn(s.context(DATASET_TIMEOUT))
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	if _, err = sqlite.Limit(s.conn, sqlite3.SQLITE_LIMIT_LENGTH, MAX_VALUE_LENGTH); err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	// A dataset may not reach files, ATTACH and VACUUM INTO both attach one
+	if _, err = sqlite.Limit(s.conn, sqlite3.SQLITE_LIMIT_ATTACHED, 0); err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	// Sorts and temporary tables stay in memory, where the heap limit holds
+	if _, err = s.conn.ExecContext(s.context(DATASET_TIMEOUT), "PRAGMA temp_store = MEMORY"); err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	if _, err = s.conn.ExecContext(s.context(DATASET_TIMEOUT), dataset); err != nil {
+		s.Close()
+		return nil, errs.ErrSQLDatasetInvalid
+	}
+
+	if _, err = s.conn.ExecContext(s.context(DATASET_TIMEOUT), "PRAGMA query_only = ON"); err != nil {
+		s.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *sandbox) context(timeout time.Duration) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	s.cancels = append(s.cancels, cancel)
+	return ctx
+}
+
+func (s *sandbox) Close() error {
+	if s.conn != nil {
+		s.conn.Close()
+	}
+
+	err := s.db.Close()
+	for _, cancel := range s.cancels {
+		cancel()
+	}
+	return err
+}
+
+/**
+ * Run a single SELECT statement in the sandbox
+ *
+ * @param query 	statement to run
+ *
+ * @return the result set, an AppError if the statement is not allowed, runs
+ * too long, uses too much memory or returns too many rows, otherwise the
+ * error of SQLite
+ */
+func (s *sandbox) Query(query string) (*Result, error) {
+	query, err := checkSelect(query)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := s.context(QUERY_TIMEOUT)
+
+	rows, err := s.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, timeoutOr(ctx, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	result := Result{Columns: columns, Rows: [][]string{}}
+
+	for rows.Next() {
+		if len(result.Rows) == MAX_ROWS {
+			return nil, errs.ErrSQLQueryTooManyRows
+		}
+
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+
+		if err = rows.Scan(pointers...); err != nil {
+			return nil, timeoutOr(ctx, err)
+		}
+
+		row := make([]string, len(columns))
+		for i, value := range values {
+			row[i] = formatValue(value)
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, timeoutOr(ctx, err)
+	}
+
+	return &result, nil
+}
+
+func timeoutOr(ctx context.Context, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errs.ErrSQLQueryTimeout
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() & 0xff {
+		case sqlite3.SQLITE_NOMEM, sqlite3.SQLITE_TOOBIG:
+			return errs.ErrSQLQueryTooLarge
+		}
+	}
+
+	return err
+}
+
+// checkSelect allows one SELECT (or WITH ... SELECT) statement. The sandbox is
+// read only anyway, this keeps learners from running several statements or
+// PRAGMAs and gives them a clear message when they try.
+func checkSelect(query string) (string, error) {
+	query = strings.TrimSpace(query)
+	if len(query) > MAX_QUERY_LENGTH {
+		return "", errs.ErrSQLQueryNotSelect
+	}
+
+	code := stripLiterals(query)
+	if code == nil {
+		return "", errs.ErrSQLQueryNotSelect
+	}
+
+	// Semicolons closing the statement are fine, any other starts a new one
+	if strings.Contains(strings.TrimRight(*code, "; \t\r\n"), ";") {
+		return "", errs.ErrSQLQueryNotSelect
+	}
+
+	fields := strings.Fields(*code)
+	if len(fields) == 0 {
+		return "", errs.ErrSQLQueryNotSelect
+	}
+
+	switch strings.ToUpper(fields[0]) {
+	case "SELECT", "WITH":
+		return query, nil
+	default:
+		return "", errs.ErrSQLQueryNotSelect
+	}
+}
+
+// stripLiterals replaces quoted strings, quoted identifiers and comments with
+// spaces, nil if one of them is not closed
+func stripLiterals(query string) *string {
+	code := strings.Builder{}
+
+	for i := 0; i < len(query); i++ {
+		switch {
+		case query[i] == '\'' || query[i] == '"' || query[i] == '`':
+			end := strings.IndexByte(query[i+1:], query[i])
+			if end < 0 {
+				return nil
+			}
+			i += end + 1
+			code.WriteByte(' ')
+		case query[i] == '[':
+			end := strings.IndexByte(query[i+1:], ']')
+			if end < 0 {
+				return nil
+			}
+			i += end + 1
+			code.WriteByte(' ')
+		case strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			i += end
+			code.WriteByte(' ')
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return nil
+			}
+			i += end + 3
+			code.WriteByte(' ')
+		default:
+			code.WriteByte(query[i])
+		}
+	}
+
+	stripped := code.String()
+	return &stripped
+}
+
+// The heap limit needs SQLite to count its memory, which this build does not
+// do by default and which can only be turned on before SQLite is initialized
+// by the first sandbox
+func init() {
+	tls := libc.NewTLS()
+	defer tls.Close()
+
+	list := libc.NewVaList(int32(1))
+	defer libc.Xfree(tls, list)
+
+	sqlite3.Xsqlite3_config(tls, sqlite3.SQLITE_CONFIG_MEMSTATUS, list)
+	sqlite3.Xsqlite3_hard_heap_limit64(tls, MAX_HEAP)
+}
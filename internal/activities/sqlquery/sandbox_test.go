@@ -0,0 +1,123 @@
+package sqlquery
+
+import (
+	"database-camp/internal/errs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const testDataset = `
+CREATE TABLE Student (id INTEGER PRIMARY KEY, name TEXT, gpa REAL);
+INSERT INTO Student VALUES (1, 'Ann', 3.5), (2, 'Bob', 2.0), (3, 'Cat', 3.0);
+CREATE TABLE Enroll (id INTEGER, course TEXT);
+INSERT INTO Enroll VALUES (1, 'DB'), (1, 'OS'), (2, 'DB');
+`
+
+func TestCheckSelect(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		allowed bool
+	}{
+		{"select", "SELECT * FROM Student", true},
+		{"lower case", "  select name from Student  ", true},
+		{"with", "WITH s AS (SELECT * FROM Student) SELECT * FROM s", true},
+		{"closing semicolons", "SELECT 1;;", true},
+		{"semicolon in string", "SELECT ';DROP TABLE Student'", true},
+		{"semicolon in identifier", `SELECT 1 AS "a;b"`, true},
+		{"semicolon in comment", "SELECT 1 -- ; DELETE\n", true},
+		{"empty", "", false},
+		{"only a comment", "/* SELECT */", false},
+		{"insert", "INSERT INTO Student VALUES (4, 'Dan', 1.0)", false},
+		{"pragma", "PRAGMA query_only = OFF", false},
+		{"two statements", "SELECT 1; DELETE FROM Student", false},
+		{"comment first", "/* x */ DELETE FROM Student", false},
+		{"unclosed string", "SELECT 'a", false},
+		{"unclosed comment", "SELECT 1 /* a", false},
+		{"too long", "SELECT " + strings.Repeat("1 + ", MAX_QUERY_LENGTH) + "1", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := checkSelect(test.query)
+			if test.allowed && err != nil {
+				t.Errorf("%q is refused: %v", test.query, err)
+			} else if !test.allowed && err != errs.ErrSQLQueryNotSelect {
+				t.Errorf("%q: %v, want %v", test.query, err, errs.ErrSQLQueryNotSelect)
+			}
+		})
+	}
+}
+
+func TestSandboxQuery(t *testing.T) {
+	_sandbox, err := newSandbox(testDataset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer _sandbox.Close()
+
+	result, err := _sandbox.Query("SELECT name, gpa * 2, NULL FROM Student WHERE id = 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(result.Rows[0], "|"); got != "Ann|7|NULL" {
+		t.Errorf("row = %s, want Ann|7|NULL", got)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  error
+	}{
+		{"read only", "WITH x AS (SELECT 1) DELETE FROM Student", nil},
+		{"too many rows", "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c WHERE x < " + strconv.Itoa(MAX_ROWS+1) + ") SELECT x FROM c", errs.ErrSQLQueryTooManyRows},
+		{"too long value", "SELECT length(randomblob(" + strconv.Itoa(MAX_VALUE_LENGTH+1) + "))", errs.ErrSQLQueryTooLarge},
+		{"too much memory", "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c WHERE x < 400) SELECT count(*) FROM (SELECT zeroblob(900000) || x AS b FROM c ORDER BY b)", errs.ErrSQLQueryTooLarge},
+		{"runs too long", "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c) SELECT count(*) FROM c", errs.ErrSQLQueryTimeout},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := _sandbox.Query(test.query)
+			if err == nil {
+				t.Fatalf("%q is run", test.query)
+			}
+			if test.want != nil && err != test.want {
+				t.Errorf("%q: %v, want %v", test.query, err, test.want)
+			}
+		})
+	}
+
+	// A query that failed leaves the sandbox as it was
+	result, err = _sandbox.Query("SELECT count(*) FROM Student")
+	if err != nil || result.Rows[0][0] != "3" {
+		t.Errorf("Student has %v rows: %v", result, err)
+	}
+}
+
+func TestNewSandboxDatasetInvalid(t *testing.T) {
+	if _, err := newSandbox("CREATE TABLE"); err != errs.ErrSQLDatasetInvalid {
+		t.Errorf("err = %v, want %v", err, errs.ErrSQLDatasetInvalid)
+	}
+}
+
+func TestNewSandboxDatasetFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.db")
+
+	datasets := []string{
+		"ATTACH '" + path + "' AS out; CREATE TABLE out.t (a INTEGER)",
+		"CREATE TABLE t (a INTEGER); VACUUM INTO '" + path + "'",
+	}
+
+	for _, dataset := range datasets {
+		if _, err := newSandbox(dataset); err != errs.ErrSQLDatasetInvalid {
+			t.Errorf("%q: err = %v, want %v", dataset, err, errs.ErrSQLDatasetInvalid)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%q: wrote %s", dataset, path)
+		}
+	}
+}
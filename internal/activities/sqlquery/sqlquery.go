@@ -0,0 +1,235 @@
+package sqlquery
+
+// sqlquery is the SQL query exercise, the learner writes a SELECT statement
+// that is run against the sample dataset of the activity in a SQLite sandbox
+// and graded by comparing its result with the result of the reference query.
+
+import (
+	"crypto/sha256"
+	"database-camp/internal/errs"
+	"database-camp/internal/infrastructure/cache"
+	"database-camp/internal/infrastructure/database"
+	"database-camp/internal/logs"
+	"database-camp/internal/models/entities/activity"
+	"database-camp/internal/repositories"
+	"database-camp/internal/utils"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+const (
+	TYPE_ID    = 8
+	TABLE_NAME = "SQLQueryChoice"
+
+	// SAMPLE_ROWS is how many rows of every table are shown with the question
+	SAMPLE_ROWS = 5
+
+	// MAX_CACHED_DATASETS is how many datasets keep their tables in memory
+	MAX_CACHED_DATASETS = 256
+)
+
+// The tables of a dataset are cached by the hash of the dataset, so a changed
+// dataset is a new entry and an entry never goes stale
+var (
+	datasetTables      = map[[sha256.Size]byte][]map[string]interface{}{}
+	datasetTablesMutex sync.RWMutex
+)
+
+// Choice is the dataset and the reference query of an activity
+type Choice struct {
+	ActivityID     int    `gorm:"primaryKey;column:activity_id" json:"-"`
+	Dataset        string `gorm:"column:dataset" json:"dataset"`
+	ReferenceQuery string `gorm:"column:reference_query" json:"reference_query"`
+	OrderSensitive bool   `gorm:"column:order_sensitive" json:"order_sensitive"`
+}
+
+// CreatePropositionChoices shows the learner the tables of the dataset with a
+// few of their rows, the reference query stays on the server
+func (choice Choice) CreatePropositionChoices() interface{} {
+	tables, err := getDatasetTables(choice.Dataset)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		tables = make([]map[string]interface{}, 0)
+	}
+
+	return map[string]interface{}{
+		"tables":          tables,
+		"order_sensitive": choice.OrderSensitive,
+	}
+}
+
+// getDatasetTables runs a dataset only the first time its tables are shown
+func getDatasetTables(dataset string) ([]map[string]interface{}, error) {
+	key := sha256.Sum256([]byte(dataset))
+
+	datasetTablesMutex.RLock()
+	tables, ok := datasetTables[key]
+	datasetTablesMutex.RUnlock()
+	if ok {
+		return tables, nil
+	}
+
+	tables, err := loadDatasetTables(dataset)
+	if err != nil {
+		return nil, err
+	}
+
+	datasetTablesMutex.Lock()
+	if len(datasetTables) >= MAX_CACHED_DATASETS {
+		datasetTables = map[[sha256.Size]byte][]map[string]interface{}{}
+	}
+	datasetTables[key] = tables
+	datasetTablesMutex.Unlock()
+
+	return tables, nil
+}
+
+func loadDatasetTables(dataset string) ([]map[string]interface{}, error) {
+	tables := make([]map[string]interface{}, 0)
+
+	_sandbox, err := newSandbox(dataset)
+	if err != nil {
+		return nil, err
+	}
+	defer _sandbox.Close()
+
+	schema, err := _sandbox.Query("SELECT name, sql FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range schema.Rows {
+		sample, err := _sandbox.Query("SELECT * FROM \"" + strings.ReplaceAll(row[0], "\"", "\"\"") + "\" LIMIT " + strconv.Itoa(SAMPLE_ROWS))
+		if err != nil {
+			logs.GetInstance().Error(err)
+			continue
+		}
+
+		tables = append(tables, map[string]interface{}{
+			"name":    row[0],
+			"sql":     row[1],
+			"columns": sample.Columns,
+			"rows":    sample.Rows,
+		})
+	}
+
+	return tables, nil
+}
+
+// Answer is the query the learner wrote
+type Answer struct {
+	Query string `json:"query"`
+}
+
+/**
+ * Run the query of the learner and the reference query in one sandbox and
+ * compare their results
+ *
+ * @param choices 	choice of the activity
+ *
+ * @return the grade with the rows that differ, a query that fails is a wrong
+ * answer while a dataset or reference query that fails is an error
+ */
+func (answer Answer) Grade(choices activity.Choices) (*activity.Grade, error) {
+	choice, ok := choices.(Choice)
+	if !ok {
+		return nil, errs.ErrAnswerInvalid
+	}
+
+	_sandbox, err := newSandbox(choice.Dataset)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrSQLDatasetInvalid
+	}
+	defer _sandbox.Close()
+
+	expected, err := _sandbox.Query(choice.ReferenceQuery)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrSQLReferenceQueryInvalid
+	}
+
+	actual, err := _sandbox.Query(answer.Query)
+	if err != nil {
+		message := err.Error()
+		if appErr, ok := err.(errs.AppError); ok {
+			message = appErr.ThMessage
+		}
+
+		return &activity.Grade{IsCorrect: false, Message: &message}, nil
+	}
+
+	feedback := Compare(*expected, *actual, choice.OrderSensitive)
+	if feedback == nil {
+		return &activity.Grade{IsCorrect: true, Score: 1}, nil
+	}
+
+	message := feedback.Message()
+
+	return &activity.Grade{IsCorrect: false, Message: &message, Feedback: feedback}, nil
+}
+
+func parseChoices(choices interface{}) (interface{}, error) {
+	var choice Choice
+	if err := utils.StructToStruct(choices, &choice); err != nil {
+		return nil, errs.ErrChoicesInvalid
+	}
+
+	if strings.TrimSpace(choice.Dataset) == "" || strings.TrimSpace(choice.ReferenceQuery) == "" {
+		return nil, errs.ErrChoicesInvalid
+	}
+
+	_sandbox, err := newSandbox(choice.Dataset)
+	if err != nil {
+		return nil, errs.ErrSQLDatasetInvalid
+	}
+	defer _sandbox.Close()
+
+	if _, err = _sandbox.Query(choice.ReferenceQuery); err != nil {
+		return nil, errs.ErrSQLReferenceQueryInvalid
+	}
+
+	return choice, nil
+}
+
+func init() {
+	activity.RegisterType(activity.ActivityType{
+		ID:   TYPE_ID,
+		Name: "sql_query",
+		DecodeAnswer: func(answer interface{}) (activity.Answer, error) {
+			var sqlQueryAnswer Answer
+			err := utils.StructToStruct(answer, &sqlQueryAnswer)
+			return sqlQueryAnswer, err
+		},
+		ParseChoices: parseChoices,
+	})
+
+	// The choices are not cached, the authoring repositories only clear the
+	// cache of the built-in types
+	repositories.RegisterChoiceLoader(TYPE_ID, func(db database.MysqlDB, cache cache.Cache, activityID int) (activity.Choices, error) {
+		var choice Choice
+
+		err := db.GetDB().
+			Table(TABLE_NAME).
+			Where("activity_id = ?", activityID).
+			Take(&choice).
+			Error
+
+		return choice, err
+	})
+
+	repositories.RegisterChoiceWriter(TYPE_ID, repositories.ChoiceWriter{
+		Insert: func(tx *gorm.DB, activityID int, choices interface{}) error {
+			choice := choices.(Choice)
+			choice.ActivityID = activityID
+
+			return tx.Table(TABLE_NAME).Create(&choice).Error
+		},
+		Delete: func(tx *gorm.DB, activityID int) error {
+			return tx.Table(TABLE_NAME).Where("activity_id = ?", activityID).Delete(&Choice{}).Error
+		},
+	})
+}
@@ -0,0 +1,108 @@
+package sqlquery
+
+import (
+	"crypto/sha256"
+	"database-camp/internal/errs"
+	"testing"
+)
+
+var testChoice = Choice{
+	Dataset:        testDataset,
+	ReferenceQuery: "SELECT name FROM Student WHERE gpa >= 3 ORDER BY name",
+}
+
+func TestGrade(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		orderSensitive bool
+		isCorrect      bool
+		wrongOrder     bool
+	}{
+		{"same query", "SELECT name FROM Student WHERE gpa >= 3 ORDER BY name", true, true, false},
+		{"another way", "SELECT s.name AS n FROM Student s WHERE s.gpa > 2.5", false, true, false},
+		{"wrong order ignored", "SELECT name FROM Student WHERE gpa >= 3 ORDER BY name DESC", false, true, false},
+		{"wrong order", "SELECT name FROM Student WHERE gpa >= 3 ORDER BY name DESC", true, false, true},
+		{"missing row", "SELECT name FROM Student WHERE gpa > 3", false, false, false},
+		{"unexpected row", "SELECT name FROM Student", false, false, false},
+		{"duplicated row", "SELECT name FROM Student WHERE gpa >= 3 UNION ALL SELECT 'Ann'", false, false, false},
+		{"wrong columns", "SELECT name, gpa FROM Student WHERE gpa >= 3", false, false, false},
+		{"not select", "DELETE FROM Student", false, false, false},
+		{"syntax error", "SELECT FROM", false, false, false},
+		{"too much memory", "SELECT randomblob(1000000000)", false, false, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			choice := testChoice
+			choice.OrderSensitive = test.orderSensitive
+
+			grade, err := Answer{Query: test.query}.Grade(choice)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if grade.IsCorrect != test.isCorrect {
+				t.Errorf("IsCorrect = %v, want %v", grade.IsCorrect, test.isCorrect)
+			}
+			if !grade.IsCorrect && grade.Message == nil {
+				t.Error("a wrong answer has no message")
+			}
+
+			feedback, _ := grade.Feedback.(*Feedback)
+			if test.wrongOrder != (feedback != nil && feedback.WrongOrder) {
+				t.Errorf("Feedback = %+v, want wrong order %v", grade.Feedback, test.wrongOrder)
+			}
+		})
+	}
+}
+
+func TestGradeChoiceInvalid(t *testing.T) {
+	choice := testChoice
+	choice.ReferenceQuery = "SELECT FROM"
+	if _, err := (Answer{Query: "SELECT 1"}).Grade(choice); err != errs.ErrSQLReferenceQueryInvalid {
+		t.Errorf("err = %v, want %v", err, errs.ErrSQLReferenceQueryInvalid)
+	}
+
+	choice = testChoice
+	choice.Dataset = "CREATE TABLE"
+	if _, err := (Answer{Query: "SELECT 1"}).Grade(choice); err != errs.ErrSQLDatasetInvalid {
+		t.Errorf("err = %v, want %v", err, errs.ErrSQLDatasetInvalid)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	expected := Result{Columns: []string{"a"}, Rows: [][]string{{"1"}, {"2"}, {"2"}}}
+
+	feedback := Compare(expected, Result{Columns: []string{"b"}, Rows: [][]string{{"2"}, {"3"}, {"2"}}}, false)
+	if feedback == nil || len(feedback.MissingRows) != 1 || feedback.MissingRows[0][0] != "1" ||
+		len(feedback.UnexpectedRows) != 1 || feedback.UnexpectedRows[0][0] != "3" {
+		t.Errorf("Feedback = %+v, want 1 missing and 3 unexpected", feedback)
+	}
+
+	if feedback := Compare(expected, Result{Columns: []string{"a"}, Rows: [][]string{{"2"}, {"1"}, {"2"}}}, false); feedback != nil {
+		t.Errorf("Feedback = %+v, want none", feedback)
+	}
+
+	if feedback := Compare(expected, Result{Columns: []string{"a", "b"}, Rows: [][]string{}}, false); feedback == nil || feedback.Columns == nil {
+		t.Errorf("Feedback = %+v, want the columns", feedback)
+	}
+}
+
+func TestCreatePropositionChoices(t *testing.T) {
+	prepared := testChoice.CreatePropositionChoices().(map[string]interface{})
+
+	tables := prepared["tables"].([]map[string]interface{})
+	if len(tables) != 2 || tables[0]["name"] != "Enroll" || tables[1]["name"] != "Student" {
+		t.Fatalf("tables = %v, want Enroll and Student", tables)
+	}
+
+	if _, ok := datasetTables[sha256.Sum256([]byte(testChoice.Dataset))]; !ok {
+		t.Error("tables of the dataset are not cached")
+	}
+
+	again := testChoice.CreatePropositionChoices().(map[string]interface{})
+	if len(again["tables"].([]map[string]interface{})) != 2 {
+		t.Errorf("cached tables = %v", again["tables"])
+	}
+}
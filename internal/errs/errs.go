@@ -5,15 +5,19 @@ package errs
  * 	This file used to manage error of the application
  */
 
-import "net/http"
+import (
+	"net/http"
+	"time"
+)
 
 /**
  * This class represent code and message of the error
  */
 type AppError struct {
-	Code      int    // Code of the error
-	ThMessage string // Message of the error in Thai
-	EnMessage string // Message of the error in English
+	Code       int           // Code of the error
+	ThMessage  string        // Message of the error in Thai
+	EnMessage  string        // Message of the error in English
+	RetryAfter time.Duration // How long the client should wait before retrying, zero if it need not wait
 }
 
 // Implement build in error class
@@ -21,6 +25,22 @@ func (e AppError) Error() string {
 	return e.EnMessage
 }
 
+/**
+ * Attach retry hint to the error
+ *
+ * @param err        error to attach the hint to
+ * @param retryAfter how long the client should wait before retrying
+ *
+ * @return error with the hint, or err itself if it is not an AppError
+ */
+func WithRetryAfter(err error, retryAfter time.Duration) error {
+	if e, ok := err.(AppError); ok {
+		e.RetryAfter = retryAfter
+		return e
+	}
+	return err
+}
+
 /**
  * Create not found error by Thai and English message
  *
@@ -100,3 +120,35 @@ func NewServiceUnavailableError(thMessage string, enMessage string) error {
 		EnMessage: enMessage,
 	}
 }
+
+/**
+ * Create too many requests error by Thai and English message
+ *
+ * @param thMessage error message in Thai
+ * @param enMessage error message in English
+ *
+ * @return error
+ */
+func NewTooManyRequestsError(thMessage string, enMessage string) error {
+	return AppError{
+		Code:      http.StatusTooManyRequests,
+		ThMessage: thMessage,
+		EnMessage: enMessage,
+	}
+}
+
+/**
+ * Create request entity too large error by Thai and English message
+ *
+ * @param thMessage error message in Thai
+ * @param enMessage error message in English
+ *
+ * @return error
+ */
+func NewRequestEntityTooLargeError(thMessage string, enMessage string) error {
+	return AppError{
+		Code:      http.StatusRequestEntityTooLarge,
+		ThMessage: thMessage,
+		EnMessage: enMessage,
+	}
+}
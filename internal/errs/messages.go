@@ -15,6 +15,9 @@ const (
 
 	BAD_REQUEST_ERROR_TH = "คำร้องขอไม่ถูกต้อง"
 	BAD_REQUEST_ERROR_EN = "Bad request"
+
+	REQUEST_BODY_TOO_LARGE_TH = "คำร้องขอมีขนาดใหญ่เกินไป"
+	REQUEST_BODY_TOO_LARGE_EN = "Request body is too large"
 )
 
 // Thai and english message about manipulation error
@@ -87,12 +90,289 @@ const (
 
 	EMAIL_OR_PASSWORD_NOT_CORRECT_TH = "อีเมลหรือรหัสผ่านไม่ถูกต้อง"
 	EMAIL_OR_PASSWORD_NOT_CORRECT_EN = "Email or password not correct"
+
+	PASSWORD_NOT_FOUND_TH = "ไม่พบรหัสผ่านในคำร้องขอ"
+	PASSWORD_NOT_FOUND_EN = "Password Not Found"
+
+	PASSWORD_NOT_CORRECT_TH = "รหัสผ่านเดิมไม่ถูกต้อง"
+	PASSWORD_NOT_CORRECT_EN = "Old password not correct"
+
+	PASSWORD_NOT_CHANGED_TH = "รหัสผ่านใหม่ต้องไม่ซ้ำกับรหัสผ่านเดิม"
+	PASSWORD_NOT_CHANGED_EN = "New password must differ from the old password"
+
+	LOGIN_THROTTLED_TH = "พยายามเข้าสู่ระบบบ่อยเกินไป กรุณารอสักครู่"
+	LOGIN_THROTTLED_EN = "Too many login attempts, please wait"
+
+	ACCOUNT_LOCKED_TH = "บัญชีถูกล็อกชั่วคราว กรุณาตรวจสอบอีเมลเพื่อปลดล็อก"
+	ACCOUNT_LOCKED_EN = "Account is temporarily locked, check your email to unlock it"
+
+	UNLOCK_TOKEN_NOT_FOUND_TH = "ไม่พบโทเค็นสำหรับปลดล็อกบัญชีในคำร้องขอ"
+	UNLOCK_TOKEN_NOT_FOUND_EN = "Unlock token not found"
+
+	UNLOCK_TOKEN_INVALID_TH = "ลิงก์ปลดล็อกบัญชีไม่ถูกต้องหรือหมดอายุ"
+	UNLOCK_TOKEN_INVALID_EN = "Unlock link invalid or expired"
+
+	PASSWORD_RESET_TOKEN_NOT_FOUND_TH = "ไม่พบโทเค็นสำหรับรีเซ็ตรหัสผ่านในคำร้องขอ"
+	PASSWORD_RESET_TOKEN_NOT_FOUND_EN = "Password reset token not found"
+
+	PASSWORD_RESET_TOKEN_INVALID_TH = "ลิงก์รีเซ็ตรหัสผ่านไม่ถูกต้องหรือหมดอายุ"
+	PASSWORD_RESET_TOKEN_INVALID_EN = "Password reset link invalid or expired"
+
+	EMAIL_NOT_VERIFIED_TH = "กรุณายืนยันอีเมลก่อนใช้งาน"
+	EMAIL_NOT_VERIFIED_EN = "Email is not verified"
+
+	EMAIL_ALREADY_VERIFIED_TH = "อีเมลได้รับการยืนยันแล้ว"
+	EMAIL_ALREADY_VERIFIED_EN = "Email is already verified"
+
+	EMAIL_VERIFICATION_TOKEN_NOT_FOUND_TH = "ไม่พบโทเค็นสำหรับยืนยันอีเมลในคำร้องขอ"
+	EMAIL_VERIFICATION_TOKEN_NOT_FOUND_EN = "Email verification token not found"
+
+	EMAIL_VERIFICATION_TOKEN_INVALID_TH = "ลิงก์ยืนยันอีเมลไม่ถูกต้องหรือหมดอายุ"
+	EMAIL_VERIFICATION_TOKEN_INVALID_EN = "Email verification link invalid or expired"
+
+	EMAIL_VERIFICATION_THROTTLED_TH = "ส่งอีเมลยืนยันบ่อยเกินไป กรุณารอสักครู่"
+	EMAIL_VERIFICATION_THROTTLED_EN = "Verification email was sent recently, please wait"
+
+	ROLE_INVALID_TH = "บทบาทของผู้ใช้ไม่ถูกต้อง"
+	ROLE_INVALID_EN = "Role invalid"
+
+	EXPORT_FORMAT_INVALID_TH = "รูปแบบไฟล์ส่งออกข้อมูลไม่ถูกต้อง"
+	EXPORT_FORMAT_INVALID_EN = "Export format invalid"
+
+	ACCOUNT_DELETION_SCHEDULED_TH = "บัญชีนี้อยู่ระหว่างรอการลบแล้ว"
+	ACCOUNT_DELETION_SCHEDULED_EN = "Account deletion is already scheduled"
+
+	PROFILE_NOT_CHANGED_TH = "ไม่พบข้อมูลโปรไฟล์ที่ต้องการแก้ไขในคำร้องขอ"
+	PROFILE_NOT_CHANGED_EN = "No profile field to edit"
+
+	BIO_TOO_LONG_TH = "คำอธิบายตัวเองยาวเกินไป"
+	BIO_TOO_LONG_EN = "Bio is too long"
+
+	LANGUAGE_INVALID_TH = "ภาษาที่เลือกไม่ถูกต้อง"
+	LANGUAGE_INVALID_EN = "Language invalid"
+
+	AVATAR_NOT_FOUND_TH = "ไม่พบไฟล์รูปโปรไฟล์ในคำร้องขอ"
+	AVATAR_NOT_FOUND_EN = "Avatar file not found"
+
+	AVATAR_TOO_LARGE_TH = "ไฟล์รูปโปรไฟล์มีขนาดใหญ่เกินไป"
+	AVATAR_TOO_LARGE_EN = "Avatar file is too large"
+
+	AVATAR_INVALID_TH = "รูปโปรไฟล์ต้องเป็นไฟล์ JPEG, PNG หรือ GIF ที่มีขนาดไม่เกินที่กำหนด"
+	AVATAR_INVALID_EN = "Avatar must be a JPEG, PNG or GIF image within the size limit"
+
+	PROFILE_VISIBILITY_INVALID_TH = "การตั้งค่าการมองเห็นโปรไฟล์ไม่ถูกต้อง"
+	PROFILE_VISIBILITY_INVALID_EN = "Profile visibility invalid"
+
+	PROFILE_NOT_FOUND_TH = "ไม่พบโปรไฟล์ หรือโปรไฟล์นี้ไม่เปิดให้คุณดู"
+	PROFILE_NOT_FOUND_EN = "Profile not found or not visible to you"
+)
+
+// Thai and english message about content authoring error
+const (
+	CONTENT_GROUP_NOT_FOUND_TH = "ไม่พบกลุ่มเนื้อหา"
+	CONTENT_GROUP_NOT_FOUND_EN = "Content group not found"
+
+	CONTENT_GROUP_ID_NOT_FOUND_TH = "ไม่พบรหัสของกลุ่มเนื้อหาในคำร้องขอ"
+	CONTENT_GROUP_ID_NOT_FOUND_EN = "Content group ID not found"
+
+	BADGE_NOT_FOUND_TH = "ไม่พบเหรียญตรา"
+	BADGE_NOT_FOUND_EN = "Badge not found"
+
+	BADGE_ID_NOT_FOUND_TH = "ไม่พบรหัสของเหรียญตราในคำร้องขอ"
+	BADGE_ID_NOT_FOUND_EN = "Badge ID not found"
+
+	MINI_EXAM_ID_NOT_FOUND_TH = "ไม่พบรหัสของแบบทดสอบย่อยในคำร้องขอ"
+	MINI_EXAM_ID_NOT_FOUND_EN = "Mini exam ID not found"
+
+	CONTENT_GROUP_NOT_EMPTY_TH = "ไม่สามารถลบกลุ่มเนื้อหาที่ยังมีเนื้อหาอยู่ได้"
+	CONTENT_GROUP_NOT_EMPTY_EN = "Content group still has contents"
+
+	CONTENT_NOT_EMPTY_TH = "ไม่สามารถลบเนื้อหาที่ยังมีกิจกรรมอยู่ได้"
+	CONTENT_NOT_EMPTY_EN = "Content still has activities"
+
+	ACTIVITY_IN_USE_TH = "ไม่สามารถลบกิจกรรมที่มีผู้เรียนทำแล้วหรืออยู่ในข้อสอบได้"
+	ACTIVITY_IN_USE_EN = "Activity has been answered or is part of an exam"
+
+	NAME_NOT_FOUND_TH = "ไม่พบชื่อในคำร้องขอ"
+	NAME_NOT_FOUND_EN = "Name not found"
+
+	QUESTION_NOT_FOUND_TH = "ไม่พบคำถามในคำร้องขอ"
+	QUESTION_NOT_FOUND_EN = "Question not found"
+
+	POINT_INVALID_TH = "คะแนนต้องไม่ติดลบ"
+	POINT_INVALID_EN = "Point must not be negative"
+
+	ACTIVITY_TYPE_UNKNOWN_TH = "ไม่รู้จักประเภทของกิจกรรม"
+	ACTIVITY_TYPE_UNKNOWN_EN = "Activity type unknown"
+
+	CHOICES_NOT_FOUND_TH = "ไม่พบตัวเลือกของกิจกรรมในคำร้องขอ"
+	CHOICES_NOT_FOUND_EN = "Activity choices not found"
+
+	CHOICES_INVALID_TH = "รูปแบบตัวเลือกไม่ตรงกับประเภทของกิจกรรม"
+	CHOICES_INVALID_EN = "Choices do not match the activity type"
+
+	HINT_NOT_FOUND_TH = "ไม่พบคำใบ้"
+	HINT_NOT_FOUND_EN = "Hint not found"
+
+	HINT_CONTENT_NOT_FOUND_TH = "ไม่พบเนื้อหาของคำใบ้ในคำร้องขอ"
+	HINT_CONTENT_NOT_FOUND_EN = "Hint content not found"
+
+	HINT_LEVEL_INVALID_TH = "ระดับของคำใบ้ไม่ถูกต้องหรือซ้ำกับคำใบ้อื่น"
+	HINT_LEVEL_INVALID_EN = "Hint level invalid or already used"
+
+	BUNDLE_FORMAT_UNKNOWN_TH = "ไม่รู้จักรูปแบบของไฟล์หลักสูตร"
+	BUNDLE_FORMAT_UNKNOWN_EN = "Curriculum bundle format unknown, use yaml or json"
+
+	BUNDLE_INVALID_TH = "ไม่สามารถอ่านไฟล์หลักสูตรได้"
+	BUNDLE_INVALID_EN = "Curriculum bundle cannot be read"
+)
+
+// Thai and english message about SQL query exercise error
+const (
+	SQL_QUERY_NOT_SELECT_TH = "คำตอบต้องเป็นคำสั่ง SELECT เพียงคำสั่งเดียว"
+	SQL_QUERY_NOT_SELECT_EN = "Answer must be a single SELECT statement"
+
+	SQL_QUERY_TIMEOUT_TH = "คำสั่งใช้เวลาทำงานนานเกินไป"
+	SQL_QUERY_TIMEOUT_EN = "Query took too long to run"
+
+	SQL_QUERY_TOO_MANY_ROWS_TH = "ผลลัพธ์ของคำสั่งมีจำนวนแถวมากเกินไป"
+	SQL_QUERY_TOO_MANY_ROWS_EN = "Query returned too many rows"
+
+	SQL_QUERY_TOO_LARGE_TH = "คำสั่งใช้หน่วยความจำมากเกินไป"
+	SQL_QUERY_TOO_LARGE_EN = "Query used too much memory"
+
+	SQL_DATASET_INVALID_TH = "ไม่สามารถสร้างข้อมูลตัวอย่างของกิจกรรมได้"
+	SQL_DATASET_INVALID_EN = "Sample dataset of the activity cannot be created"
+
+	SQL_REFERENCE_QUERY_INVALID_TH = "ไม่สามารถรันคำสั่งเฉลยของกิจกรรมได้"
+	SQL_REFERENCE_QUERY_INVALID_EN = "Reference query of the activity cannot be run"
+)
+
+// Thai and english message about relational algebra exercise error, the
+// messages about an expression are formatted with where the problem is
+const (
+	EXPRESSION_UNEXPECTED_TH = "ไม่คาดว่าจะพบ %s ที่ตำแหน่ง %d ของนิพจน์"
+	EXPRESSION_UNEXPECTED_EN = "Unexpected %s at position %d of the expression"
+
+	EXPRESSION_EXPECTED_TH = "นิพจน์ต้องมี %s ที่ตำแหน่ง %d แต่พบ %s"
+	EXPRESSION_EXPECTED_EN = "Expected %s at position %d of the expression but found %s"
+
+	EXPRESSION_RELATION_NOT_FOUND_TH = "ไม่พบรีเลชัน %s"
+	EXPRESSION_RELATION_NOT_FOUND_EN = "Relation %s not found"
+
+	EXPRESSION_ATTRIBUTE_NOT_FOUND_TH = "ไม่พบแอตทริบิวต์ %s"
+	EXPRESSION_ATTRIBUTE_NOT_FOUND_EN = "Attribute %s not found"
+
+	EXPRESSION_ATTRIBUTE_AMBIGUOUS_TH = "แอตทริบิวต์ %s กำกวม ให้ระบุชื่อรีเลชันหน้าแอตทริบิวต์"
+	EXPRESSION_ATTRIBUTE_AMBIGUOUS_EN = "Attribute %s is ambiguous, qualify it with the name of its relation"
+
+	EXPRESSION_NOT_UNION_COMPATIBLE_TH = "รีเลชันทั้งสองข้างของ %s ต้องมีจำนวนแอตทริบิวต์เท่ากัน"
+	EXPRESSION_NOT_UNION_COMPATIBLE_EN = "Both sides of %s must have the same number of attributes"
+
+	EXPRESSION_RENAME_INVALID_TH = "ρ ต้องตั้งชื่อให้แอตทริบิวต์ครบ %d แอตทริบิวต์"
+	EXPRESSION_RENAME_INVALID_EN = "ρ must name all %d attributes"
+
+	EXPRESSION_AGGREGATE_INVALID_TH = "ใช้ %s กับค่าที่ไม่ใช่ตัวเลขไม่ได้"
+	EXPRESSION_AGGREGATE_INVALID_EN = "%s can only be used on numbers"
+
+	EXPRESSION_TOO_LARGE_TH = "ผลลัพธ์ของนิพจน์มีจำนวนแถวมากเกินไป"
+	EXPRESSION_TOO_LARGE_EN = "Expression result has too many rows"
+
+	EXPRESSION_TOO_LONG_TH = "นิพจน์ยาวเกินไป"
+	EXPRESSION_TOO_LONG_EN = "Expression is too long"
+
+	EXPRESSION_TOO_DEEP_TH = "นิพจน์ซ้อนกันหลายชั้นเกินไป"
+	EXPRESSION_TOO_DEEP_EN = "Expression is nested too deeply"
+
+	REFERENCE_EXPRESSION_INVALID_TH = "ไม่สามารถคำนวณนิพจน์เฉลยของกิจกรรมได้"
+	REFERENCE_EXPRESSION_INVALID_EN = "Reference expression of the activity cannot be evaluated"
+)
+
+// Thai and english message about answer review
+const (
+	REGRADE_JOB_NOT_FOUND_TH = "ไม่พบงานตรวจคำตอบใหม่"
+	REGRADE_JOB_NOT_FOUND_EN = "Regrade job not found"
+
+	REGRADE_JOB_RUNNING_TH = "กำลังตรวจคำตอบของกิจกรรมนี้ใหม่อยู่"
+	REGRADE_JOB_RUNNING_EN = "Answers of this activity are already being regraded"
+)
+
+// Thai and english message about point
+const (
+	POINT_ADJUSTMENT_INVALID_TH = "ต้องระบุจำนวนแต้มที่ไม่เป็นศูนย์และเหตุผลในการปรับแต้ม"
+	POINT_ADJUSTMENT_INVALID_EN = "Adjustment needs a non-zero amount and a note"
+
+	POINTS_NOT_ENOUGH_TH = "แต้มของผู้ใช้ไม่เพียงพอ"
+	POINTS_NOT_ENOUGH_EN = "User does not have enough points"
+)
+
+// Thai and english message about prerequisite
+const (
+	CONTENT_LOCKED_TH = "ต้องผ่านเงื่อนไขของบทเรียนก่อนหน้าเพื่อปลดล็อกบทเรียนนี้"
+	CONTENT_LOCKED_EN = "Meet the prerequisites to unlock this content"
+
+	CONTENT_GROUP_LOCKED_TH = "ต้องผ่านเงื่อนไขก่อนหน้าเพื่อปลดล็อกกลุ่มเนื้อหานี้"
+	CONTENT_GROUP_LOCKED_EN = "Meet the prerequisites to unlock this content group"
+
+	PREREQUISITE_NOT_FOUND_TH = "ไม่พบเงื่อนไขการปลดล็อก"
+	PREREQUISITE_NOT_FOUND_EN = "Prerequisite not found"
+
+	PREREQUISITE_INVALID_TH = "เงื่อนไขการปลดล็อกไม่ถูกต้อง"
+	PREREQUISITE_INVALID_EN = "Prerequisite invalid"
+
+	PREREQUISITE_CYCLE_TH = "เงื่อนไขการปลดล็อกทำให้บทเรียนต้องรอตัวเอง"
+	PREREQUISITE_CYCLE_EN = "Prerequisite would lock a content behind itself"
+)
+
+// Thai and english message about attachment
+const (
+	ATTACHMENT_NOT_FOUND_TH = "ไม่พบไฟล์ประกอบบทเรียน"
+	ATTACHMENT_NOT_FOUND_EN = "Attachment not found"
+
+	ATTACHMENT_FILE_NOT_FOUND_TH = "ไม่พบไฟล์ประกอบบทเรียนในคำร้องขอ"
+	ATTACHMENT_FILE_NOT_FOUND_EN = "Attachment file not found"
+
+	ATTACHMENT_TOO_LARGE_TH = "ไฟล์ประกอบบทเรียนมีขนาดใหญ่เกินไป"
+	ATTACHMENT_TOO_LARGE_EN = "Attachment file is too large"
+
+	ATTACHMENT_INVALID_TH = "ต้องระบุชื่อและประเภทของไฟล์ประกอบบทเรียนเป็น slide, sample_database หรือ cheat_sheet"
+	ATTACHMENT_INVALID_EN = "Attachment needs a name and a type of slide, sample_database or cheat_sheet"
 )
 
 // Thai and english message about verification
 const (
 	UNEXPECTED_SIGNING_METHOD_TH = "วิธีการลงนามที่ไม่คาดคิด"
 	UNEXPECTED_SIGNING_METHOD_EN = "Unexpected signing method"
+
+	TOKEN_INVALID_TH = "โทเค็นไม่ถูกต้อง"
+	TOKEN_INVALID_EN = "Token Invalid"
+
+	REFRESH_TOKEN_NOT_FOUND_TH = "ไม่พบรีเฟรชโทเค็นในคำร้องขอ"
+	REFRESH_TOKEN_NOT_FOUND_EN = "Refresh token not found"
+
+	REFRESH_TOKEN_INVALID_TH = "รีเฟรชโทเค็นไม่ถูกต้องหรือหมดอายุ"
+	REFRESH_TOKEN_INVALID_EN = "Refresh token invalid or expired"
+
+	SESSION_NOT_FOUND_TH = "ไม่พบเซสชัน"
+	SESSION_NOT_FOUND_EN = "Session not found"
+
+	PERMISSION_DENIED_TH = "ไม่มีสิทธิ์เข้าถึง"
+	PERMISSION_DENIED_EN = "Permission denied"
+
+	OIDC_PROVIDER_NOT_FOUND_TH = "ไม่พบผู้ให้บริการเข้าสู่ระบบ"
+	OIDC_PROVIDER_NOT_FOUND_EN = "Login provider not found"
+
+	OIDC_STATE_INVALID_TH = "คำร้องขอเข้าสู่ระบบไม่ถูกต้องหรือหมดอายุ"
+	OIDC_STATE_INVALID_EN = "Login request invalid or expired"
+
+	OIDC_CODE_NOT_FOUND_TH = "ไม่พบรหัสยืนยันตัวตนในคำร้องขอ"
+	OIDC_CODE_NOT_FOUND_EN = "Authorization code not found"
+
+	OIDC_LOGIN_FAILED_TH = "เข้าสู่ระบบผ่านผู้ให้บริการไม่สำเร็จ"
+	OIDC_LOGIN_FAILED_EN = "Login with provider failed"
+
+	OIDC_EMAIL_NOT_VERIFIED_TH = "อีเมลของบัญชีผู้ให้บริการยังไม่ได้รับการยืนยัน"
+	OIDC_EMAIL_NOT_VERIFIED_EN = "Provider account email is not verified"
 )
 
 // Server error
@@ -100,6 +380,7 @@ var (
 	ErrInternalServerError     = NewInternalServerError(INTERNAL_SERVER_ERROR_TH, INTERNAL_SERVER_ERROR_EN)
 	ErrServiceUnavailableError = NewServiceUnavailableError(SERVICE_UNAVAILABLE_ERROR_TH, SERVICE_UNAVAILABLE_ERROR_EN)
 	ErrBadRequestError         = NewBadRequestError(BAD_REQUEST_ERROR_TH, BAD_REQUEST_ERROR_EN)
+	ErrRequestBodyTooLarge     = NewRequestEntityTooLargeError(REQUEST_BODY_TOO_LARGE_TH, REQUEST_BODY_TOO_LARGE_EN)
 )
 
 // Manipulation error
@@ -129,13 +410,119 @@ var (
 
 // User error
 var (
-	ErrUserNotFound              = NewNotFoundError(USER_NOT_FOUND_TH, USER_NOT_FOUND_EN)
-	ErrLeaderBoardNotFound       = NewNotFoundError(LEADER_BOARD_NOT_FOUND_TH, LEADER_BOARD_NOT_FOUND_EN)
-	ErrEmailAlreadyExists        = NewBadRequestError(EMAIL_ALREADY_EXISTS_TH, EMAIL_ALREADY_EXISTS_EN)
-	ErrEmailOrPasswordNotCorrect = NewBadRequestError(EMAIL_OR_PASSWORD_NOT_CORRECT_TH, EMAIL_OR_PASSWORD_NOT_CORRECT_EN)
+	ErrUserNotFound                   = NewNotFoundError(USER_NOT_FOUND_TH, USER_NOT_FOUND_EN)
+	ErrLeaderBoardNotFound            = NewNotFoundError(LEADER_BOARD_NOT_FOUND_TH, LEADER_BOARD_NOT_FOUND_EN)
+	ErrEmailAlreadyExists             = NewBadRequestError(EMAIL_ALREADY_EXISTS_TH, EMAIL_ALREADY_EXISTS_EN)
+	ErrEmailOrPasswordNotCorrect      = NewBadRequestError(EMAIL_OR_PASSWORD_NOT_CORRECT_TH, EMAIL_OR_PASSWORD_NOT_CORRECT_EN)
+	ErrPasswordNotFound               = NewBadRequestError(PASSWORD_NOT_FOUND_TH, PASSWORD_NOT_FOUND_EN)
+	ErrPasswordNotCorrect             = NewBadRequestError(PASSWORD_NOT_CORRECT_TH, PASSWORD_NOT_CORRECT_EN)
+	ErrPasswordNotChanged             = NewBadRequestError(PASSWORD_NOT_CHANGED_TH, PASSWORD_NOT_CHANGED_EN)
+	ErrLoginThrottled                 = NewTooManyRequestsError(LOGIN_THROTTLED_TH, LOGIN_THROTTLED_EN)
+	ErrAccountLocked                  = NewTooManyRequestsError(ACCOUNT_LOCKED_TH, ACCOUNT_LOCKED_EN)
+	ErrUnlockTokenNotFound            = NewBadRequestError(UNLOCK_TOKEN_NOT_FOUND_TH, UNLOCK_TOKEN_NOT_FOUND_EN)
+	ErrUnlockTokenInvalid             = NewBadRequestError(UNLOCK_TOKEN_INVALID_TH, UNLOCK_TOKEN_INVALID_EN)
+	ErrPasswordResetTokenNotFound     = NewBadRequestError(PASSWORD_RESET_TOKEN_NOT_FOUND_TH, PASSWORD_RESET_TOKEN_NOT_FOUND_EN)
+	ErrPasswordResetTokenInvalid      = NewBadRequestError(PASSWORD_RESET_TOKEN_INVALID_TH, PASSWORD_RESET_TOKEN_INVALID_EN)
+	ErrEmailNotVerified               = NewForbiddenError(EMAIL_NOT_VERIFIED_TH, EMAIL_NOT_VERIFIED_EN)
+	ErrEmailAlreadyVerified           = NewBadRequestError(EMAIL_ALREADY_VERIFIED_TH, EMAIL_ALREADY_VERIFIED_EN)
+	ErrEmailVerificationTokenNotFound = NewBadRequestError(EMAIL_VERIFICATION_TOKEN_NOT_FOUND_TH, EMAIL_VERIFICATION_TOKEN_NOT_FOUND_EN)
+	ErrEmailVerificationTokenInvalid  = NewBadRequestError(EMAIL_VERIFICATION_TOKEN_INVALID_TH, EMAIL_VERIFICATION_TOKEN_INVALID_EN)
+	ErrEmailVerificationThrottled     = NewTooManyRequestsError(EMAIL_VERIFICATION_THROTTLED_TH, EMAIL_VERIFICATION_THROTTLED_EN)
+	ErrRoleInvalid                    = NewBadRequestError(ROLE_INVALID_TH, ROLE_INVALID_EN)
+	ErrExportFormatInvalid            = NewBadRequestError(EXPORT_FORMAT_INVALID_TH, EXPORT_FORMAT_INVALID_EN)
+	ErrAccountDeletionScheduled       = NewBadRequestError(ACCOUNT_DELETION_SCHEDULED_TH, ACCOUNT_DELETION_SCHEDULED_EN)
+	ErrProfileNotChanged              = NewBadRequestError(PROFILE_NOT_CHANGED_TH, PROFILE_NOT_CHANGED_EN)
+	ErrBioTooLong                     = NewBadRequestError(BIO_TOO_LONG_TH, BIO_TOO_LONG_EN)
+	ErrLanguageInvalid                = NewBadRequestError(LANGUAGE_INVALID_TH, LANGUAGE_INVALID_EN)
+	ErrAvatarNotFound                 = NewBadRequestError(AVATAR_NOT_FOUND_TH, AVATAR_NOT_FOUND_EN)
+	ErrAvatarTooLarge                 = NewBadRequestError(AVATAR_TOO_LARGE_TH, AVATAR_TOO_LARGE_EN)
+	ErrAvatarInvalid                  = NewBadRequestError(AVATAR_INVALID_TH, AVATAR_INVALID_EN)
+	ErrProfileVisibilityInvalid       = NewBadRequestError(PROFILE_VISIBILITY_INVALID_TH, PROFILE_VISIBILITY_INVALID_EN)
+	ErrProfileNotFound                = NewNotFoundError(PROFILE_NOT_FOUND_TH, PROFILE_NOT_FOUND_EN)
+)
+
+// Content authoring error
+var (
+	ErrContentGroupNotFound   = NewNotFoundError(CONTENT_GROUP_NOT_FOUND_TH, CONTENT_GROUP_NOT_FOUND_EN)
+	ErrContentGroupIDNotFound = NewBadRequestError(CONTENT_GROUP_ID_NOT_FOUND_TH, CONTENT_GROUP_ID_NOT_FOUND_EN)
+	ErrBadgeNotFound          = NewNotFoundError(BADGE_NOT_FOUND_TH, BADGE_NOT_FOUND_EN)
+	ErrBadgeIDNotFound        = NewBadRequestError(BADGE_ID_NOT_FOUND_TH, BADGE_ID_NOT_FOUND_EN)
+	ErrMiniExamIDNotFound     = NewBadRequestError(MINI_EXAM_ID_NOT_FOUND_TH, MINI_EXAM_ID_NOT_FOUND_EN)
+	ErrContentGroupNotEmpty   = NewBadRequestError(CONTENT_GROUP_NOT_EMPTY_TH, CONTENT_GROUP_NOT_EMPTY_EN)
+	ErrContentNotEmpty        = NewBadRequestError(CONTENT_NOT_EMPTY_TH, CONTENT_NOT_EMPTY_EN)
+	ErrActivityInUse          = NewBadRequestError(ACTIVITY_IN_USE_TH, ACTIVITY_IN_USE_EN)
+	ErrNameNotFound           = NewBadRequestError(NAME_NOT_FOUND_TH, NAME_NOT_FOUND_EN)
+	ErrQuestionNotFound       = NewBadRequestError(QUESTION_NOT_FOUND_TH, QUESTION_NOT_FOUND_EN)
+	ErrPointInvalid           = NewBadRequestError(POINT_INVALID_TH, POINT_INVALID_EN)
+	ErrActivityTypeUnknown    = NewBadRequestError(ACTIVITY_TYPE_UNKNOWN_TH, ACTIVITY_TYPE_UNKNOWN_EN)
+	ErrChoicesNotFound        = NewBadRequestError(CHOICES_NOT_FOUND_TH, CHOICES_NOT_FOUND_EN)
+	ErrChoicesInvalid         = NewBadRequestError(CHOICES_INVALID_TH, CHOICES_INVALID_EN)
+	ErrHintNotFound           = NewNotFoundError(HINT_NOT_FOUND_TH, HINT_NOT_FOUND_EN)
+	ErrHintContentNotFound    = NewBadRequestError(HINT_CONTENT_NOT_FOUND_TH, HINT_CONTENT_NOT_FOUND_EN)
+	ErrHintLevelInvalid       = NewBadRequestError(HINT_LEVEL_INVALID_TH, HINT_LEVEL_INVALID_EN)
+	ErrBundleFormatUnknown    = NewBadRequestError(BUNDLE_FORMAT_UNKNOWN_TH, BUNDLE_FORMAT_UNKNOWN_EN)
+	ErrBundleInvalid          = NewBadRequestError(BUNDLE_INVALID_TH, BUNDLE_INVALID_EN)
+)
+
+// SQL query exercise error
+var (
+	ErrSQLQueryNotSelect        = NewBadRequestError(SQL_QUERY_NOT_SELECT_TH, SQL_QUERY_NOT_SELECT_EN)
+	ErrSQLQueryTimeout          = NewBadRequestError(SQL_QUERY_TIMEOUT_TH, SQL_QUERY_TIMEOUT_EN)
+	ErrSQLQueryTooManyRows      = NewBadRequestError(SQL_QUERY_TOO_MANY_ROWS_TH, SQL_QUERY_TOO_MANY_ROWS_EN)
+	ErrSQLQueryTooLarge         = NewBadRequestError(SQL_QUERY_TOO_LARGE_TH, SQL_QUERY_TOO_LARGE_EN)
+	ErrSQLDatasetInvalid        = NewBadRequestError(SQL_DATASET_INVALID_TH, SQL_DATASET_INVALID_EN)
+	ErrSQLReferenceQueryInvalid = NewBadRequestError(SQL_REFERENCE_QUERY_INVALID_TH, SQL_REFERENCE_QUERY_INVALID_EN)
+)
+
+// Relational algebra exercise error, the errors about an expression are
+// created where the expression is parsed
+var (
+	ErrExpressionTooLarge         = NewBadRequestError(EXPRESSION_TOO_LARGE_TH, EXPRESSION_TOO_LARGE_EN)
+	ErrExpressionTooLong          = NewBadRequestError(EXPRESSION_TOO_LONG_TH, EXPRESSION_TOO_LONG_EN)
+	ErrExpressionTooDeep          = NewBadRequestError(EXPRESSION_TOO_DEEP_TH, EXPRESSION_TOO_DEEP_EN)
+	ErrReferenceExpressionInvalid = NewBadRequestError(REFERENCE_EXPRESSION_INVALID_TH, REFERENCE_EXPRESSION_INVALID_EN)
+)
+
+// Answer review error
+var (
+	ErrRegradeJobNotFound = NewNotFoundError(REGRADE_JOB_NOT_FOUND_TH, REGRADE_JOB_NOT_FOUND_EN)
+	ErrRegradeJobRunning  = NewBadRequestError(REGRADE_JOB_RUNNING_TH, REGRADE_JOB_RUNNING_EN)
+)
+
+// Point error
+var (
+	ErrPointAdjustmentInvalid = NewBadRequestError(POINT_ADJUSTMENT_INVALID_TH, POINT_ADJUSTMENT_INVALID_EN)
+	ErrPointsNotEnough        = NewBadRequestError(POINTS_NOT_ENOUGH_TH, POINTS_NOT_ENOUGH_EN)
+)
+
+// Prerequisite error
+var (
+	ErrContentLocked        = NewForbiddenError(CONTENT_LOCKED_TH, CONTENT_LOCKED_EN)
+	ErrContentGroupLocked   = NewForbiddenError(CONTENT_GROUP_LOCKED_TH, CONTENT_GROUP_LOCKED_EN)
+	ErrPrerequisiteNotFound = NewNotFoundError(PREREQUISITE_NOT_FOUND_TH, PREREQUISITE_NOT_FOUND_EN)
+	ErrPrerequisiteInvalid  = NewBadRequestError(PREREQUISITE_INVALID_TH, PREREQUISITE_INVALID_EN)
+	ErrPrerequisiteCycle    = NewBadRequestError(PREREQUISITE_CYCLE_TH, PREREQUISITE_CYCLE_EN)
+)
+
+// Attachment error
+var (
+	ErrAttachmentNotFound     = NewNotFoundError(ATTACHMENT_NOT_FOUND_TH, ATTACHMENT_NOT_FOUND_EN)
+	ErrAttachmentFileNotFound = NewBadRequestError(ATTACHMENT_FILE_NOT_FOUND_TH, ATTACHMENT_FILE_NOT_FOUND_EN)
+	ErrAttachmentTooLarge     = NewBadRequestError(ATTACHMENT_TOO_LARGE_TH, ATTACHMENT_TOO_LARGE_EN)
+	ErrAttachmentInvalid      = NewBadRequestError(ATTACHMENT_INVALID_TH, ATTACHMENT_INVALID_EN)
 )
 
 // Verification error
 var (
 	ErrUnExpectedsigningMethod = NewForbiddenError(UNEXPECTED_SIGNING_METHOD_TH, UNEXPECTED_SIGNING_METHOD_EN)
+	ErrTokenInvalid            = NewForbiddenError(TOKEN_INVALID_TH, TOKEN_INVALID_EN)
+	ErrRefreshTokenNotFound    = NewBadRequestError(REFRESH_TOKEN_NOT_FOUND_TH, REFRESH_TOKEN_NOT_FOUND_EN)
+	ErrRefreshTokenInvalid     = NewForbiddenError(REFRESH_TOKEN_INVALID_TH, REFRESH_TOKEN_INVALID_EN)
+	ErrSessionNotFound         = NewNotFoundError(SESSION_NOT_FOUND_TH, SESSION_NOT_FOUND_EN)
+	ErrPermissionDenied        = NewForbiddenError(PERMISSION_DENIED_TH, PERMISSION_DENIED_EN)
+	ErrOIDCProviderNotFound    = NewNotFoundError(OIDC_PROVIDER_NOT_FOUND_TH, OIDC_PROVIDER_NOT_FOUND_EN)
+	ErrOIDCStateInvalid        = NewBadRequestError(OIDC_STATE_INVALID_TH, OIDC_STATE_INVALID_EN)
+	ErrOIDCCodeNotFound        = NewBadRequestError(OIDC_CODE_NOT_FOUND_TH, OIDC_CODE_NOT_FOUND_EN)
+	ErrOIDCLoginFailed         = NewForbiddenError(OIDC_LOGIN_FAILED_TH, OIDC_LOGIN_FAILED_EN)
+	ErrOIDCEmailNotVerified    = NewForbiddenError(OIDC_EMAIL_NOT_VERIFIED_TH, OIDC_EMAIL_NOT_VERIFIED_EN)
 )
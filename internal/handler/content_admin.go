@@ -0,0 +1,464 @@
+package handler
+
+import (
+	"database-camp/internal/errs"
+	"database-camp/internal/infrastructure/application"
+	"database-camp/internal/models/entities/content"
+	"database-camp/internal/models/request"
+	"database-camp/internal/services"
+	"database-camp/internal/utils"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+type ContentAdminHandler interface {
+	GetContentGroups(c application.Context)
+	CreateContentGroup(c application.Context)
+	UpdateContentGroup(c application.Context)
+	DeleteContentGroup(c application.Context)
+	GetContents(c application.Context)
+	CreateContent(c application.Context)
+	UpdateContent(c application.Context)
+	DeleteContent(c application.Context)
+	GetActivities(c application.Context)
+	GetActivity(c application.Context)
+	CreateActivity(c application.Context)
+	UpdateActivity(c application.Context)
+	DeleteActivity(c application.Context)
+	CreateHint(c application.Context)
+	UpdateHint(c application.Context)
+	DeleteHint(c application.Context)
+	GetPrerequisites(c application.Context)
+	CreatePrerequisite(c application.Context)
+	DeletePrerequisite(c application.Context)
+	GetAttachmentStatistics(c application.Context)
+	CreateAttachment(c application.Context)
+	DeleteAttachment(c application.Context)
+}
+
+type contentAdminHandler struct {
+	service services.ContentAdminService
+}
+
+func NewContentAdminHandler(service services.ContentAdminService) *contentAdminHandler {
+	return &contentAdminHandler{service: service}
+}
+
+func (h contentAdminHandler) GetContentGroups(c application.Context) {
+	response, err := h.service.GetContentGroups()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) CreateContentGroup(c application.Context) {
+	request := request.ContentGroupRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.CreateContentGroup(request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) UpdateContentGroup(c application.Context) {
+	id := utils.ParseInt(c.Params("id"))
+	request := request.ContentGroupRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.UpdateContentGroup(id, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) DeleteContentGroup(c application.Context) {
+	id := utils.ParseInt(c.Params("id"))
+
+	response, err := h.service.DeleteContentGroup(id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) GetContents(c application.Context) {
+	id := utils.ParseInt(c.Params("id"))
+
+	response, err := h.service.GetContents(id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) CreateContent(c application.Context) {
+	request := request.ContentRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.CreateContent(request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) UpdateContent(c application.Context) {
+	id := utils.ParseInt(c.Params("id"))
+	request := request.ContentRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.UpdateContent(id, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) DeleteContent(c application.Context) {
+	id := utils.ParseInt(c.Params("id"))
+
+	response, err := h.service.DeleteContent(id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) GetActivities(c application.Context) {
+	id := utils.ParseInt(c.Params("id"))
+
+	response, err := h.service.GetActivities(id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) GetActivity(c application.Context) {
+	id := utils.ParseInt(c.Params("id"))
+
+	response, err := h.service.GetActivity(id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) CreateActivity(c application.Context) {
+	request := request.ActivityRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.CreateActivity(request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) UpdateActivity(c application.Context) {
+	id := utils.ParseInt(c.Params("id"))
+	request := request.ActivityRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.UpdateActivity(id, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) DeleteActivity(c application.Context) {
+	id := utils.ParseInt(c.Params("id"))
+
+	response, err := h.service.DeleteActivity(id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) CreateHint(c application.Context) {
+	request := request.HintRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.CreateHint(request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) UpdateHint(c application.Context) {
+	id := utils.ParseInt(c.Params("id"))
+	request := request.HintRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.UpdateHint(id, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) DeleteHint(c application.Context) {
+	id := utils.ParseInt(c.Params("id"))
+
+	response, err := h.service.DeleteHint(id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) GetPrerequisites(c application.Context) {
+	response, err := h.service.GetPrerequisites()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) CreatePrerequisite(c application.Context) {
+	request := request.PrerequisiteRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.CreatePrerequisite(request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) DeletePrerequisite(c application.Context) {
+	id := utils.ParseInt(c.Params("id"))
+
+	response, err := h.service.DeletePrerequisite(id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) GetAttachmentStatistics(c application.Context) {
+	contentID := utils.ParseInt(c.Params("id"))
+
+	response, err := h.service.GetAttachmentStatistics(contentID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) CreateAttachment(c application.Context) {
+	contentID := utils.ParseInt(c.Params("id"))
+
+	file, err := c.FormFile("file")
+	if err == errs.ErrRequestBodyTooLarge {
+		c.Error(errs.ErrAttachmentTooLarge)
+		return
+	} else if err != nil {
+		c.Error(errs.ErrAttachmentFileNotFound)
+		return
+	}
+
+	if file.Size > request.MAX_ATTACHMENT_BYTES {
+		c.Error(errs.ErrAttachmentTooLarge)
+		return
+	}
+
+	_file, err := file.Open()
+	if err != nil {
+		c.Error(errs.ErrAttachmentFileNotFound)
+		return
+	}
+	defer _file.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(_file, request.MAX_ATTACHMENT_BYTES+1))
+	if err != nil {
+		c.Error(errs.ErrAttachmentFileNotFound)
+		return
+	}
+
+	contentType := file.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	request := request.AttachmentRequest{
+		Type:        c.FormValue("type"),
+		Name:        c.FormValue("name"),
+		FileName:    content.AttachmentFileName(file.Filename),
+		ContentType: contentType,
+		Data:        data,
+	}
+
+	err = request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.CreateAttachment(contentID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h contentAdminHandler) DeleteAttachment(c application.Context) {
+	id := utils.ParseInt(c.Params("id"))
+
+	response, err := h.service.DeleteAttachment(id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
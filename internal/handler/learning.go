@@ -14,9 +14,12 @@ type LearningHandler interface {
 	GetOverview(c application.Context)
 	GetActivity(c application.Context)
 	GetRecommend(c application.Context)
+	GetReviews(c application.Context)
 	UseHint(c application.Context)
 	CheckAnswer(c application.Context)
 	PeerReview(c application.Context)
+	GetAttachments(c application.Context)
+	DownloadAttachment(c application.Context)
 }
 
 type learningHandler struct {
@@ -41,9 +44,10 @@ func (h learningHandler) GetContentRoadmap(c application.Context) {
 }
 
 func (h learningHandler) GetVideo(c application.Context) {
+	userID := utils.ParseInt(c.Locals("id"))
 	contentID := c.Params("id")
 
-	response, err := h.service.GetVideoLecture(utils.ParseInt(contentID))
+	response, err := h.service.GetVideoLecture(userID, utils.ParseInt(contentID))
 	if err != nil {
 		c.Error(err)
 		return
@@ -79,8 +83,38 @@ func (h learningHandler) GetActivity(c application.Context) {
 
 func (h learningHandler) GetRecommend(c application.Context) {
 	userID := utils.ParseInt(c.Locals("id"))
+	request := request.RecommendRequest{
+		Limit: utils.ParseInt(c.Query("limit")),
+	}
+
+	err := request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.GetRecommend(userID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h learningHandler) GetReviews(c application.Context) {
+	userID := utils.ParseInt(c.Locals("id"))
+	request := request.ReviewQueueRequest{
+		Limit: utils.ParseInt(c.Query("limit")),
+	}
+
+	err := request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
 
-	response, err := h.service.GetRecommend(userID)
+	response, err := h.service.GetReviews(userID, request)
 	if err != nil {
 		c.Error(err)
 		return
@@ -151,3 +185,29 @@ func (h learningHandler) PeerReview(c application.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+func (h learningHandler) GetAttachments(c application.Context) {
+	userID := utils.ParseInt(c.Locals("id"))
+	contentID := utils.ParseInt(c.Params("id"))
+
+	response, err := h.service.GetAttachments(userID, contentID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h learningHandler) DownloadAttachment(c application.Context) {
+	userID := utils.ParseInt(c.Locals("id"))
+	attachmentID := utils.ParseInt(c.Params("id"))
+
+	response, err := h.service.GetAttachmentLink(userID, attachmentID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
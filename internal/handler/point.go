@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"database-camp/internal/infrastructure/application"
+	"database-camp/internal/models/request"
+	"database-camp/internal/services"
+	"database-camp/internal/utils"
+	"net/http"
+)
+
+type PointHandler interface {
+	GetOwnHistory(c application.Context)
+	GetHistory(c application.Context)
+	AdjustPoint(c application.Context)
+}
+
+type pointHandler struct {
+	service services.PointService
+}
+
+func NewPointHandler(service services.PointService) *pointHandler {
+	return &pointHandler{service: service}
+}
+
+func (h pointHandler) GetOwnHistory(c application.Context) {
+	h.getHistory(c, utils.ParseInt(c.Locals("id")))
+}
+
+func (h pointHandler) GetHistory(c application.Context) {
+	h.getHistory(c, utils.ParseInt(c.Params("id")))
+}
+
+func (h pointHandler) getHistory(c application.Context, userID int) {
+	request := request.PointHistoryRequest{
+		Limit:  utils.ParseInt(c.Query("limit")),
+		Offset: utils.ParseInt(c.Query("offset")),
+	}
+
+	err := request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.GetHistory(userID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h pointHandler) AdjustPoint(c application.Context) {
+	adminID := utils.ParseInt(c.Locals("id"))
+	userID := utils.ParseInt(c.Params("id"))
+	request := request.PointAdjustmentRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.AdjustPoint(adminID, userID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"database-camp/internal/infrastructure/application"
+	"database-camp/internal/models/request"
+	"database-camp/internal/services"
+	"database-camp/internal/utils"
+)
+
+type PrivacyHandler interface {
+	ExportData(c application.Context)
+}
+
+type privacyHandler struct {
+	service services.PrivacyService
+}
+
+func NewPrivacyHandler(service services.PrivacyService) *privacyHandler {
+	return &privacyHandler{service: service}
+}
+
+func (h privacyHandler) ExportData(c application.Context) {
+	userID := utils.ParseInt(c.Locals("id"))
+	request := request.DataExportRequest{Format: c.Query("format")}
+
+	err := request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.ExportData(userID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Download(response.Filename, response.ContentType, response.Data)
+}
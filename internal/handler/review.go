@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"database-camp/internal/infrastructure/application"
+	"database-camp/internal/models/request"
+	"database-camp/internal/services"
+	"database-camp/internal/utils"
+	"net/http"
+)
+
+type ReviewHandler interface {
+	GetOwnAttempts(c application.Context)
+	GetAttempts(c application.Context)
+	GetExamResult(c application.Context)
+	StartRegrade(c application.Context)
+	GetRegradeJob(c application.Context)
+}
+
+type reviewHandler struct {
+	service services.ReviewService
+}
+
+func NewReviewHandler(service services.ReviewService) *reviewHandler {
+	return &reviewHandler{service: service}
+}
+
+func (h reviewHandler) GetOwnAttempts(c application.Context) {
+	userID := utils.ParseInt(c.Locals("id"))
+	activityID := utils.ParseInt(c.Params("id"))
+	request := attemptsRequest(c)
+
+	err := request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.GetOwnAttempts(userID, activityID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h reviewHandler) GetAttempts(c application.Context) {
+	activityID := utils.ParseInt(c.Params("id"))
+	request := attemptsRequest(c)
+
+	if userID := c.Query("user_id"); userID != "" {
+		id := utils.ParseInt(userID)
+		request.UserID = &id
+	}
+
+	err := request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.GetAttempts(activityID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h reviewHandler) GetExamResult(c application.Context) {
+	examResultID := utils.ParseInt(c.Params("id"))
+
+	response, err := h.service.GetExamResult(examResultID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h reviewHandler) StartRegrade(c application.Context) {
+	activityID := utils.ParseInt(c.Params("id"))
+
+	response, err := h.service.StartRegrade(activityID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+func (h reviewHandler) GetRegradeJob(c application.Context) {
+	response, err := h.service.GetRegradeJob(c.Params("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func attemptsRequest(c application.Context) request.AttemptsRequest {
+	return request.AttemptsRequest{
+		Limit:  utils.ParseInt(c.Query("limit")),
+		Offset: utils.ParseInt(c.Query("offset")),
+	}
+}
@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"database-camp/internal/errs"
 	"database-camp/internal/infrastructure/application"
 	"database-camp/internal/models/request"
 	"database-camp/internal/services"
 	"database-camp/internal/utils"
+	"io"
+	"io/ioutil"
 	"net/http"
 )
 
@@ -15,6 +18,23 @@ type UserHandler interface {
 	GetOwnProfile(c application.Context)
 	GetUserRanking(c application.Context)
 	Edit(c application.Context)
+	RefreshToken(c application.Context)
+	Logout(c application.Context)
+	GetSessions(c application.Context)
+	RevokeSession(c application.Context)
+	RequestPasswordReset(c application.Context)
+	ConfirmPasswordReset(c application.Context)
+	VerifyEmail(c application.Context)
+	ResendEmailVerification(c application.Context)
+	GetUserRoles(c application.Context)
+	SetUserRoles(c application.Context)
+	OIDCLogin(c application.Context)
+	OIDCCallback(c application.Context)
+	UnlockLogin(c application.Context)
+	ChangePassword(c application.Context)
+	DeleteAccount(c application.Context)
+	UploadAvatar(c application.Context)
+	DeleteAvatar(c application.Context)
 }
 
 type userHandler struct {
@@ -40,7 +60,7 @@ func (h userHandler) Register(c application.Context) {
 		return
 	}
 
-	response, err := h.service.Register(request)
+	response, err := h.service.Register(request, h.client(c))
 	if err != nil {
 		c.Error(err)
 		return
@@ -64,7 +84,7 @@ func (h userHandler) Login(c application.Context) {
 		return
 	}
 
-	response, err := h.service.Login(request)
+	response, err := h.service.Login(request, h.client(c))
 	if err != nil {
 		c.Error(err)
 		return
@@ -75,8 +95,9 @@ func (h userHandler) Login(c application.Context) {
 
 func (h userHandler) GetProfile(c application.Context) {
 	id := utils.ParseInt(c.Params("id"))
+	viewerID := utils.ParseInt(c.Locals("id"))
 
-	response, err := h.service.GetProfile(id)
+	response, err := h.service.GetProfile(viewerID, id)
 	if err != nil {
 		c.Error(err)
 		return
@@ -88,7 +109,7 @@ func (h userHandler) GetProfile(c application.Context) {
 func (h userHandler) GetOwnProfile(c application.Context) {
 	id := utils.ParseInt(c.Locals("id"))
 
-	response, err := h.service.GetProfile(id)
+	response, err := h.service.GetProfile(id, id)
 	if err != nil {
 		c.Error(err)
 		return
@@ -110,7 +131,7 @@ func (h userHandler) GetUserRanking(c application.Context) {
 }
 
 func (h userHandler) Edit(c application.Context) {
-	request := request.UserRequest{}
+	request := request.EditProfileRequest{}
 	userID := utils.ParseInt(c.Locals("id"))
 
 	err := c.Bind(&request)
@@ -119,7 +140,7 @@ func (h userHandler) Edit(c application.Context) {
 		return
 	}
 
-	err = request.ValidateEdit()
+	err = request.Validate()
 	if err != nil {
 		c.Error(err)
 		return
@@ -133,3 +154,356 @@ func (h userHandler) Edit(c application.Context) {
 
 	c.JSON(http.StatusOK, response)
 }
+
+func (h userHandler) UploadAvatar(c application.Context) {
+	userID := utils.ParseInt(c.Locals("id"))
+
+	file, err := c.FormFile("avatar")
+	if err == errs.ErrRequestBodyTooLarge {
+		c.Error(errs.ErrAvatarTooLarge)
+		return
+	} else if err != nil {
+		c.Error(errs.ErrAvatarNotFound)
+		return
+	}
+
+	if file.Size > request.MAX_AVATAR_BYTES {
+		c.Error(errs.ErrAvatarTooLarge)
+		return
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		c.Error(errs.ErrAvatarNotFound)
+		return
+	}
+	defer content.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(content, request.MAX_AVATAR_BYTES+1))
+	if err != nil {
+		c.Error(errs.ErrAvatarNotFound)
+		return
+	}
+
+	request := request.AvatarRequest{Data: data}
+
+	err = request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.UploadAvatar(userID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h userHandler) DeleteAvatar(c application.Context) {
+	userID := utils.ParseInt(c.Locals("id"))
+
+	response, err := h.service.DeleteAvatar(userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h userHandler) RefreshToken(c application.Context) {
+	request := request.RefreshTokenRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.RefreshToken(request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h userHandler) Logout(c application.Context) {
+	userID := utils.ParseInt(c.Locals("id"))
+	sessionID := utils.ParseInt(c.Locals("sid"))
+
+	response, err := h.service.RevokeSession(userID, sessionID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h userHandler) GetSessions(c application.Context) {
+	userID := utils.ParseInt(c.Locals("id"))
+	sessionID := utils.ParseInt(c.Locals("sid"))
+
+	response, err := h.service.GetSessions(userID, sessionID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h userHandler) RevokeSession(c application.Context) {
+	userID := utils.ParseInt(c.Locals("id"))
+	sessionID := utils.ParseInt(c.Params("id"))
+
+	response, err := h.service.RevokeSession(userID, sessionID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h userHandler) RequestPasswordReset(c application.Context) {
+	request := request.PasswordResetRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.RequestPasswordReset(request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h userHandler) ConfirmPasswordReset(c application.Context) {
+	request := request.PasswordResetConfirmRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.ConfirmPasswordReset(request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h userHandler) VerifyEmail(c application.Context) {
+	request := request.EmailVerificationRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.VerifyEmail(request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h userHandler) ResendEmailVerification(c application.Context) {
+	userID := utils.ParseInt(c.Locals("id"))
+
+	response, err := h.service.ResendEmailVerification(userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h userHandler) GetUserRoles(c application.Context) {
+	userID := utils.ParseInt(c.Params("id"))
+
+	response, err := h.service.GetUserRoles(userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h userHandler) SetUserRoles(c application.Context) {
+	userID := utils.ParseInt(c.Params("id"))
+	request := request.UserRolesRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.SetUserRoles(userID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h userHandler) OIDCLogin(c application.Context) {
+	response, err := h.service.OIDCLogin(c.Params("provider"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h userHandler) OIDCCallback(c application.Context) {
+	request := request.OIDCCallbackRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.OIDCCallback(c.Params("provider"), request, h.client(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h userHandler) UnlockLogin(c application.Context) {
+	request := request.LoginUnlockRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.UnlockLogin(request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h userHandler) ChangePassword(c application.Context) {
+	userID := utils.ParseInt(c.Locals("id"))
+	sessionID := utils.ParseInt(c.Locals("sid"))
+	request := request.ChangePasswordRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.ChangePassword(userID, sessionID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h userHandler) DeleteAccount(c application.Context) {
+	userID := utils.ParseInt(c.Locals("id"))
+	request := request.AccountDeletionRequest{}
+
+	err := c.Bind(&request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response, err := h.service.DeleteAccount(userID, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h userHandler) client(c application.Context) request.Client {
+	return request.Client{
+		UserAgent: c.GetHeader("User-Agent"),
+		IPAddress: c.IP(),
+	}
+}
@@ -1,5 +1,7 @@
 package application
 
+import "mime/multipart"
+
 type App interface {
 	Get(path string, handles ...func(Context))
 	Post(path string, handles ...func(Context))
@@ -19,11 +21,16 @@ type Router interface {
 type Context interface {
 	Bind(v interface{}) error
 	JSON(statuscode int, v interface{})
+	Download(filename string, contentType string, data []byte)
 	Error(err error) error
 	Params(key string, defaultValue ...string) string
+	Query(key string, defaultValue ...string) string
+	FormFile(key string) (*multipart.FileHeader, error)
+	FormValue(key string, defaultValue ...string) string
 	Locals(key string, value ...interface{}) (val interface{})
 
 	Next() error
 
 	GetHeader(key string) string
+	IP() string
 }
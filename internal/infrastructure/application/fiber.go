@@ -1,8 +1,13 @@
 package application
 
 import (
+	"bytes"
 	"database-camp/internal/errs"
 	"database-camp/internal/logs"
+	"errors"
+	"math"
+	"mime/multipart"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -36,6 +41,10 @@ func NewFiberApp() *FiberApp {
 		StrictRouting: true,
 		ServerHeader:  "Fiber",
 		AppName:       "Database Camp",
+		// Bodies over the default BodyLimit reach the handlers as a stream,
+		// which FiberCtx reads only on routes that raised it with BodyLimit
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
 	})
 
 	r.Use(cors.New())
@@ -66,6 +75,22 @@ func (r *FiberApp) Group(path string, handles ...func(Context)) Router {
 	return NewFiberRouter(router)
 }
 
+const bodyLimitKey = "bodyLimit"
+
+/**
+ * Raise the body limit of the routes after this handler
+ *
+ * @param limit largest request body in bytes the routes accept
+ *
+ * @return handler setting the limit
+ */
+func BodyLimit(limit int) func(Context) {
+	return func(c Context) {
+		c.Locals(bodyLimitKey, limit)
+		c.Next()
+	}
+}
+
 type FiberCtx struct {
 	*fiber.Ctx
 }
@@ -90,7 +115,66 @@ func (c *FiberCtx) Params(key string, defaultValue ...string) string {
 	return c.Ctx.Params(key, defaultValue...)
 }
 
+/**
+ * Check the request body against the body limit of the route before reading it
+ *
+ * @return nil if the body fits, ErrRequestBodyTooLarge otherwise
+ */
+func (c *FiberCtx) checkBodyLimit() error {
+	request := c.Request()
+	if !request.IsBodyStream() {
+		return nil
+	}
+
+	limit, ok := c.Ctx.Locals(bodyLimitKey).(int)
+	if !ok {
+		limit = c.App().Config().BodyLimit
+	}
+
+	length := request.Header.ContentLength()
+	if length > limit {
+		// Leave the rest of the body unread on a connection that will not be reused
+		c.Context().SetConnectionClose()
+		return errs.ErrRequestBodyTooLarge
+	} else if length >= 0 {
+		return nil
+	}
+
+	// A chunked body has no length ahead, so read it up to the limit
+	body := &limitedBuffer{limit: limit}
+	if err := request.BodyWriteTo(body); err == errBodyTooLarge {
+		c.Context().SetConnectionClose()
+		return errs.ErrRequestBodyTooLarge
+	} else if err != nil {
+		logs.GetInstance().Error(err)
+		return errs.ErrBadRequestError
+	}
+	request.SetBody(body.buffer.Bytes())
+
+	return nil
+}
+
+var errBodyTooLarge = errors.New("body exceeds its limit")
+
+// Buffer refusing to grow beyond its limit, it is not an io.ReaderFrom
+// so that copying into it goes through Write
+type limitedBuffer struct {
+	buffer bytes.Buffer
+	limit  int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.buffer.Len()+len(p) > b.limit {
+		return 0, errBodyTooLarge
+	}
+	return b.buffer.Write(p)
+}
+
 func (c *FiberCtx) Bind(v interface{}) error {
+	if err := c.checkBodyLimit(); err != nil {
+		return err
+	}
+
 	err := c.BodyParser(v)
 	if err != nil {
 		logs.GetInstance().Error(err)
@@ -99,20 +183,48 @@ func (c *FiberCtx) Bind(v interface{}) error {
 	return nil
 }
 
+func (c *FiberCtx) FormFile(key string) (*multipart.FileHeader, error) {
+	if err := c.checkBodyLimit(); err != nil {
+		return nil, err
+	}
+	return c.Ctx.FormFile(key)
+}
+
+func (c *FiberCtx) FormValue(key string, defaultValue ...string) string {
+	if err := c.checkBodyLimit(); err != nil {
+		if len(defaultValue) > 0 {
+			return defaultValue[0]
+		}
+		return ""
+	}
+	return c.Ctx.FormValue(key, defaultValue...)
+}
+
 func (c *FiberCtx) JSON(statuscode int, v interface{}) {
 	c.Ctx.Status(statuscode).JSON(v)
 }
 
+func (c *FiberCtx) Download(filename string, contentType string, data []byte) {
+	c.Ctx.Attachment(filename)
+	c.Ctx.Set(fiber.HeaderContentType, contentType)
+	c.Ctx.Status(fiber.StatusOK).Send(data)
+}
+
 func (c *FiberCtx) Error(err error) error {
 
 	type message struct {
-		Th string `json:"th_message"`
-		En string `json:"en_message"`
+		Th         string `json:"th_message"`
+		En         string `json:"en_message"`
+		RetryAfter int    `json:"retry_after,omitempty"`
 	}
 
 	switch e := err.(type) {
 	case errs.AppError:
-		return c.Status(e.Code).JSON(message{Th: e.ThMessage, En: e.EnMessage})
+		retryAfter := int(math.Ceil(e.RetryAfter.Seconds()))
+		if retryAfter > 0 {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+		}
+		return c.Status(e.Code).JSON(message{Th: e.ThMessage, En: e.EnMessage, RetryAfter: retryAfter})
 	case error:
 		return c.Status(fiber.StatusInternalServerError).JSON(message{
 			Th: errs.INTERNAL_SERVER_ERROR_TH,
@@ -1,8 +1,22 @@
 package cache
 
-import "time"
+import (
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Nil is the error of a key that does not exist
+const Nil = redis.Nil
 
 type Cache interface {
 	Get(key string) (string, error)
 	Set(key string, value interface{}, expiration time.Duration) error
+	SetNX(key string, value interface{}, expiration time.Duration) (bool, error)
+	GetDel(key string) (string, error)
+	Expire(key string, expiration time.Duration) (bool, error)
+	TTL(key string) (time.Duration, error)
+	Incr(key string, expiration time.Duration) (int64, error)
+	Delete(keys ...string) error
+	DeleteIfEqual(key string, value string) (bool, error)
 }
@@ -24,5 +24,54 @@ func (c *redisClient) Get(key string) (string, error) {
 }
 
 func (c *redisClient) Set(key string, value interface{}, expiration time.Duration) error {
-	return c.Client.Set(context.Background(), key, value, time.Second*10).Err()
+	return c.Client.Set(context.Background(), key, value, expiration).Err()
+}
+
+func (c *redisClient) SetNX(key string, value interface{}, expiration time.Duration) (bool, error) {
+	return c.Client.SetNX(context.Background(), key, value, expiration).Result()
+}
+
+func (c *redisClient) GetDel(key string) (string, error) {
+	return c.Client.GetDel(context.Background(), key).Result()
+}
+
+func (c *redisClient) Expire(key string, expiration time.Duration) (bool, error) {
+	return c.Client.Expire(context.Background(), key, expiration).Result()
+}
+
+func (c *redisClient) TTL(key string) (time.Duration, error) {
+	return c.Client.TTL(context.Background(), key).Result()
+}
+
+var incr = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// Incr starts the expiration when the counter is created, so the counter is a
+// fixed window rather than being extended by every increment. Both run in one
+// script, a counter is never left without an expiration.
+func (c *redisClient) Incr(key string, expiration time.Duration) (int64, error) {
+	return incr.Run(context.Background(), c.Client, []string{key}, expiration.Milliseconds()).Int64()
+}
+
+func (c *redisClient) Delete(keys ...string) error {
+	return c.Client.Del(context.Background(), keys...).Err()
+}
+
+var deleteIfEqual = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// DeleteIfEqual deletes the key only while it still holds the value, so a
+// lock that expired and was taken by someone else is left to them.
+func (c *redisClient) DeleteIfEqual(key string, value string) (bool, error) {
+	deleted, err := deleteIfEqual.Run(context.Background(), c.Client, []string{key}, value).Int()
+	return deleted > 0, err
 }
@@ -0,0 +1,38 @@
+package mailer
+
+import (
+	"database-camp/internal/logs"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// logMailer never sends anything, it is meant for development and tests.
+type logMailer struct {
+	path  string
+	from  string
+	mutex *sync.Mutex
+}
+
+func NewLogMailer(path string, from string) *logMailer {
+	return &logMailer{path: path, from: from, mutex: &sync.Mutex{}}
+}
+
+func (m logMailer) Send(mail Mail) error {
+	if m.path == "" {
+		logs.GetInstance().Info(fmt.Sprintf("mail to %s: %s\n%s", mail.To, mail.Subject, mail.Body))
+		return nil
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	file, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "From: %s\nTo: %s\nSubject: %s\n\n%s\n\n", m.from, mail.To, mail.Subject, mail.Body)
+	return err
+}
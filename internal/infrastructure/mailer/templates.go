@@ -0,0 +1,141 @@
+package mailer
+
+// templates.go
+/**
+ * 	This file used to defind all mail content in the application,
+ *  every mail is written in Thai followed by English
+ */
+
+import (
+	"fmt"
+	"time"
+)
+
+const bilingualBody = "%s\n\n----------\n\n%s"
+
+// Thai and english content of password reset mail
+const (
+	PASSWORD_RESET_SUBJECT_TH = "รีเซ็ตรหัสผ่าน Database Camp"
+	PASSWORD_RESET_SUBJECT_EN = "Reset your Database Camp password"
+
+	PASSWORD_RESET_BODY_TH = "สวัสดีคุณ %s\n\nเราได้รับคำร้องขอรีเซ็ตรหัสผ่านของบัญชีคุณ กรุณาตั้งรหัสผ่านใหม่ผ่านลิงก์ด้านล่างภายใน %d นาที\n\n%s\n\nหากคุณไม่ได้ร้องขอ สามารถเพิกเฉยต่ออีเมลฉบับนี้ได้"
+	PASSWORD_RESET_BODY_EN = "Hello %s,\n\nWe received a request to reset the password of your account. Please set a new password with the link below within %d minutes.\n\n%s\n\nIf you did not make this request, you can ignore this email."
+)
+
+// Thai and english content of email verification mail
+const (
+	EMAIL_VERIFICATION_SUBJECT_TH = "ยืนยันอีเมล Database Camp"
+	EMAIL_VERIFICATION_SUBJECT_EN = "Verify your Database Camp email"
+
+	EMAIL_VERIFICATION_BODY_TH = "สวัสดีคุณ %s\n\nขอบคุณที่สมัครสมาชิก Database Camp กรุณายืนยันอีเมลของคุณผ่านลิงก์ด้านล่างภายใน %d ชั่วโมง\n\n%s\n\nหากคุณไม่ได้สมัครสมาชิก สามารถเพิกเฉยต่ออีเมลฉบับนี้ได้"
+	EMAIL_VERIFICATION_BODY_EN = "Hello %s,\n\nThank you for signing up to Database Camp. Please verify your email with the link below within %d hours.\n\n%s\n\nIf you did not sign up, you can ignore this email."
+)
+
+// Thai and english content of account locked mail
+const (
+	ACCOUNT_LOCKED_SUBJECT_TH = "บัญชี Database Camp ถูกล็อก"
+	ACCOUNT_LOCKED_SUBJECT_EN = "Your Database Camp account is locked"
+
+	ACCOUNT_LOCKED_BODY_TH = "สวัสดีคุณ %s\n\nมีการพยายามเข้าสู่ระบบด้วยรหัสผ่านที่ไม่ถูกต้องหลายครั้ง บัญชีของคุณจึงถูกล็อกเป็นเวลา %d นาที หากเป็นคุณ สามารถปลดล็อกได้ทันทีผ่านลิงก์ด้านล่าง\n\n%s\n\nหากไม่ใช่คุณ แนะนำให้เปลี่ยนรหัสผ่าน"
+	ACCOUNT_LOCKED_BODY_EN = "Hello %s,\n\nThere were too many failed login attempts, so your account is locked for %d minutes. If this was you, you can unlock it right away with the link below.\n\n%s\n\nIf this was not you, we recommend changing your password."
+)
+
+// Thai and english content of account deletion mail
+const (
+	ACCOUNT_DELETION_SUBJECT_TH = "คำร้องขอลบบัญชี Database Camp"
+	ACCOUNT_DELETION_SUBJECT_EN = "Your Database Camp account will be deleted"
+
+	ACCOUNT_DELETION_BODY_TH = "สวัสดีคุณ %s\n\nเราได้รับคำร้องขอลบบัญชีของคุณ ข้อมูลทั้งหมดของคุณจะถูกลบอย่างถาวรในวันที่ %s\n\nหากคุณเปลี่ยนใจ เพียงเข้าสู่ระบบอีกครั้งก่อนวันดังกล่าว การลบบัญชีจะถูกยกเลิก"
+	ACCOUNT_DELETION_BODY_EN = "Hello %s,\n\nWe received a request to delete your account. All of your data will be permanently deleted on %s.\n\nIf you change your mind, simply log in again before then and the deletion will be cancelled."
+)
+
+func bilingualSubject(th string, en string) string {
+	return th + " / " + en
+}
+
+/**
+ * Create password reset mail
+ *
+ * @param to         	email address of the user
+ * @param name       	name of the user
+ * @param link       	link to the password reset page
+ * @param expiration	lifetime of the link
+ *
+ * @return mail
+ */
+func NewPasswordResetMail(to string, name string, link string, expiration time.Duration) Mail {
+	minutes := int(expiration.Minutes())
+	return Mail{
+		To:      to,
+		Subject: bilingualSubject(PASSWORD_RESET_SUBJECT_TH, PASSWORD_RESET_SUBJECT_EN),
+		Body: fmt.Sprintf(bilingualBody,
+			fmt.Sprintf(PASSWORD_RESET_BODY_TH, name, minutes, link),
+			fmt.Sprintf(PASSWORD_RESET_BODY_EN, name, minutes, link),
+		),
+	}
+}
+
+/**
+ * Create email verification mail
+ *
+ * @param to         	email address of the user
+ * @param name       	name of the user
+ * @param link       	link to the email verification page
+ * @param expiration	lifetime of the link
+ *
+ * @return mail
+ */
+func NewEmailVerificationMail(to string, name string, link string, expiration time.Duration) Mail {
+	hours := int(expiration.Hours())
+	return Mail{
+		To:      to,
+		Subject: bilingualSubject(EMAIL_VERIFICATION_SUBJECT_TH, EMAIL_VERIFICATION_SUBJECT_EN),
+		Body: fmt.Sprintf(bilingualBody,
+			fmt.Sprintf(EMAIL_VERIFICATION_BODY_TH, name, hours, link),
+			fmt.Sprintf(EMAIL_VERIFICATION_BODY_EN, name, hours, link),
+		),
+	}
+}
+
+/**
+ * Create account locked mail
+ *
+ * @param to         	email address of the user
+ * @param name       	name of the user
+ * @param link       	link to unlock the account
+ * @param duration		how long the account is locked
+ *
+ * @return mail
+ */
+func NewAccountLockedMail(to string, name string, link string, duration time.Duration) Mail {
+	minutes := int(duration.Minutes())
+	return Mail{
+		To:      to,
+		Subject: bilingualSubject(ACCOUNT_LOCKED_SUBJECT_TH, ACCOUNT_LOCKED_SUBJECT_EN),
+		Body: fmt.Sprintf(bilingualBody,
+			fmt.Sprintf(ACCOUNT_LOCKED_BODY_TH, name, minutes, link),
+			fmt.Sprintf(ACCOUNT_LOCKED_BODY_EN, name, minutes, link),
+		),
+	}
+}
+
+/**
+ * Create account deletion mail
+ *
+ * @param to         	email address of the user
+ * @param name       	name of the user
+ * @param deletionAt	time the account will be deleted
+ *
+ * @return mail
+ */
+func NewAccountDeletionMail(to string, name string, deletionAt time.Time) Mail {
+	date := deletionAt.Format("2006-01-02 15:04")
+	return Mail{
+		To:      to,
+		Subject: bilingualSubject(ACCOUNT_DELETION_SUBJECT_TH, ACCOUNT_DELETION_SUBJECT_EN),
+		Body: fmt.Sprintf(bilingualBody,
+			fmt.Sprintf(ACCOUNT_DELETION_BODY_TH, name, date),
+			fmt.Sprintf(ACCOUNT_DELETION_BODY_EN, name, date),
+		),
+	}
+}
@@ -0,0 +1,200 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+var ErrIDTokenInvalid = errors.New("oidc: id token invalid")
+
+type IDToken struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type jwk struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Curve   string `json:"crv"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+// keySet caches the signing keys of a provider. An unknown kid refreshes the
+// set, at most once a minute, to pick up rotated keys.
+type keySet struct {
+	url      string
+	provider *Provider
+
+	mutex       *sync.Mutex
+	keys        map[string]interface{}
+	refreshedAt time.Time
+}
+
+func newKeySet(url string, provider *Provider) *keySet {
+	return &keySet{url: url, provider: provider, mutex: &sync.Mutex{}}
+}
+
+func (s *keySet) get(kid string) (interface{}, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+
+	if time.Since(s.refreshedAt) < time.Minute {
+		return nil, fmt.Errorf("oidc: signing key %q not found", kid)
+	}
+
+	err := s.refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("oidc: signing key %q not found", kid)
+}
+
+func (s *keySet) refresh() error {
+	jwks := struct {
+		Keys []jwk `json:"keys"`
+	}{}
+
+	err := s.provider.getJSON(s.url, &jwks)
+	if err != nil {
+		return err
+	}
+
+	keys := map[string]interface{}{}
+	for _, k := range jwks.Keys {
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.KeyID] = key
+	}
+
+	s.keys = keys
+	s.refreshedAt = time.Now()
+
+	return nil
+}
+
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeSegment(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeSegment(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("oidc: unsupported curve %q", k.Curve)
+		}
+		x, err := decodeSegment(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeSegment(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	default:
+		return nil, fmt.Errorf("oidc: unsupported key type %q", k.KeyType)
+	}
+}
+
+// verify checks the signature, issuer, audience, lifetime and nonce of the ID
+// token as required by OpenID Connect Core 3.1.3.7.
+func (p *Provider) verify(rawIDToken string, nonce string) (*IDToken, error) {
+	discovery, err := p.getDiscovery()
+	if err != nil {
+		return nil, err
+	}
+
+	parser := jwt.Parser{ValidMethods: []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}}
+
+	token, err := parser.Parse(rawIDToken, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.keys.get(kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrIDTokenInvalid
+	}
+
+	now := time.Now().Unix()
+	valid := claims.VerifyExpiresAt(now, true) &&
+		claims.VerifyIssuer(discovery.Issuer, true) &&
+		claims.VerifyAudience(p.config.ClientID, true)
+
+	if tokenNonce, _ := claims["nonce"].(string); !valid || tokenNonce != nonce {
+		return nil, ErrIDTokenInvalid
+	}
+
+	idToken := IDToken{Issuer: discovery.Issuer}
+	idToken.Subject, _ = claims["sub"].(string)
+	idToken.Email, _ = claims["email"].(string)
+	idToken.Name, _ = claims["name"].(string)
+
+	// Some providers send email_verified as the string "true"
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		idToken.EmailVerified = verified
+	case string:
+		idToken.EmailVerified = verified == "true"
+	}
+
+	if idToken.Subject == "" {
+		return nil, ErrIDTokenInvalid
+	}
+
+	return &idToken, nil
+}
+
+func decodeSegment(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
@@ -0,0 +1,219 @@
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const GOOGLE_ISSUER = "https://accounts.google.com"
+
+var ErrProviderNotFound = errors.New("oidc: provider not found")
+
+type ProviderConfig struct {
+	Name         string   `json:"name"`
+	Issuer       string   `json:"issuer"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	RedirectURL  string   `json:"redirect_url"`
+	Scopes       []string `json:"scopes"`
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+// Provider is an OpenID Connect provider. Its discovery document is fetched on
+// first use, so the server still starts while a provider is unreachable.
+type Provider struct {
+	config ProviderConfig
+	client *http.Client
+
+	mutex     *sync.Mutex
+	discovery *discovery
+	keys      *keySet
+}
+
+type Providers map[string]*Provider
+
+// LoadProviders reads the providers from the file named by OIDC_PROVIDERS_PATH.
+// Without it Google is configured from GOOGLE_CLIENT_ID, GOOGLE_CLIENT_SECRET
+// and GOOGLE_REDIRECT_URL when the client id is set.
+func LoadProviders() (Providers, error) {
+	configs := make([]ProviderConfig, 0)
+
+	if path := os.Getenv("OIDC_PROVIDERS_PATH"); path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal(data, &configs)
+		if err != nil {
+			return nil, err
+		}
+	} else if clientID := os.Getenv("GOOGLE_CLIENT_ID"); clientID != "" {
+		configs = append(configs, ProviderConfig{
+			Name:         "google",
+			Issuer:       GOOGLE_ISSUER,
+			ClientID:     clientID,
+			ClientSecret: os.Getenv("GOOGLE_CLIENT_SECRET"),
+			RedirectURL:  os.Getenv("GOOGLE_REDIRECT_URL"),
+		})
+	}
+
+	providers := Providers{}
+
+	for _, config := range configs {
+		if config.Name == "" || config.Issuer == "" || config.ClientID == "" {
+			return nil, fmt.Errorf("oidc: provider %q needs a name, issuer and client id", config.Name)
+		}
+
+		if _, ok := providers[config.Name]; ok {
+			return nil, fmt.Errorf("oidc: duplicate provider %q", config.Name)
+		}
+
+		providers[config.Name] = NewProvider(config)
+	}
+
+	return providers, nil
+}
+
+func NewProvider(config ProviderConfig) *Provider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &Provider{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+		mutex:  &sync.Mutex{},
+	}
+}
+
+func (p Providers) Get(name string) (*Provider, error) {
+	provider, ok := p[name]
+	if !ok {
+		return nil, ErrProviderNotFound
+	}
+	return provider, nil
+}
+
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+// AuthCodeURL builds the authorization link, the code verifier is sent as an
+// S256 PKCE challenge.
+func (p *Provider) AuthCodeURL(state string, nonce string, codeVerifier string) (string, error) {
+	oauth2Config, err := p.oauth2Config()
+	if err != nil {
+		return "", err
+	}
+
+	return oauth2Config.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("nonce", nonce),
+		oauth2.SetAuthURLParam("code_challenge", codeChallenge(codeVerifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	), nil
+}
+
+// Exchange trades the authorization code for tokens and returns the verified
+// ID token.
+func (p *Provider) Exchange(code string, codeVerifier string, nonce string) (*IDToken, error) {
+	oauth2Config, err := p.oauth2Config()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, p.client)
+
+	token, err := oauth2Config.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", codeVerifier))
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("oidc: token response has no id_token")
+	}
+
+	return p.verify(rawIDToken, nonce)
+}
+
+func (p *Provider) oauth2Config() (*oauth2.Config, error) {
+	discovery, err := p.getDiscovery()
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  p.config.RedirectURL,
+		Scopes:       p.config.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  discovery.AuthorizationEndpoint,
+			TokenURL: discovery.TokenEndpoint,
+		},
+	}, nil
+}
+
+func (p *Provider) getDiscovery() (*discovery, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	url := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+
+	d := discovery{}
+	err := p.getJSON(url, &d)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("oidc: provider %q reports issuer %q", p.config.Name, d.Issuer)
+	}
+
+	p.discovery = &d
+	p.keys = newKeySet(d.JwksURI, p)
+
+	return p.discovery, nil
+}
+
+func (p *Provider) getJSON(url string, v interface{}) error {
+	resp, err := p.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc: GET %s returned %s", url, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func codeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
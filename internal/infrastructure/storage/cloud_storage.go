@@ -1,15 +1,21 @@
 package storage
 
 import (
+	"context"
 	"io/ioutil"
+	"mime"
 	"net/http"
+	"net/url"
 	"os"
 	"time"
 
 	"cloud.google.com/go/storage"
 	"golang.org/x/oauth2/google"
+	"google.golang.org/api/option"
 )
 
+const serviceAccountPath = "service_account.json"
+
 type cloudStorageService struct {
 	bucketName string
 }
@@ -30,7 +36,22 @@ func initGoogleCloudStorageService() *cloudStorageService {
 }
 
 func (c cloudStorageService) GetFileLink(objectName string) (string, error) {
-	jsonKey, err := ioutil.ReadFile("service_account.json")
+	return c.signURL(objectName, nil)
+}
+
+// GetDownloadLink signs a link that makes the browser save the object as
+// fileName instead of opening it.
+func (c cloudStorageService) GetDownloadLink(objectName string, fileName string) (string, error) {
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": fileName})
+	if disposition == "" {
+		disposition = "attachment"
+	}
+
+	return c.signURL(objectName, url.Values{"response-content-disposition": {disposition}})
+}
+
+func (c cloudStorageService) signURL(objectName string, queryParameters url.Values) (string, error) {
+	jsonKey, err := ioutil.ReadFile(serviceAccountPath)
 	if err != nil {
 		return "", err
 	}
@@ -41,14 +62,56 @@ func (c cloudStorageService) GetFileLink(objectName string) (string, error) {
 	}
 
 	opts := &storage.SignedURLOptions{
-		Scheme:         storage.SigningSchemeV4,
-		Method:         http.MethodGet,
-		GoogleAccessID: conf.Email,
-		PrivateKey:     conf.PrivateKey,
-		Expires:        time.Now().Add(15 * time.Minute),
+		Scheme:          storage.SigningSchemeV4,
+		Method:          http.MethodGet,
+		GoogleAccessID:  conf.Email,
+		PrivateKey:      conf.PrivateKey,
+		Expires:         time.Now().Add(15 * time.Minute),
+		QueryParameters: queryParameters,
+	}
+
+	return storage.SignedURL(c.bucketName, objectName, opts)
+}
+
+func (c cloudStorageService) Upload(objectName string, contentType string, data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := storage.NewClient(ctx, option.WithCredentialsFile(serviceAccountPath))
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	writer := client.Bucket(c.bucketName).Object(objectName).NewWriter(ctx)
+	writer.ContentType = contentType
+
+	_, err = writer.Write(data)
+	if err != nil {
+		writer.Close()
+		return err
+	}
+
+	return writer.Close()
+}
+
+// Delete ignores objects that do not exist.
+func (c cloudStorageService) Delete(objectNames ...string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client, err := storage.NewClient(ctx, option.WithCredentialsFile(serviceAccountPath))
+	if err != nil {
+		return err
 	}
+	defer client.Close()
 
-	url, err := storage.SignedURL(c.bucketName, objectName, opts)
+	for _, objectName := range objectNames {
+		err = client.Bucket(c.bucketName).Object(objectName).Delete(ctx)
+		if err != nil && err != storage.ErrObjectNotExist {
+			return err
+		}
+	}
 
-	return url, err
+	return nil
 }
@@ -3,10 +3,14 @@ package jwt
 import (
 	"database-camp/internal/errs"
 	"database-camp/internal/infrastructure/application"
+	"database-camp/internal/infrastructure/cache"
 	"database-camp/internal/logs"
+	"database-camp/internal/models/entities/user"
+	"database-camp/internal/models/request"
 	"database-camp/internal/repositories"
 	"database-camp/internal/utils"
-	"fmt"
+	"errors"
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -15,127 +19,278 @@ import (
 	uuid "github.com/satori/go.uuid"
 )
 
+const (
+	defaultAccessTokenExpireMinute = 15
+	defaultSessionExpireHour       = 24 * 30
+	defaultIssuer                  = "database-camp"
+	defaultAudience                = "database-camp-api"
+)
+
+type Token struct {
+	SessionID    int
+	AccessToken  string
+	RefreshToken string
+}
+
 type Jwt interface {
-	Sign(id int) (string, error)
+	Sign(userID int, client request.Client) (*Token, error)
+	Refresh(refreshToken string) (*Token, error)
 	Verify(application.Context)
+	VerifyOptional(application.Context)
+	RequireRole(roles ...user.Role) func(application.Context)
+	JWKS(application.Context)
+	SignEmailVerification(userID int, email string, expiration time.Duration) (string, error)
+	ParseEmailVerification(verificationToken string) (*EmailVerification, error)
 }
+
 type jwtMiddleware struct {
-	repo repositories.UserRepository
+	sessionRepo repositories.SessionRepository
+	userRepo    repositories.UserRepository
+	keyring     *Keyring
 }
 
-func New(repo repositories.UserRepository) jwtMiddleware {
-	return jwtMiddleware{repo: repo}
+func New(sessionRepo repositories.SessionRepository, userRepo repositories.UserRepository, keyring *Keyring) jwtMiddleware {
+	return jwtMiddleware{sessionRepo: sessionRepo, userRepo: userRepo, keyring: keyring}
 }
 
-func (j jwtMiddleware) Sign(id int) (string, error) {
-	atClaims := jwt.MapClaims{
-		"id":     id,
-		"secret": uuid.NewV4(),
+func (j jwtMiddleware) Sign(userID int, client request.Client) (*Token, error) {
+	refreshToken, err := utils.RandomToken()
+	if err != nil {
+		return nil, err
 	}
 
-	at := jwt.NewWithClaims(jwt.SigningMethodHS256, atClaims)
+	session, err := j.sessionRepo.InsertSession(user.Session{
+		UserID:           userID,
+		UserAgent:        client.UserAgent,
+		IPAddress:        client.IPAddress,
+		CreatedTimestamp: time.Now().Local(),
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	token, err := at.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	err = j.sessionRepo.ActivateSession(userID, session.ID, sessionExpire())
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	err = j.updateToken(id, token)
+	ref := user.SessionRef{UserID: userID, SessionID: session.ID}
+
+	err = j.sessionRepo.SetRefreshToken(utils.HashToken(refreshToken), ref, sessionExpire())
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return token, nil
+	err = j.sessionRepo.DeleteInactiveSessions(userID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+	}
+
+	accessToken, err := j.signAccessToken(userID, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Token{
+		SessionID:    session.ID,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// Refresh consumes the refresh token, so a token can only ever be exchanged once.
+// The roles are read again for the new access token, a change of roles revokes
+// the sessions of the user instead of waiting for a refresh.
+func (j jwtMiddleware) Refresh(refreshToken string) (*Token, error) {
+	// A token that expired or was used already is gone, only an unreachable
+	// cache is an error
+	ref, err := j.sessionRepo.PopRefreshToken(utils.HashToken(refreshToken))
+	if errors.Is(err, cache.Nil) {
+		return nil, errs.ErrRefreshTokenInvalid
+	} else if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrServiceUnavailableError
+	}
+
+	active, err := j.sessionRepo.TouchSession(ref.UserID, ref.SessionID, sessionExpire())
+	if err != nil {
+		return nil, err
+	}
+
+	if !active {
+		return nil, errs.ErrRefreshTokenInvalid
+	}
+
+	newRefreshToken, err := utils.RandomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	err = j.sessionRepo.SetRefreshToken(utils.HashToken(newRefreshToken), *ref, sessionExpire())
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := j.signAccessToken(ref.UserID, ref.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Token{
+		SessionID:    ref.SessionID,
+		AccessToken:  accessToken,
+		RefreshToken: newRefreshToken,
+	}, nil
 }
 
 func (j jwtMiddleware) Verify(c application.Context) {
-	bearer, err := j.jwtFromHeader(c)
+	err := j.authenticate(c)
 	if err != nil {
-		logs.GetInstance().Error(err)
 		c.Error(err)
 		return
 	}
 
-	token, err := jwt.Parse(bearer, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			EnMessage := fmt.Sprintf("unexpected signing method: %v", token.Header["alg"])
-			ThMessage := fmt.Sprintf("วิธีการลงนามที่ไม่คาดคิด: %v", token.Header["alg"])
-			return nil, c.Error(errs.NewForbiddenError(ThMessage, EnMessage))
-		}
+	c.Next()
+}
 
-		return []byte(os.Getenv("JWT_SECRET")), nil
-	})
+// VerifyOptional lets requests without a token through anonymously, a token
+// that is sent must still be valid.
+func (j jwtMiddleware) VerifyOptional(c application.Context) {
+	if c.GetHeader("Authorization") == "" {
+		c.Next()
+		return
+	}
+
+	j.Verify(c)
+}
+
+// authenticate checks the bearer token and its session, and puts the claims in
+// Locals.
+func (j jwtMiddleware) authenticate(c application.Context) error {
+	bearer, err := j.jwtFromHeader(c)
 	if err != nil {
 		logs.GetInstance().Error(err)
-		c.Error(errs.NewForbiddenError("โทเค็นไม่ถูกต้อง", "Token Invalid"))
-		return
+		return err
 	}
 
-	claims, err := j.getClaims(token)
+	parser := jwt.Parser{ValidMethods: j.keyring.Algorithms()}
+
+	token, err := parser.Parse(bearer, j.keyring.Keyfunc)
 	if err != nil {
 		logs.GetInstance().Error(err)
-		c.Error(err)
-		return
+		return errs.ErrTokenInvalid
 	}
 
-	id := utils.ParseInt(claims["id"])
+	claims, err := j.getClaims(token)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return err
+	}
 
-	if !j.validUser(bearer, id) {
-		c.Error(errs.NewForbiddenError("โทเค็นไม่ถูกต้อง", "Token Invalid"))
-		return
+	if !j.validClaims(claims) {
+		return errs.ErrTokenInvalid
 	}
 
-	err = j.updateToken(id, bearer)
+	userID := utils.ParseInt(claims["id"])
+	sessionID := utils.ParseInt(claims["sid"])
+
+	active, err := j.sessionRepo.TouchSession(userID, sessionID, sessionExpire())
 	if err != nil {
 		logs.GetInstance().Error(err)
-		c.Error(errs.NewInternalServerError("เกิดข้อผิดพลาด", "Internal Server Error"))
-		return
+		return errs.ErrServiceUnavailableError
+	}
+
+	if !active {
+		return errs.ErrTokenInvalid
 	}
 
 	j.setClaims(c, claims)
-	c.Next()
+
+	return nil
 }
 
-func (j jwtMiddleware) updateToken(id int, token string) error {
-	tokenExpireHour := time.Hour * utils.ParseDuration(os.Getenv("TOKEN_EXPIRE_HOUR"))
-	expiredTokenTimestamp := time.Now().Local().Add(tokenExpireHour)
-	err := j.repo.UpdatesByID(id, map[string]interface{}{
-		"access_token":            token,
-		"expired_token_timestamp": expiredTokenTimestamp,
-	})
-	return err
+// RequireRole lets the request through when the user holds any of the roles,
+// it must run after Verify so the roles claim is already in Locals.
+func (j jwtMiddleware) RequireRole(roles ...user.Role) func(application.Context) {
+	return func(c application.Context) {
+		userRoles, _ := c.Locals("roles").([]string)
+
+		for _, userRole := range userRoles {
+			for _, role := range roles {
+				if userRole == string(role) {
+					c.Next()
+					return
+				}
+			}
+		}
+
+		c.Error(errs.ErrPermissionDenied)
+	}
+}
+
+func (j jwtMiddleware) JWKS(c application.Context) {
+	c.JSON(http.StatusOK, j.keyring.JWKS())
+}
+
+func (j jwtMiddleware) signAccessToken(userID int, sessionID int) (string, error) {
+	roles, err := j.userRepo.GetUserRoles(userID)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().Local()
+	key := j.keyring.Active()
+
+	atClaims := jwt.MapClaims{
+		"iss":   issuer(),
+		"aud":   audience(),
+		"sub":   utils.ParseString(userID),
+		"iat":   now.Unix(),
+		"nbf":   now.Unix(),
+		"exp":   now.Add(accessTokenExpire()).Unix(),
+		"jti":   uuid.NewV4().String(),
+		"id":    userID,
+		"sid":   sessionID,
+		"roles": roles,
+	}
+
+	at := jwt.NewWithClaims(key.Method, atClaims)
+	at.Header["kid"] = key.ID
+
+	return at.SignedString(key.SignKey)
 }
 
 func (j jwtMiddleware) getClaims(token *jwt.Token) (jwt.MapClaims, error) {
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return claims, errs.NewForbiddenError("โทเค็นไม่ถูกต้อง", "Token Invalid")
+		return claims, errs.ErrTokenInvalid
 	} else {
 		return claims, nil
 	}
 }
 
+func (j jwtMiddleware) validClaims(claims jwt.MapClaims) bool {
+	now := time.Now().Local().Unix()
+	return claims.VerifyExpiresAt(now, true) &&
+		claims.VerifyIssuedAt(now, true) &&
+		claims.VerifyIssuer(issuer(), true) &&
+		claims.VerifyAudience(audience(), true)
+}
+
 func (j jwtMiddleware) setClaims(c application.Context, claims jwt.MapClaims) {
 	for k, v := range claims {
-		if k != "secret" {
+		if values, ok := v.([]interface{}); ok {
+			items := make([]string, 0, len(values))
+			for _, value := range values {
+				items = append(items, utils.ParseString(value))
+			}
+			c.Locals(k, items)
+		} else {
 			c.Locals(k, utils.ParseString(v))
 		}
 	}
 }
 
-func (j jwtMiddleware) validUser(token string, id int) bool {
-	user, err := j.repo.GetUserByID(id)
-	if err != nil || user == nil {
-		return false
-	}
-
-	if user.AccessToken != token || user.ExpiredTokenTimestamp.Before(time.Now().Local()) {
-		return false
-	}
-
-	return true
-}
-
 func (j jwtMiddleware) jwtFromHeader(c application.Context) (string, error) {
 	auth := c.GetHeader("Authorization")
 	l := len("Bearer")
@@ -144,3 +299,33 @@ func (j jwtMiddleware) jwtFromHeader(c application.Context) (string, error) {
 	}
 	return "", errs.NewBadRequestError("ไม่พบ JWT Token ในส่วนหัวของคำร้องขอ", "JWT Token Not found")
 }
+
+func accessTokenExpire() time.Duration {
+	minute := utils.ParseDuration(os.Getenv("ACCESS_TOKEN_EXPIRE_MINUTE"))
+	if minute <= 0 {
+		minute = defaultAccessTokenExpireMinute
+	}
+	return time.Minute * minute
+}
+
+func sessionExpire() time.Duration {
+	hour := utils.ParseDuration(os.Getenv("TOKEN_EXPIRE_HOUR"))
+	if hour <= 0 {
+		hour = defaultSessionExpireHour
+	}
+	return time.Hour * hour
+}
+
+func issuer() string {
+	if iss := os.Getenv("JWT_ISSUER"); iss != "" {
+		return iss
+	}
+	return defaultIssuer
+}
+
+func audience() string {
+	if aud := os.Getenv("JWT_AUDIENCE"); aud != "" {
+		return aud
+	}
+	return defaultAudience
+}
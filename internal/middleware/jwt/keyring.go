@@ -0,0 +1,283 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"database-camp/internal/errs"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const DEFAULT_KEY_ID = "default"
+
+type keyConfig struct {
+	ID             string `json:"kid"`
+	Algorithm      string `json:"alg"`
+	Secret         string `json:"secret"`
+	PrivateKeyPath string `json:"private_key_path"`
+	PublicKeyPath  string `json:"public_key_path"`
+}
+
+type keyringConfig struct {
+	Active string      `json:"active"`
+	Keys   []keyConfig `json:"keys"`
+}
+
+type Key struct {
+	ID        string
+	Method    jwt.SigningMethod
+	SignKey   interface{}
+	VerifyKey interface{}
+}
+
+type Keyring struct {
+	active *Key
+	keys   map[string]*Key
+}
+
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// LoadKeyring reads the keyring file named by JWT_KEYRING_PATH. Without it a
+// single HS256 key is built from JWT_SECRET.
+func LoadKeyring() (*Keyring, error) {
+	path := os.Getenv("JWT_KEYRING_PATH")
+	if path == "" {
+		return newKeyring(keyringConfig{
+			Active: DEFAULT_KEY_ID,
+			Keys: []keyConfig{
+				{ID: DEFAULT_KEY_ID, Algorithm: jwt.SigningMethodHS256.Alg(), Secret: os.Getenv("JWT_SECRET")},
+			},
+		})
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := keyringConfig{}
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return newKeyring(config)
+}
+
+func newKeyring(config keyringConfig) (*Keyring, error) {
+	keyring := Keyring{keys: map[string]*Key{}}
+
+	for _, keyConfig := range config.Keys {
+		if _, ok := keyring.keys[keyConfig.ID]; ok {
+			return nil, fmt.Errorf("jwt: duplicate key id %q", keyConfig.ID)
+		}
+
+		key, err := parseKey(keyConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		keyring.keys[key.ID] = key
+	}
+
+	active, ok := keyring.keys[config.Active]
+	if !ok {
+		return nil, fmt.Errorf("jwt: active key %q not found", config.Active)
+	}
+
+	if active.SignKey == nil {
+		return nil, fmt.Errorf("jwt: active key %q cannot sign", config.Active)
+	}
+
+	keyring.active = active
+
+	return &keyring, nil
+}
+
+func (k *Keyring) Active() *Key {
+	return k.active
+}
+
+func (k *Keyring) Algorithms() []string {
+	algorithms := make([]string, 0, len(k.keys))
+	for _, key := range k.keys {
+		algorithms = append(algorithms, key.Method.Alg())
+	}
+	return algorithms
+}
+
+func (k *Keyring) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, errs.ErrTokenInvalid
+	}
+
+	if key.Method.Alg() != token.Method.Alg() {
+		return nil, errs.ErrUnExpectedsigningMethod
+	}
+
+	return key.VerifyKey, nil
+}
+
+func (k *Keyring) JWKS() JWKS {
+	jwks := JWKS{Keys: make([]JWK, 0)}
+
+	for _, key := range k.keys {
+		jwk, ok := key.toJWK()
+		if ok {
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+	}
+
+	return jwks
+}
+
+// toJWK only exports asymmetric keys, HMAC secrets must never leave the server.
+func (key Key) toJWK() (JWK, bool) {
+	jwk := JWK{
+		KeyID:     key.ID,
+		Use:       "sig",
+		Algorithm: key.Method.Alg(),
+	}
+
+	switch publicKey := key.VerifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = encodeSegment(publicKey.N.Bytes())
+		jwk.E = encodeSegment(big.NewInt(int64(publicKey.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		jwk.KeyType = "EC"
+		jwk.Curve = publicKey.Curve.Params().Name
+		jwk.X = encodeSegment(publicKey.X.FillBytes(make([]byte, size)))
+		jwk.Y = encodeSegment(publicKey.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = encodeSegment(publicKey)
+	default:
+		return jwk, false
+	}
+
+	return jwk, true
+}
+
+func parseKey(config keyConfig) (*Key, error) {
+	if config.ID == "" {
+		return nil, errors.New("jwt: key id is required")
+	}
+
+	method := jwt.GetSigningMethod(config.Algorithm)
+	if method == nil {
+		return nil, fmt.Errorf("jwt: key %q has unsupported algorithm %q", config.ID, config.Algorithm)
+	}
+
+	key := Key{ID: config.ID, Method: method}
+
+	var err error
+
+	switch method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if config.Secret == "" {
+			return nil, fmt.Errorf("jwt: key %q has no secret", config.ID)
+		}
+		key.SignKey = []byte(config.Secret)
+		key.VerifyKey = []byte(config.Secret)
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		err = key.loadPEM(config,
+			func(b []byte) (interface{}, error) { return jwt.ParseRSAPrivateKeyFromPEM(b) },
+			func(b []byte) (interface{}, error) { return jwt.ParseRSAPublicKeyFromPEM(b) },
+		)
+	case *jwt.SigningMethodECDSA:
+		err = key.loadPEM(config,
+			func(b []byte) (interface{}, error) { return jwt.ParseECPrivateKeyFromPEM(b) },
+			func(b []byte) (interface{}, error) { return jwt.ParseECPublicKeyFromPEM(b) },
+		)
+	case *jwt.SigningMethodEd25519:
+		err = key.loadPEM(config,
+			func(b []byte) (interface{}, error) { return jwt.ParseEdPrivateKeyFromPEM(b) },
+			func(b []byte) (interface{}, error) { return jwt.ParseEdPublicKeyFromPEM(b) },
+		)
+	default:
+		err = fmt.Errorf("jwt: key %q has unsupported algorithm %q", config.ID, config.Algorithm)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// loadPEM sets the key pair from PEM files. A key with only a public key can
+// still verify tokens that were signed before it was rotated out.
+func (key *Key) loadPEM(
+	config keyConfig,
+	parsePrivate func([]byte) (interface{}, error),
+	parsePublic func([]byte) (interface{}, error),
+) error {
+	if config.PrivateKeyPath != "" {
+		data, err := ioutil.ReadFile(config.PrivateKeyPath)
+		if err != nil {
+			return err
+		}
+
+		privateKey, err := parsePrivate(data)
+		if err != nil {
+			return err
+		}
+
+		key.SignKey = privateKey
+
+		if signer, ok := privateKey.(crypto.Signer); ok {
+			key.VerifyKey = signer.Public()
+		}
+	}
+
+	if config.PublicKeyPath != "" {
+		data, err := ioutil.ReadFile(config.PublicKeyPath)
+		if err != nil {
+			return err
+		}
+
+		key.VerifyKey, err = parsePublic(data)
+		if err != nil {
+			return err
+		}
+	}
+
+	if key.VerifyKey == nil {
+		return fmt.Errorf("jwt: key %q has no private or public key", config.ID)
+	}
+
+	return nil
+}
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
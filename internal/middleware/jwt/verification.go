@@ -0,0 +1,69 @@
+package jwt
+
+import (
+	"database-camp/internal/errs"
+	"database-camp/internal/utils"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+type EmailVerification struct {
+	UserID int
+	Email  string
+}
+
+// SignEmailVerification signs a token for the verification link. The token is
+// bound to the email so changing the email voids links that were already sent,
+// and its audience keeps it from being accepted as an access token.
+func (j jwtMiddleware) SignEmailVerification(userID int, email string, expiration time.Duration) (string, error) {
+	now := time.Now().Local()
+	key := j.keyring.Active()
+
+	claims := jwt.MapClaims{
+		"iss":   issuer(),
+		"aud":   emailVerificationAudience(),
+		"sub":   utils.ParseString(userID),
+		"iat":   now.Unix(),
+		"exp":   now.Add(expiration).Unix(),
+		"email": email,
+	}
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.SignKey)
+}
+
+func (j jwtMiddleware) ParseEmailVerification(verificationToken string) (*EmailVerification, error) {
+	parser := jwt.Parser{ValidMethods: j.keyring.Algorithms()}
+
+	token, err := parser.Parse(verificationToken, j.keyring.Keyfunc)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := j.getClaims(token)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Local().Unix()
+	valid := claims.VerifyExpiresAt(now, true) &&
+		claims.VerifyIssuedAt(now, true) &&
+		claims.VerifyIssuer(issuer(), true) &&
+		claims.VerifyAudience(emailVerificationAudience(), true)
+
+	email, _ := claims["email"].(string)
+	userID := utils.ParseInt(claims["sub"])
+
+	if !valid || email == "" || userID == 0 {
+		return nil, errs.ErrTokenInvalid
+	}
+
+	return &EmailVerification{UserID: userID, Email: email}, nil
+}
+
+func emailVerificationAudience() string {
+	return audience() + "/email-verification"
+}
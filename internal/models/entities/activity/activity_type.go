@@ -0,0 +1,227 @@
+package activity
+
+import (
+	"database-camp/internal/errs"
+	"math"
+	"sync"
+)
+
+// activity_type_id of the built-in activity types
+const (
+	TYPE_MATCHING    = 1
+	TYPE_MULTIPLE    = 2
+	TYPE_COMPLETION  = 3
+	TYPE_VOCAB_GROUP = 4
+	TYPE_DEPENDENCY  = 5
+	TYPE_ER          = 6
+	TYPE_PEER_REVIEW = 7
+)
+
+// ActivityType describes how the activities of one activity_type_id are
+// authored, answered and graded. The choices are loaded by the loader
+// registered with repositories.RegisterChoiceLoader, stored by the writer
+// registered with repositories.RegisterChoiceWriter for types that are not
+// built in, and shown to learners through their CreatePropositionChoices.
+type ActivityType struct {
+	ID   int
+	Name string
+
+	// DecodeAnswer decodes the answer sent by a learner, nil if activities of
+	// the type are not answered through CheckAnswer
+	DecodeAnswer func(answer interface{}) (Answer, error)
+
+	// ParseChoices decodes and checks the choices of an authored activity,
+	// nil if activities of the type have no choices of their own
+	ParseChoices func(choices interface{}) (interface{}, error)
+
+	// AuthoredChoices turns loaded choices into the structure ParseChoices
+	// returns, nil if they are the same
+	AuthoredChoices func(choices Choices) interface{}
+}
+
+// Grade is the outcome of checking an answer. The score is the fraction of
+// the answer that is right, 1 when it is correct, and the items tell which
+// parts were right. The message tells the learner what is wrong when the type
+// can explain it and the feedback carries any structured detail the type
+// wants to show with it
+type Grade struct {
+	IsCorrect bool
+	Score     float64
+	Items     []ItemResult
+	Message   *string
+	Feedback  interface{}
+}
+
+// ItemResult tells whether one part of an answer, a matching pair, a blank,
+// a placed vocab or a dependency, is right
+type ItemResult struct {
+	Item      string `json:"item"`
+	IsCorrect bool   `json:"is_correct"`
+}
+
+/**
+ * Create the grade of an answer made of items, the score is the fraction of
+ * the expected items answered right
+ *
+ * @param items 	result of every item of the answer
+ * @param correct 	number of items answered right
+ * @param expected 	number of items the answer should have
+ *
+ * @return the grade, correct only when every expected item is right and no
+ * item is wrong
+ */
+func NewItemsGrade(items []ItemResult, correct int, expected int) *Grade {
+	score := 1.0
+	if expected > 0 {
+		score = math.Min(float64(correct)/float64(expected), 1)
+	}
+
+	isCorrect := correct >= expected
+	for _, item := range items {
+		if !item.IsCorrect {
+			isCorrect = false
+		}
+	}
+
+	if isCorrect {
+		score = 1
+	}
+
+	return &Grade{IsCorrect: isCorrect, Score: score, Items: items}
+}
+
+/**
+ * Points earned by a graded answer
+ *
+ * @param point 	point of the activity
+ *
+ * @return the point scaled by the score
+ */
+func (grade Grade) Point(point int) int {
+	if grade.IsCorrect {
+		return point
+	}
+
+	return int(math.Round(float64(point) * grade.Score))
+}
+
+var (
+	activityTypes      = map[int]ActivityType{}
+	activityTypesMutex sync.RWMutex
+)
+
+/**
+ * Register an activity type, a type registered again replaces the old one
+ *
+ * @param activityType 	type to register
+ */
+func RegisterType(activityType ActivityType) {
+	activityTypesMutex.Lock()
+	defer activityTypesMutex.Unlock()
+
+	activityTypes[activityType.ID] = activityType
+}
+
+/**
+ * Get a registered activity type
+ *
+ * @param activityTypeID 	activity_type_id of the type
+ *
+ * @return the type and ErrActivityTypeInvalid if it is not registered
+ */
+func GetType(activityTypeID int) (ActivityType, error) {
+	activityTypesMutex.RLock()
+	defer activityTypesMutex.RUnlock()
+
+	activityType, ok := activityTypes[activityTypeID]
+	if !ok {
+		return ActivityType{}, errs.ErrActivityTypeInvalid
+	}
+
+	return activityType, nil
+}
+
+/**
+ * Decode the answer of a learner into the structure of the activity type
+ *
+ * @param answer 			answer sent by the learner
+ * @param activityTypeID 	activity_type_id of the activity
+ *
+ * @return the answer and ErrActivityTypeInvalid if the type is not answered
+ */
+func FormatAnswer(answer interface{}, activityTypeID int) (Answer, error) {
+	activityType, err := GetType(activityTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if activityType.DecodeAnswer == nil {
+		return nil, errs.ErrActivityTypeInvalid
+	}
+
+	return activityType.DecodeAnswer(answer)
+}
+
+/**
+ * Decode and check the answer of a learner
+ *
+ * @param answer 			answer sent by the learner
+ * @param activityTypeID 	activity_type_id of the activity
+ * @param choices 			choices of the activity
+ *
+ * @return the grade of the answer
+ */
+func GradeAnswer(answer interface{}, activityTypeID int, choices Choices) (*Grade, error) {
+	formatedAnswer, err := FormatAnswer(answer, activityTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return formatedAnswer.Grade(choices)
+}
+
+/**
+ * Decode the choices of an authored activity
+ *
+ * @param choices 			choices sent by the author
+ * @param activityTypeID 	activity_type_id of the activity
+ *
+ * @return the choices in the structure of the type, nil if the type has none
+ */
+func ParseChoices(choices interface{}, activityTypeID int) (interface{}, error) {
+	activityType, err := GetType(activityTypeID)
+	if err != nil {
+		return nil, errs.ErrActivityTypeUnknown
+	}
+
+	if activityType.ParseChoices == nil {
+		return nil, nil
+	} else if choices == nil {
+		return nil, errs.ErrChoicesNotFound
+	}
+
+	return activityType.ParseChoices(choices)
+}
+
+// HasChoices reports whether activities of the type are authored with choices
+func HasChoices(activityTypeID int) bool {
+	activityType, err := GetType(activityTypeID)
+	return err == nil && activityType.ParseChoices != nil
+}
+
+/**
+ * Turn loaded choices into the structure they are authored in
+ *
+ * @param choices 			choices loaded from the database
+ * @param activityTypeID 	activity_type_id of the activity
+ *
+ * @return the authored choices
+ */
+func AuthoredChoices(choices Choices, activityTypeID int) interface{} {
+	activityType, err := GetType(activityTypeID)
+	if err != nil || activityType.AuthoredChoices == nil {
+		return choices
+	}
+
+	return activityType.AuthoredChoices(choices)
+}
@@ -2,38 +2,13 @@ package activity
 
 import (
 	"database-camp/internal/errs"
-	"database-camp/internal/utils"
+	"strconv"
+	"strings"
 )
 
-func FormatAnswer(answer interface{}, activityTypeID int) (Answer, error) {
-	switch activityTypeID {
-	case 1:
-		var matchingChoiceAnswer MatchingChoiceAnswer
-		err := utils.StructToStruct(answer, &matchingChoiceAnswer)
-		return matchingChoiceAnswer, err
-	case 2:
-		var multipleChoiceAnswer MultipleChoiceAnswer
-		err := utils.StructToStruct(answer, &multipleChoiceAnswer)
-		return multipleChoiceAnswer, err
-	case 3:
-		var completionChoiceAnswer CompletionChoiceAnswer
-		err := utils.StructToStruct(answer, &completionChoiceAnswer)
-		return completionChoiceAnswer, err
-	case 4:
-		var vocabGroupChoiceAnswer VocabGroupChoiceAnswer
-		err := utils.StructToStruct(answer, &vocabGroupChoiceAnswer)
-		return vocabGroupChoiceAnswer, err
-	case 5:
-		var dependencyChoiceAnswer DependencyChoiceAnswer
-		err := utils.StructToStruct(answer, &dependencyChoiceAnswer)
-		return dependencyChoiceAnswer, err
-	default:
-		return nil, errs.ErrActivityTypeInvalid
-	}
-}
-
+// Answer is the answer of a learner decoded for its activity type
 type Answer interface {
-	IsCorrect(choices Choices) (bool, error)
+	Grade(choices Choices) (*Grade, error)
 }
 
 type MatchingItem struct {
@@ -43,10 +18,11 @@ type MatchingItem struct {
 
 type MatchingChoiceAnswer []MatchingItem
 
-func (answer MatchingChoiceAnswer) IsCorrect(choices Choices) (bool, error) {
+// Grade checks every pair, the score is the fraction of the pairs matched
+func (answer MatchingChoiceAnswer) Grade(choices Choices) (*Grade, error) {
 	matchingChoices, ok := choices.(MatchingChoices)
 	if !ok {
-		return false, errs.ErrAnswerInvalid
+		return nil, errs.ErrAnswerInvalid
 	}
 
 	Item1Item2Map := map[string]string{}
@@ -54,51 +30,73 @@ func (answer MatchingChoiceAnswer) IsCorrect(choices Choices) (bool, error) {
 		Item1Item2Map[correct.PairItem1] = correct.PairItem2
 	}
 
+	items := make([]ItemResult, 0, len(answer))
+	matched := map[string]bool{}
 	for _, item := range answer {
-		if Item1Item2Map[item.Item1] != item.Item2 && Item1Item2Map[item.Item2] != item.Item1 {
-			return false, nil
+		isCorrect := Item1Item2Map[item.Item1] == item.Item2 || Item1Item2Map[item.Item2] == item.Item1
+		if isCorrect {
+			if Item1Item2Map[item.Item1] == item.Item2 {
+				matched[item.Item1] = true
+			} else {
+				matched[item.Item2] = true
+			}
 		}
+
+		items = append(items, ItemResult{Item: item.Item1 + " - " + item.Item2, IsCorrect: isCorrect})
 	}
 
-	return true, nil
+	return NewItemsGrade(items, len(matched), len(matchingChoices)), nil
 }
 
 type MultipleChoiceAnswer []int
 
-func (answer MultipleChoiceAnswer) IsCorrect(choices Choices) (bool, error) {
+// Grade checks every selected choice, a wrong selection cancels a right one
+func (answer MultipleChoiceAnswer) Grade(choices Choices) (*Grade, error) {
 	multipleChoices, ok := choices.(MultipleChoices)
 	if !ok {
-		return false, errs.ErrAnswerInvalid
+		return nil, errs.ErrAnswerInvalid
 	}
 
 	countCorrect := 0
 
-	solution := map[int]bool{}
+	solution := map[int]MultipleChoice{}
 	for _, choice := range multipleChoices {
-		solution[choice.ID] = choice.IsCorrect
+		solution[choice.ID] = choice
 
 		if choice.IsCorrect {
 			countCorrect++
 		}
 	}
 
-	if countCorrect != len(answer) {
-		return false, nil
-	}
-
+	items := make([]ItemResult, 0, len(answer))
+	selected := map[int]bool{}
+	right, wrong := 0, 0
 	for _, v := range answer {
+		if selected[v] {
+			continue
+		}
+		selected[v] = true
 
-		if _, ok := solution[v]; !ok {
-			return false, nil
+		choice, ok := solution[v]
+		if ok && choice.IsCorrect {
+			right++
+		} else {
+			wrong++
 		}
 
-		if !solution[v] {
-			return false, nil
+		item := choice.Content
+		if !ok {
+			item = strconv.Itoa(v)
 		}
 
+		items = append(items, ItemResult{Item: item, IsCorrect: ok && choice.IsCorrect})
 	}
 
-	return true, nil
+	if right < wrong {
+		right, wrong = 0, 0
+	}
+
+	return NewItemsGrade(items, right-wrong, countCorrect), nil
 }
 
 type completionItem struct {
@@ -108,37 +106,51 @@ type completionItem struct {
 
 type CompletionChoiceAnswer []completionItem
 
-func (answer CompletionChoiceAnswer) IsCorrect(choices Choices) (bool, error) {
+// Grade checks every blank, a blank left empty is wrong
+func (answer CompletionChoiceAnswer) Grade(choices Choices) (*Grade, error) {
 	completionChoices, ok := choices.(CompletionChoices)
 	if !ok {
-		return false, errs.ErrAnswerInvalid
+		return nil, errs.ErrAnswerInvalid
 	}
 
+	contents := map[int]string{}
+	for _, v := range answer {
+		if v.ID != nil && v.Content != nil {
+			contents[*v.ID] = *v.Content
+		}
+	}
+
+	items := make([]ItemResult, 0, len(completionChoices))
+	correct := 0
 	for _, choice := range completionChoices {
-		for _, v := range answer {
-			if (choice.ID == *v.ID) && (choice.Content != *v.Content) {
-				return false, nil
-			}
+		content, ok := contents[choice.ID]
+		isCorrect := ok && content == choice.Content
+		if isCorrect {
+			correct++
 		}
+
+		items = append(items, ItemResult{
+			Item:      strings.TrimSpace(choice.QuestionFirst + " ___ " + choice.QuestionLast),
+			IsCorrect: isCorrect,
+		})
 	}
 
-	return true, nil
+	return NewItemsGrade(items, correct, len(completionChoices)), nil
 }
 
 type VocabGroupChoiceAnswer struct {
 	Groups []VocabGroup `json:"groups"`
 }
 
-func (answer VocabGroupChoiceAnswer) IsCorrect(choices Choices) (bool, error) {
+// Grade checks the group of every vocab placed
+func (answer VocabGroupChoiceAnswer) Grade(choices Choices) (*Grade, error) {
 
 	vocabGroupChoice, ok := choices.(VocabGroupChoice)
 	if !ok {
-		return false, errs.ErrAnswerInvalid
+		return nil, errs.ErrAnswerInvalid
 	}
 
-	if len(answer.Groups) != len(vocabGroupChoice.Groups) {
-		return false, nil
-	}
+	countVocabs := 0
 
 	solution := map[string]map[string]bool{}
 	for _, choice := range vocabGroupChoice.Groups {
@@ -148,61 +160,99 @@ func (answer VocabGroupChoiceAnswer) IsCorrect(choices Choices) (bool, error) {
 
 		for _, vocab := range choice.Vocabs {
 			solution[choice.GroupName][vocab] = true
+			countVocabs++
 		}
 	}
 
+	items := make([]ItemResult, 0, countVocabs)
+	placed := map[string]map[string]bool{}
+	correct := 0
 	for _, group := range answer.Groups {
-
-		if len(solution[group.GroupName]) != len(group.Vocabs) {
-			return false, nil
+		if _, ok := placed[group.GroupName]; !ok {
+			placed[group.GroupName] = map[string]bool{}
 		}
 
 		for _, vocab := range group.Vocabs {
-			if _, ok := solution[group.GroupName]; !ok {
-				return false, nil
+			if placed[group.GroupName][vocab] {
+				continue
 			}
+			placed[group.GroupName][vocab] = true
 
-			if !solution[group.GroupName][vocab] {
-				return false, nil
+			isCorrect := solution[group.GroupName][vocab]
+			if isCorrect {
+				correct++
 			}
+
+			items = append(items, ItemResult{Item: vocab + " → " + group.GroupName, IsCorrect: isCorrect})
 		}
 	}
 
-	return true, nil
+	return NewItemsGrade(items, correct, countVocabs), nil
 }
 
 type DependencyChoiceAnswer []Dependency
 
-func (answer DependencyChoiceAnswer) IsCorrect(choices Choices) (bool, error) {
+// Grade checks every determinant of every dependency answered against the
+// determinants the learner had to place, fixed ones are shown already placed
+// and count neither way. Like multiple choice, every wrong determinant takes
+// one right one off the score, so placing every attribute earns nothing.
+func (answer DependencyChoiceAnswer) Grade(choices Choices) (*Grade, error) {
 	choice, ok := choices.(DependencyChoice)
 	if !ok {
-		return false, errs.ErrAnswerInvalid
+		return nil, errs.ErrAnswerInvalid
 	}
 
+	countDeterminants := 0
+
 	solution := map[string]map[string]bool{}
+	fixed := map[string]map[string]bool{}
 	for _, dependency := range choice.Dependencies {
+		if _, ok := solution[dependency.Dependent]; !ok {
+			solution[dependency.Dependent] = map[string]bool{}
+			fixed[dependency.Dependent] = map[string]bool{}
+		}
+
 		for _, determinant := range dependency.Determinants {
-			if _, ok := solution[dependency.Dependent]; !ok {
-				solution[dependency.Dependent] = map[string]bool{}
+			if determinant.Fixed {
+				fixed[dependency.Dependent][determinant.Value] = true
+				continue
 			}
 
 			solution[dependency.Dependent][determinant.Value] = true
+			countDeterminants++
 		}
 	}
 
+	items := make([]ItemResult, 0, countDeterminants)
+	answered := map[string]map[string]bool{}
+	correct, wrong := 0, 0
 	for _, dependency := range answer {
+		if _, ok := answered[dependency.Dependent]; !ok {
+			answered[dependency.Dependent] = map[string]bool{}
+		}
+
 		for _, determinant := range dependency.Determinants {
-			if _, ok := solution[dependency.Dependent]; !ok {
-				return false, nil
+			if answered[dependency.Dependent][determinant.Value] || fixed[dependency.Dependent][determinant.Value] {
+				continue
 			}
+			answered[dependency.Dependent][determinant.Value] = true
 
-			if !solution[dependency.Dependent][determinant.Value] {
-				return false, nil
+			isCorrect := solution[dependency.Dependent][determinant.Value]
+			if isCorrect {
+				correct++
+			} else {
+				wrong++
 			}
+
+			items = append(items, ItemResult{Item: determinant.Value + " → " + dependency.Dependent, IsCorrect: isCorrect})
 		}
 	}
 
-	return true, nil
+	if correct < wrong {
+		correct, wrong = 0, 0
+	}
+
+	return NewItemsGrade(items, correct-wrong, countDeterminants), nil
 }
 
 type ERChoiceAnswer struct {
@@ -210,7 +260,26 @@ type ERChoiceAnswer struct {
 	Relationships Relationships `json:"relationships"`
 }
 
-func (answer ERChoiceAnswer) IsCorrect(choice ERChoice) (bool, string) {
+// Grade tells the learner the first thing that is wrong, a diagram is right
+// or wrong as a whole
+func (answer ERChoiceAnswer) Grade(choices Choices) (*Grade, error) {
+	choice, ok := choices.(ERChoice)
+	if !ok {
+		return nil, errs.ErrAnswerInvalid
+	}
+
+	isCorrect, message := answer.Check(choice)
+
+	score := 0.0
+	if isCorrect {
+		score = 1
+	}
+
+	return &Grade{IsCorrect: isCorrect, Score: score, Message: &message}, nil
+}
+
+// Check compares the answer with the ER choice and tells what is wrong first
+func (answer ERChoiceAnswer) Check(choice ERChoice) (bool, string) {
 
 	if len(answer.Tables) < len(choice.Tables) {
 		return false, RelationSuggestions[SUGGESTION_LESS_RELATION]
@@ -0,0 +1,53 @@
+package activity
+
+import "testing"
+
+func TestDependencyChoiceAnswerGrade(t *testing.T) {
+	choice := DependencyChoice{
+		Dependencies: []Dependency{
+			{Dependent: "name", Determinants: []Determinant{{Value: "id"}, {Value: "email", Fixed: true}}},
+			{Dependent: "credit", Determinants: []Determinant{{Value: "course"}, {Value: "year"}}},
+		},
+	}
+
+	dependency := func(dependent string, determinants ...string) Dependency {
+		d := Dependency{Dependent: dependent}
+		for _, value := range determinants {
+			d.Determinants = append(d.Determinants, Determinant{Value: value})
+		}
+		return d
+	}
+
+	tests := []struct {
+		name      string
+		answer    DependencyChoiceAnswer
+		isCorrect bool
+		score     float64
+	}{
+		{"blank", DependencyChoiceAnswer{}, false, 0},
+		{"blank dependencies", DependencyChoiceAnswer{dependency("name"), dependency("credit")}, false, 0},
+		{"partial", DependencyChoiceAnswer{dependency("name", "id")}, false, 1.0 / 3},
+		{"partial with fixed", DependencyChoiceAnswer{dependency("name", "id", "email")}, false, 1.0 / 3},
+		{"complete", DependencyChoiceAnswer{dependency("name", "id"), dependency("credit", "course", "year")}, true, 1},
+		{"complete with fixed", DependencyChoiceAnswer{dependency("name", "id", "email"), dependency("credit", "year", "course")}, true, 1},
+		{"complete with a wrong one", DependencyChoiceAnswer{dependency("name", "id", "course"), dependency("credit", "course", "year")}, false, 2.0 / 3},
+		{"every attribute", DependencyChoiceAnswer{dependency("name", "id", "course", "year"), dependency("credit", "id", "course", "year")}, false, 0},
+		{"repeated", DependencyChoiceAnswer{dependency("name", "id", "id", "id")}, false, 1.0 / 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			grade, err := test.answer.Grade(choice)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if grade.IsCorrect != test.isCorrect {
+				t.Errorf("IsCorrect = %v, want %v", grade.IsCorrect, test.isCorrect)
+			}
+			if grade.Score != test.score {
+				t.Errorf("Score = %v, want %v", grade.Score, test.score)
+			}
+		})
+	}
+}
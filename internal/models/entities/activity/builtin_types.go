@@ -0,0 +1,129 @@
+package activity
+
+import (
+	"database-camp/internal/errs"
+	"database-camp/internal/utils"
+)
+
+func init() {
+	RegisterType(ActivityType{
+		ID:   TYPE_MATCHING,
+		Name: "matching",
+		DecodeAnswer: func(answer interface{}) (Answer, error) {
+			var matchingChoiceAnswer MatchingChoiceAnswer
+			err := utils.StructToStruct(answer, &matchingChoiceAnswer)
+			return matchingChoiceAnswer, err
+		},
+		ParseChoices: func(choices interface{}) (interface{}, error) {
+			var matchingChoices MatchingChoices
+			if err := utils.StructToStruct(choices, &matchingChoices); err != nil || !validateMatchingChoices(matchingChoices) {
+				return nil, errs.ErrChoicesInvalid
+			}
+			return matchingChoices, nil
+		},
+	})
+
+	RegisterType(ActivityType{
+		ID:   TYPE_MULTIPLE,
+		Name: "multiple",
+		DecodeAnswer: func(answer interface{}) (Answer, error) {
+			var multipleChoiceAnswer MultipleChoiceAnswer
+			err := utils.StructToStruct(answer, &multipleChoiceAnswer)
+			return multipleChoiceAnswer, err
+		},
+		ParseChoices: func(choices interface{}) (interface{}, error) {
+			var multipleChoices MultipleChoices
+			if err := utils.StructToStruct(choices, &multipleChoices); err != nil || !validateMultipleChoices(multipleChoices) {
+				return nil, errs.ErrChoicesInvalid
+			}
+			return multipleChoices, nil
+		},
+	})
+
+	RegisterType(ActivityType{
+		ID:   TYPE_COMPLETION,
+		Name: "completion",
+		DecodeAnswer: func(answer interface{}) (Answer, error) {
+			var completionChoiceAnswer CompletionChoiceAnswer
+			err := utils.StructToStruct(answer, &completionChoiceAnswer)
+			return completionChoiceAnswer, err
+		},
+		ParseChoices: func(choices interface{}) (interface{}, error) {
+			var completionChoices CompletionChoices
+			if err := utils.StructToStruct(choices, &completionChoices); err != nil || !validateCompletionChoices(completionChoices) {
+				return nil, errs.ErrChoicesInvalid
+			}
+			return completionChoices, nil
+		},
+	})
+
+	RegisterType(ActivityType{
+		ID:   TYPE_VOCAB_GROUP,
+		Name: "vocab_group",
+		DecodeAnswer: func(answer interface{}) (Answer, error) {
+			var vocabGroupChoiceAnswer VocabGroupChoiceAnswer
+			err := utils.StructToStruct(answer, &vocabGroupChoiceAnswer)
+			return vocabGroupChoiceAnswer, err
+		},
+		ParseChoices: func(choices interface{}) (interface{}, error) {
+			var vocabGroupChoice VocabGroupChoice
+			if err := utils.StructToStruct(choices, &vocabGroupChoice); err != nil || !validateVocabGroupChoice(vocabGroupChoice) {
+				return nil, errs.ErrChoicesInvalid
+			}
+			return vocabGroupChoice, nil
+		},
+	})
+
+	RegisterType(ActivityType{
+		ID:   TYPE_DEPENDENCY,
+		Name: "dependency",
+		DecodeAnswer: func(answer interface{}) (Answer, error) {
+			var dependencyChoiceAnswer DependencyChoiceAnswer
+			err := utils.StructToStruct(answer, &dependencyChoiceAnswer)
+			return dependencyChoiceAnswer, err
+		},
+		ParseChoices: func(choices interface{}) (interface{}, error) {
+			var dependencyChoice DependencyChoiceSpec
+			if err := utils.StructToStruct(choices, &dependencyChoice); err != nil || !validateDependencyChoice(dependencyChoice) {
+				return nil, errs.ErrChoicesInvalid
+			}
+			return dependencyChoice, nil
+		},
+		AuthoredChoices: func(choices Choices) interface{} {
+			if dependencyChoice, ok := choices.(DependencyChoice); ok {
+				return NewDependencyChoiceSpec(dependencyChoice)
+			}
+			return choices
+		},
+	})
+
+	RegisterType(ActivityType{
+		ID:   TYPE_ER,
+		Name: "er",
+		DecodeAnswer: func(answer interface{}) (Answer, error) {
+			var erChoiceAnswer ERChoiceAnswer
+			err := utils.StructToStruct(answer, &erChoiceAnswer)
+			return erChoiceAnswer, err
+		},
+		ParseChoices: func(choices interface{}) (interface{}, error) {
+			var erChoice ERChoiceSpec
+			if err := utils.StructToStruct(choices, &erChoice); err != nil || !validateERChoice(erChoice) {
+				return nil, errs.ErrChoicesInvalid
+			}
+			return erChoice, nil
+		},
+		AuthoredChoices: func(choices Choices) interface{} {
+			if erChoice, ok := choices.(ERChoice); ok {
+				return NewERChoiceSpec(erChoice)
+			}
+			return choices
+		},
+	})
+
+	// Peer review activities show the ER answers of other learners and are
+	// checked by CheckPeerReview
+	RegisterType(ActivityType{
+		ID:   TYPE_PEER_REVIEW,
+		Name: "peer_review",
+	})
+}
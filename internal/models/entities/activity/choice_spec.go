@@ -0,0 +1,112 @@
+package activity
+
+// The spec types describe choices the way they are authored. Unlike the
+// choices sent to learners they keep the fixed flags, and ER tables are
+// referenced by ids that are only meaningful inside one choice.
+
+type DeterminantSpec struct {
+	Value string `json:"value"`
+	Fixed bool   `json:"fixed"`
+}
+
+type DependencySpec struct {
+	Dependent    string            `json:"dependent"`
+	Fixed        bool              `json:"fixed"`
+	Determinants []DeterminantSpec `json:"determinants"`
+}
+
+type DependencyChoiceSpec struct {
+	Dependencies []DependencySpec `json:"dependencies"`
+}
+
+type AttributeSpec struct {
+	Key   *string `json:"key"`
+	Value string  `json:"value"`
+	Fixed bool    `json:"fixed"`
+}
+
+type TableSpec struct {
+	ID         string          `json:"table_id"`
+	Title      string          `json:"title"`
+	Fixed      bool            `json:"fixed"`
+	Attributes []AttributeSpec `json:"attributes"`
+}
+
+type RelationshipSpec struct {
+	RelationshipType string `json:"relationship_type"`
+	Table1ID         string `json:"table1_id"`
+	Table2ID         string `json:"table2_id"`
+	Fixed            bool   `json:"fixed"`
+}
+
+type ERChoiceSpec struct {
+	Type          string             `json:"type"`
+	Tables        []TableSpec        `json:"tables"`
+	Relationships []RelationshipSpec `json:"relationships"`
+}
+
+func NewDependencyChoiceSpec(choice DependencyChoice) DependencyChoiceSpec {
+	spec := DependencyChoiceSpec{Dependencies: make([]DependencySpec, 0, len(choice.Dependencies))}
+
+	for _, dependency := range choice.Dependencies {
+		dependencySpec := DependencySpec{
+			Dependent:    dependency.Dependent,
+			Fixed:        dependency.Fixed,
+			Determinants: make([]DeterminantSpec, 0, len(dependency.Determinants)),
+		}
+
+		for _, determinant := range dependency.Determinants {
+			dependencySpec.Determinants = append(dependencySpec.Determinants, DeterminantSpec{
+				Value: determinant.Value,
+				Fixed: determinant.Fixed,
+			})
+		}
+
+		spec.Dependencies = append(spec.Dependencies, dependencySpec)
+	}
+
+	return spec
+}
+
+func NewERChoiceSpec(choice ERChoice) ERChoiceSpec {
+	spec := ERChoiceSpec{
+		Type:          choice.Type,
+		Tables:        make([]TableSpec, 0, len(choice.Tables)),
+		Relationships: make([]RelationshipSpec, 0, len(choice.Relationships)),
+	}
+
+	for _, table := range choice.Tables {
+		tableSpec := TableSpec{
+			ID:         table.ID,
+			Title:      table.Title,
+			Fixed:      table.Fixed,
+			Attributes: make([]AttributeSpec, 0, len(table.Attributes)),
+		}
+
+		for _, attribute := range table.Attributes {
+			// Tables without attributes are loaded with one empty attribute
+			if attribute == (Attribute{}) {
+				continue
+			}
+
+			tableSpec.Attributes = append(tableSpec.Attributes, AttributeSpec{
+				Key:   attribute.Key,
+				Value: attribute.Value,
+				Fixed: attribute.Fixed,
+			})
+		}
+
+		spec.Tables = append(spec.Tables, tableSpec)
+	}
+
+	for _, relationship := range choice.Relationships {
+		spec.Relationships = append(spec.Relationships, RelationshipSpec{
+			RelationshipType: relationship.RelationshipType,
+			Table1ID:         relationship.Table1ID,
+			Table2ID:         relationship.Table2ID,
+			Fixed:            relationship.Fixed,
+		})
+	}
+
+	return spec
+}
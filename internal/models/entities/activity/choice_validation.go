@@ -0,0 +1,161 @@
+package activity
+
+import "strings"
+
+// The validators check authored choices, see ActivityType.ParseChoices
+
+func isBlank(s string) bool {
+	return strings.TrimSpace(s) == ""
+}
+
+// Every item appears in one pair only, otherwise the answer is ambiguous
+func validateMatchingChoices(choices MatchingChoices) bool {
+	if len(choices) < 2 {
+		return false
+	}
+
+	items1 := map[string]bool{}
+	items2 := map[string]bool{}
+	for _, choice := range choices {
+		if isBlank(choice.PairItem1) || isBlank(choice.PairItem2) || items1[choice.PairItem1] || items2[choice.PairItem2] {
+			return false
+		}
+		items1[choice.PairItem1] = true
+		items2[choice.PairItem2] = true
+	}
+
+	return true
+}
+
+func validateMultipleChoices(choices MultipleChoices) bool {
+	if len(choices) < 2 {
+		return false
+	}
+
+	contents := map[string]bool{}
+	countCorrect := 0
+	for _, choice := range choices {
+		if isBlank(choice.Content) || contents[choice.Content] {
+			return false
+		}
+		contents[choice.Content] = true
+
+		if choice.IsCorrect {
+			countCorrect++
+		}
+	}
+
+	return countCorrect > 0
+}
+
+func validateCompletionChoices(choices CompletionChoices) bool {
+	if len(choices) == 0 {
+		return false
+	}
+
+	for _, choice := range choices {
+		if isBlank(choice.Content) || (isBlank(choice.QuestionFirst) && isBlank(choice.QuestionLast)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// A vocab belongs to one group only, the answer is checked by membership
+func validateVocabGroupChoice(choice VocabGroupChoice) bool {
+	if len(choice.Groups) < 2 {
+		return false
+	}
+
+	groups := map[string]bool{}
+	vocabs := map[string]bool{}
+	for _, group := range choice.Groups {
+		if isBlank(group.GroupName) || groups[group.GroupName] || len(group.Vocabs) == 0 {
+			return false
+		}
+		groups[group.GroupName] = true
+
+		for _, vocab := range group.Vocabs {
+			if isBlank(vocab) || vocabs[vocab] {
+				return false
+			}
+			vocabs[vocab] = true
+		}
+	}
+
+	return true
+}
+
+// The answer is checked by dependent, so each dependent is listed once
+func validateDependencyChoice(choice DependencyChoiceSpec) bool {
+	if len(choice.Dependencies) == 0 {
+		return false
+	}
+
+	dependents := map[string]bool{}
+	for _, dependency := range choice.Dependencies {
+		if isBlank(dependency.Dependent) || dependents[dependency.Dependent] || len(dependency.Determinants) == 0 {
+			return false
+		}
+		dependents[dependency.Dependent] = true
+
+		determinants := map[string]bool{}
+		for _, determinant := range dependency.Determinants {
+			if isBlank(determinant.Value) || determinants[determinant.Value] {
+				return false
+			}
+			determinants[determinant.Value] = true
+		}
+	}
+
+	return true
+}
+
+// Tables are matched with the answer by title and relationships must refer
+// to tables of the same choice
+func validateERChoice(choice ERChoiceSpec) bool {
+	if choice.Type != ER_CHOICE_FILL_TABLE && choice.Type != ER_CHOICE_DRAW {
+		return false
+	}
+
+	if len(choice.Tables) == 0 {
+		return false
+	}
+
+	tableIDs := map[string]bool{}
+	titles := map[string]bool{}
+	for _, table := range choice.Tables {
+		if isBlank(table.ID) || isBlank(table.Title) || tableIDs[table.ID] || titles[table.Title] {
+			return false
+		}
+		tableIDs[table.ID] = true
+		titles[table.Title] = true
+
+		values := map[string]bool{}
+		for _, attribute := range table.Attributes {
+			if isBlank(attribute.Value) || values[attribute.Value] {
+				return false
+			}
+			values[attribute.Value] = true
+
+			if attribute.Key != nil && *attribute.Key != ATTRIBUTE_KEY_PK && *attribute.Key != ATTRIBUTE_KEY_FK {
+				return false
+			}
+		}
+	}
+
+	for _, relationship := range choice.Relationships {
+		switch relationship.RelationshipType {
+		case RELATIONSHIP_MANY_TO_MANY, RELATIONSHIP_ONE_TO_MANY, RELATIONSHIP_ONE_TO_ONE:
+		default:
+			return false
+		}
+
+		if !tableIDs[relationship.Table1ID] || !tableIDs[relationship.Table2ID] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -2,7 +2,7 @@ package activity
 
 const (
 	PEER_ACTIVITY_ID      = 10
-	PEER_ACTIVITY_TYPE_ID = 6
+	PEER_ACTIVITY_TYPE_ID = TYPE_ER
 	PEER_ACTIVITY_POINT   = 300
 )
 
@@ -0,0 +1,52 @@
+package activity
+
+import (
+	"database-camp/internal/errs"
+	"encoding/json"
+)
+
+// ANSWER_VERSION is the version of the format submitted answers are stored
+// in, bump it when an answer structure changes so that older answers are
+// not decoded into the new one
+const ANSWER_VERSION = 1
+
+/**
+ * Encode the answer of a learner the way it is stored, decoded for the
+ * activity type first so that every stored answer has the same structure
+ *
+ * @param answer 			answer sent by the learner
+ * @param activityTypeID 	activity_type_id of the activity
+ *
+ * @return the answer as JSON
+ */
+func EncodeAnswer(answer interface{}, activityTypeID int) (json.RawMessage, error) {
+	formatedAnswer, err := FormatAnswer(answer, activityTypeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(formatedAnswer)
+}
+
+/**
+ * Check a stored answer again with the grader the activity type has now
+ *
+ * @param answer 			answer as it was stored
+ * @param version 			ANSWER_VERSION the answer was stored with
+ * @param activityTypeID 	activity_type_id of the activity
+ * @param choices 			choices of the activity
+ *
+ * @return the grade of the answer and ErrAnswerInvalid if it cannot be read
+ */
+func GradeStoredAnswer(answer json.RawMessage, version int, activityTypeID int, choices Choices) (*Grade, error) {
+	if len(answer) == 0 || version != ANSWER_VERSION {
+		return nil, errs.ErrAnswerInvalid
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(answer, &decoded); err != nil {
+		return nil, errs.ErrAnswerInvalid
+	}
+
+	return GradeAnswer(decoded, activityTypeID, choices)
+}
@@ -0,0 +1,72 @@
+package content
+
+import (
+	"path"
+	"strings"
+	"time"
+)
+
+// What an attachment of a content is for
+const (
+	ATTACHMENT_TYPE_SLIDE           = "slide"
+	ATTACHMENT_TYPE_SAMPLE_DATABASE = "sample_database"
+	ATTACHMENT_TYPE_CHEAT_SHEET     = "cheat_sheet"
+)
+
+// Attachment is a file learners download alongside a content. The file is
+// kept in the cloud storage bucket under ObjectName and is downloaded as
+// FileName, Size is in bytes.
+type Attachment struct {
+	ID               int       `gorm:"primaryKey;column:attachment_id" json:"attachment_id"`
+	ContentID        int       `gorm:"column:content_id" json:"content_id"`
+	Type             string    `gorm:"column:type" json:"type"`
+	Name             string    `gorm:"column:name" json:"name"`
+	FileName         string    `gorm:"column:file_name" json:"file_name"`
+	ObjectName       string    `gorm:"column:object_name" json:"-"`
+	ContentType      string    `gorm:"column:content_type" json:"content_type"`
+	Size             int64     `gorm:"column:size" json:"size"`
+	CreatedTimestamp time.Time `gorm:"column:created_timestamp" json:"created_timestamp"`
+}
+
+// AttachmentDownload is one signed link handed out to a user
+type AttachmentDownload struct {
+	ID               int       `gorm:"primaryKey;column:attachment_download_id" json:"attachment_download_id"`
+	AttachmentID     int       `gorm:"column:attachment_id" json:"attachment_id"`
+	UserID           int       `gorm:"column:user_id" json:"-"`
+	CreatedTimestamp time.Time `gorm:"column:created_timestamp" json:"created_timestamp"`
+}
+
+// AttachmentStatistic is an attachment with how many times it was downloaded
+// and by how many users
+type AttachmentStatistic struct {
+	Attachment
+	Downloads   int64 `gorm:"column:downloads" json:"downloads"`
+	Downloaders int64 `gorm:"column:downloaders" json:"downloaders"`
+}
+
+func IsValidAttachmentType(attachmentType string) bool {
+	switch attachmentType {
+	case ATTACHMENT_TYPE_SLIDE, ATTACHMENT_TYPE_SAMPLE_DATABASE, ATTACHMENT_TYPE_CHEAT_SHEET:
+		return true
+	default:
+		return false
+	}
+}
+
+// AttachmentFileName keeps the base name of an uploaded file, without the
+// characters that would break the download header, empty when nothing is left
+func AttachmentFileName(fileName string) string {
+	fileName = path.Base(strings.ReplaceAll(fileName, "\\", "/"))
+
+	fileName = strings.Map(func(r rune) rune {
+		if r < ' ' || r == '"' || r == '/' {
+			return -1
+		}
+		return r
+	}, fileName)
+
+	if fileName == "." || fileName == ".." {
+		return ""
+	}
+	return strings.TrimSpace(fileName)
+}
@@ -9,8 +9,10 @@ type Content struct {
 }
 
 type ContentGroup struct {
-	ID   int    `gorm:"column:content_group_id"`
-	Name string `gorm:"column:name"`
+	ID         int    `gorm:"primaryKey;column:content_group_id" json:"content_group_id"`
+	Name       string `gorm:"column:name" json:"name"`
+	BadgeID    int    `gorm:"column:badge_id" json:"badge_id"`
+	MiniExamID int    `gorm:"column:mini_exam_id" json:"mini_exam_id"`
 }
 
 type ContentGroups []ContentGroup
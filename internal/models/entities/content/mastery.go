@@ -0,0 +1,182 @@
+package content
+
+import (
+	"sort"
+	"time"
+)
+
+// Bayesian Knowledge Tracing parameters, the same for every content. A user
+// has learned a content with MASTERY_INITIAL before answering anything and
+// learns it with MASTERY_TRANSIT after each answer.
+const (
+	MASTERY_INITIAL = 0.3
+	MASTERY_TRANSIT = 0.1
+	MASTERY_SLIP    = 0.1
+	MASTERY_GUESS   = 0.2
+
+	MASTERY_MASTERED   = 0.95
+	MASTERY_STRUGGLING = 0.4
+)
+
+// Why a content is recommended, the most urgent first
+const (
+	RECOMMEND_REASON_STRUGGLING  = "struggling"
+	RECOMMEND_REASON_IN_PROGRESS = "in_progress"
+	RECOMMEND_REASON_NOT_STARTED = "not_started"
+)
+
+var recommendReasonRank = map[string]int{
+	RECOMMEND_REASON_STRUGGLING:  0,
+	RECOMMEND_REASON_IN_PROGRESS: 1,
+	RECOMMEND_REASON_NOT_STARTED: 2,
+}
+
+// Mastery is the probability that a user has learned a content, updated by
+// every answer to an activity of the content
+type Mastery struct {
+	UserID           int       `gorm:"primaryKey;column:user_id" json:"-"`
+	ContentID        int       `gorm:"primaryKey;column:content_id" json:"content_id"`
+	Probability      float64   `gorm:"column:probability" json:"probability"`
+	Attempts         int       `gorm:"column:attempts" json:"attempts"`
+	UpdatedTimestamp time.Time `gorm:"column:updated_timestamp" json:"updated_timestamp"`
+}
+
+// MasteryObservation is an answer to an activity of a content, score is the
+// fraction of the answer that is right
+type MasteryObservation struct {
+	ContentID int
+	Score     float64
+}
+
+func NewMastery(userID int, contentID int) Mastery {
+	return Mastery{
+		UserID:      userID,
+		ContentID:   contentID,
+		Probability: MASTERY_INITIAL,
+	}
+}
+
+/**
+ * Update the mastery with an answer. A partly right answer is that part
+ * evidence of a right answer and the rest evidence of a wrong one.
+ *
+ * @param score 	fraction of the answer that is right
+ * @param now 		time of the answer
+ */
+func (m *Mastery) Observe(score float64, now time.Time) {
+	if score < 0 {
+		score = 0
+	} else if score > 1 {
+		score = 1
+	}
+
+	p := m.Probability
+	right := p * (1 - MASTERY_SLIP) / (p*(1-MASTERY_SLIP) + (1-p)*MASTERY_GUESS)
+	wrong := p * MASTERY_SLIP / (p*MASTERY_SLIP + (1-p)*(1-MASTERY_GUESS))
+	posterior := score*right + (1-score)*wrong
+
+	m.Probability = posterior + (1-posterior)*MASTERY_TRANSIT
+	m.Attempts++
+	m.UpdatedTimestamp = now
+}
+
+type Masteries []Mastery
+
+// Recommendation is a content to learn next and why
+type Recommendation struct {
+	Rank           int     `json:"rank"`
+	ContentGroupID int     `json:"content_group_id"`
+	GroupName      string  `json:"group_name"`
+	ContentID      int     `json:"content_id"`
+	ContentName    string  `json:"content_name"`
+	Mastery        float64 `json:"mastery"`
+	Attempts       int     `json:"attempts"`
+	Reason         string  `json:"reason"`
+}
+
+/**
+ * Rank the contents the user has not mastered. Contents the user struggles
+ * with come first, then contents in progress, both least mastered first, then
+ * contents not started in the order of the course.
+ *
+ * @param masteries 	masteries of the user
+ * @param limit 		most contents to return
+ *
+ * @return the contents to learn next
+ */
+func (l OverviewList) GetRecommend(masteries Masteries, limit int) []Recommendation {
+	masteryMap := map[int]Mastery{}
+	for _, mastery := range masteries {
+		masteryMap[mastery.ContentID] = mastery
+	}
+
+	recommendations := make([]Recommendation, 0)
+	for _, overview := range l.getContents() {
+		mastery, ok := masteryMap[overview.ContentID]
+		if !ok {
+			mastery = NewMastery(0, overview.ContentID)
+		}
+
+		if mastery.Probability >= MASTERY_MASTERED {
+			continue
+		}
+
+		reason := RECOMMEND_REASON_IN_PROGRESS
+		if mastery.Attempts == 0 {
+			reason = RECOMMEND_REASON_NOT_STARTED
+		} else if mastery.Probability < MASTERY_STRUGGLING {
+			reason = RECOMMEND_REASON_STRUGGLING
+		}
+
+		recommendations = append(recommendations, Recommendation{
+			ContentGroupID: overview.GroupID,
+			GroupName:      overview.GroupName,
+			ContentID:      overview.ContentID,
+			ContentName:    overview.ContentName,
+			Mastery:        mastery.Probability,
+			Attempts:       mastery.Attempts,
+			Reason:         reason,
+		})
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		a, b := recommendations[i], recommendations[j]
+		if a.Reason != b.Reason {
+			return recommendReasonRank[a.Reason] < recommendReasonRank[b.Reason]
+		}
+		return a.Reason != RECOMMEND_REASON_NOT_STARTED && a.Mastery < b.Mastery
+	})
+
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+
+	for i := range recommendations {
+		recommendations[i].Rank = i + 1
+	}
+
+	return recommendations
+}
+
+// getContents returns the contents that have activities once each, in the
+// order of the course
+func (l OverviewList) getContents() OverviewList {
+	seen := map[int]bool{}
+	contents := make(OverviewList, 0)
+	for _, overview := range l {
+		if overview.ActivityID == nil || seen[overview.ContentID] {
+			continue
+		}
+		seen[overview.ContentID] = true
+		contents = append(contents, overview)
+	}
+
+	sort.SliceStable(contents, func(i, j int) bool {
+		if contents[i].GroupID != contents[j].GroupID {
+			return contents[i].GroupID < contents[j].GroupID
+		}
+		return contents[i].ContentID < contents[j].ContentID
+	})
+
+	return contents
+}
@@ -13,10 +13,12 @@ type group struct {
 }
 
 type contentOverview struct {
-	ContentID   int    `json:"content_id"`
-	ContentName string `json:"content_name"`
-	IsLasted    bool   `json:"is_lasted"`
-	Progress    int    `json:"progress"`
+	ContentID          int           `json:"content_id"`
+	ContentName        string        `json:"content_name"`
+	IsLasted           bool          `json:"is_lasted"`
+	Progress           int           `json:"progress"`
+	IsLocked           bool          `json:"is_locked"`
+	UnmetPrerequisites Prerequisites `json:"unmet_prerequisites,omitempty"`
 }
 
 type LastedGroupOverview struct {
@@ -29,11 +31,13 @@ type LastedGroupOverview struct {
 }
 
 type ContentGroupOverview struct {
-	GroupID   int               `json:"group_id"`
-	IsLasted  bool              `json:"is_lasted"`
-	GroupName string            `json:"group_name"`
-	Progress  int               `json:"progress"`
-	Contents  []contentOverview `json:"contents"`
+	GroupID            int               `json:"group_id"`
+	IsLasted           bool              `json:"is_lasted"`
+	GroupName          string            `json:"group_name"`
+	Progress           int               `json:"progress"`
+	IsLocked           bool              `json:"is_locked"`
+	UnmetPrerequisites Prerequisites     `json:"unmet_prerequisites,omitempty"`
+	Contents           []contentOverview `json:"contents"`
 }
 
 type Overview struct {
@@ -158,3 +162,19 @@ func calculateProgress(progress int, total int) int {
 	}
 
 }
+
+// GroupContentIDs lists the contents of every group, each content once
+func (l OverviewList) GroupContentIDs() map[int][]int {
+	groupContentIDs := map[int][]int{}
+	seen := map[int]bool{}
+	for _, overview := range l {
+		if seen[overview.ContentID] {
+			continue
+		}
+		seen[overview.ContentID] = true
+
+		groupContentIDs[overview.GroupID] = append(groupContentIDs[overview.GroupID], overview.ContentID)
+	}
+
+	return groupContentIDs
+}
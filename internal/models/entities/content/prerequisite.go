@@ -0,0 +1,280 @@
+package content
+
+import "fmt"
+
+// What a prerequisite locks
+const (
+	PREREQUISITE_TARGET_CONTENT       = "content"
+	PREREQUISITE_TARGET_CONTENT_GROUP = "content_group"
+)
+
+// How a prerequisite is met, RequiredID is a content for
+// UNLOCK_RULE_COMPLETE_ACTIVITIES, a content group for
+// UNLOCK_RULE_PASS_MINI_EXAM and a badge for UNLOCK_RULE_EARN_BADGE
+const (
+	UNLOCK_RULE_COMPLETE_ACTIVITIES = "complete_activities"
+	UNLOCK_RULE_PASS_MINI_EXAM      = "pass_mini_exam"
+	UNLOCK_RULE_EARN_BADGE          = "earn_badge"
+)
+
+// Prerequisite is one rule a content or content group is locked behind, a
+// target is unlocked when every rule of it and of its group is met. Count is
+// how many activities of the required content are answered correctly, zero
+// for all of them.
+type Prerequisite struct {
+	ID         int    `gorm:"primaryKey;column:prerequisite_id" json:"prerequisite_id"`
+	TargetType string `gorm:"column:target_type" json:"target_type"`
+	TargetID   int    `gorm:"column:target_id" json:"target_id"`
+	Rule       string `gorm:"column:rule" json:"rule"`
+	RequiredID int    `gorm:"column:required_id" json:"required_id"`
+	Count      int    `gorm:"column:count" json:"count"`
+}
+
+func IsValidPrerequisiteTarget(targetType string) bool {
+	return targetType == PREREQUISITE_TARGET_CONTENT || targetType == PREREQUISITE_TARGET_CONTENT_GROUP
+}
+
+func IsValidUnlockRule(rule string) bool {
+	switch rule {
+	case UNLOCK_RULE_COMPLETE_ACTIVITIES, UNLOCK_RULE_PASS_MINI_EXAM, UNLOCK_RULE_EARN_BADGE:
+		return true
+	default:
+		return false
+	}
+}
+
+type Prerequisites []Prerequisite
+
+// GetRules tells which unlock rules the prerequisites use
+func (p Prerequisites) GetRules() map[string]bool {
+	rules := map[string]bool{}
+	for _, prerequisite := range p {
+		rules[prerequisite.Rule] = true
+	}
+	return rules
+}
+
+/**
+ * Check whether adding a prerequisite would lock a content or content group
+ * behind itself. A content depends on its group, a target depends on the
+ * content of its complete activities rules and the group of its mini exam
+ * rules.
+ *
+ * @param prerequisite 	prerequisite to add
+ * @param overview 		contents of the course with their groups
+ *
+ * @return true when the prerequisites would have a cycle
+ */
+func (p Prerequisites) HasCycle(prerequisite Prerequisite, overview OverviewList) bool {
+	edges := map[string][]string{}
+	for _, row := range overview {
+		if row.ContentID != 0 {
+			from := prerequisiteNode(PREREQUISITE_TARGET_CONTENT, row.ContentID)
+			edges[from] = appendNode(edges[from], prerequisiteNode(PREREQUISITE_TARGET_CONTENT_GROUP, row.GroupID))
+		}
+	}
+
+	for _, _prerequisite := range append(p, prerequisite) {
+		if required, ok := _prerequisite.requiredNode(); ok {
+			from := prerequisiteNode(_prerequisite.TargetType, _prerequisite.TargetID)
+			edges[from] = appendNode(edges[from], required)
+		}
+	}
+
+	target := prerequisiteNode(prerequisite.TargetType, prerequisite.TargetID)
+	required, ok := prerequisite.requiredNode()
+	if !ok {
+		return false
+	}
+
+	visited := map[string]bool{}
+	stack := []string{required}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if node == target {
+			return true
+		} else if visited[node] {
+			continue
+		}
+		visited[node] = true
+
+		stack = append(stack, edges[node]...)
+	}
+
+	return false
+}
+
+func (p Prerequisite) requiredNode() (string, bool) {
+	switch p.Rule {
+	case UNLOCK_RULE_COMPLETE_ACTIVITIES:
+		return prerequisiteNode(PREREQUISITE_TARGET_CONTENT, p.RequiredID), true
+	case UNLOCK_RULE_PASS_MINI_EXAM:
+		return prerequisiteNode(PREREQUISITE_TARGET_CONTENT_GROUP, p.RequiredID), true
+	default:
+		return "", false
+	}
+}
+
+func prerequisiteNode(targetType string, id int) string {
+	return fmt.Sprintf("%s:%d", targetType, id)
+}
+
+func appendNode(nodes []string, node string) []string {
+	for _, _node := range nodes {
+		if _node == node {
+			return nodes
+		}
+	}
+	return append(nodes, node)
+}
+
+// Locks tells which contents and content groups a user has not unlocked yet
+type Locks struct {
+	prerequisites     Prerequisites
+	contentGroupIDs   map[int]int
+	contentActivities map[int]int
+	correctActivities map[int]int
+	passedGroups      map[int]bool
+	badges            map[int]bool
+}
+
+/**
+ * Find out what the user has done towards the prerequisites
+ *
+ * @param prerequisites 	every prerequisite of the course
+ * @param overview 			contents of the course with their activities
+ * @param progressions 		activities the user answered correctly
+ * @param groups 			content groups with their mini exams
+ * @param passedExamIDs 	exams the user passed
+ * @param badgeIDs 			badges the user earned
+ *
+ * @return the locks of the user
+ */
+func NewLocks(
+	prerequisites Prerequisites,
+	overview OverviewList,
+	progressions LearningProgressionList,
+	groups ContentGroups,
+	passedExamIDs []int,
+	badgeIDs []int,
+) Locks {
+	locks := Locks{
+		prerequisites:     prerequisites,
+		contentGroupIDs:   map[int]int{},
+		contentActivities: map[int]int{},
+		correctActivities: map[int]int{},
+		passedGroups:      map[int]bool{},
+		badges:            map[int]bool{},
+	}
+
+	for _, row := range overview {
+		locks.contentGroupIDs[row.ContentID] = row.GroupID
+		if row.ActivityID != nil {
+			locks.contentActivities[row.ContentID]++
+		}
+	}
+
+	activityContentIDMap := overview.createActivityContentIDMap()
+	locks.correctActivities = progressions.createUserActivityCountByContentID(activityContentIDMap)
+
+	passedExams := map[int]bool{}
+	for _, examID := range passedExamIDs {
+		passedExams[examID] = true
+	}
+	for _, group := range groups {
+		locks.passedGroups[group.ID] = passedExams[group.MiniExamID]
+	}
+
+	for _, badgeID := range badgeIDs {
+		locks.badges[badgeID] = true
+	}
+
+	return locks
+}
+
+func (l Locks) isMet(prerequisite Prerequisite) bool {
+	switch prerequisite.Rule {
+	case UNLOCK_RULE_COMPLETE_ACTIVITIES:
+		count := prerequisite.Count
+		if count <= 0 {
+			count = l.contentActivities[prerequisite.RequiredID]
+		}
+		return l.correctActivities[prerequisite.RequiredID] >= count
+	case UNLOCK_RULE_PASS_MINI_EXAM:
+		return l.passedGroups[prerequisite.RequiredID]
+	case UNLOCK_RULE_EARN_BADGE:
+		return l.badges[prerequisite.RequiredID]
+	default:
+		return true
+	}
+}
+
+func (l Locks) getUnmet(targetType string, targetID int) Prerequisites {
+	unmet := make(Prerequisites, 0)
+	for _, prerequisite := range l.prerequisites {
+		if prerequisite.TargetType == targetType && prerequisite.TargetID == targetID && !l.isMet(prerequisite) {
+			unmet = append(unmet, prerequisite)
+		}
+	}
+	return unmet
+}
+
+// GetUnmetGroup returns the prerequisites of the group the user has not met
+func (l Locks) GetUnmetGroup(groupID int) Prerequisites {
+	return l.getUnmet(PREREQUISITE_TARGET_CONTENT_GROUP, groupID)
+}
+
+// GetUnmetContent returns the prerequisites of the content and of its group
+// the user has not met
+func (l Locks) GetUnmetContent(contentID int) Prerequisites {
+	unmet := l.GetUnmetGroup(l.contentGroupIDs[contentID])
+	return append(unmet, l.getUnmet(PREREQUISITE_TARGET_CONTENT, contentID)...)
+}
+
+func (l Locks) IsGroupLocked(groupID int) bool {
+	return len(l.GetUnmetGroup(groupID)) > 0
+}
+
+func (l Locks) IsContentLocked(contentID int) bool {
+	return len(l.GetUnmetContent(contentID)) > 0
+}
+
+// GetUnlockedOverview keeps the rows of the overview of contents the user has
+// unlocked
+func (l Locks) GetUnlockedOverview(overview OverviewList) OverviewList {
+	unlocked := make(OverviewList, 0, len(overview))
+	for _, row := range overview {
+		if !l.IsContentLocked(row.ContentID) {
+			unlocked = append(unlocked, row)
+		}
+	}
+	return unlocked
+}
+
+// GetLockedActivityIDs lists the activities of the overview in contents the
+// user has not unlocked
+func (l Locks) GetLockedActivityIDs(overview OverviewList) []int {
+	activityIDs := make([]int, 0)
+	for _, row := range overview {
+		if row.ActivityID != nil && l.IsContentLocked(row.ContentID) {
+			activityIDs = append(activityIDs, *row.ActivityID)
+		}
+	}
+	return activityIDs
+}
+
+// SetLocked marks the groups and contents of the overview the user has not
+// unlocked
+func (l Locks) SetLocked(groups []ContentGroupOverview) {
+	for i := range groups {
+		groups[i].UnmetPrerequisites = l.GetUnmetGroup(groups[i].GroupID)
+		groups[i].IsLocked = len(groups[i].UnmetPrerequisites) > 0
+
+		for j := range groups[i].Contents {
+			groups[i].Contents[j].UnmetPrerequisites = l.GetUnmetContent(groups[i].Contents[j].ContentID)
+			groups[i].Contents[j].IsLocked = len(groups[i].Contents[j].UnmetPrerequisites) > 0
+		}
+	}
+}
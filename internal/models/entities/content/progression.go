@@ -1,13 +1,19 @@
 package content
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type LearningProgression struct {
-	ID               int       `gorm:"primaryKey;column:learning_progression_id" json:"learning_progression_id"`
-	UserID           int       `gorm:"column:user_id" json:"user_id"`
-	ActivityID       int       `gorm:"column:activity_id" json:"activity_id"`
-	IsCorrect        bool      `gorm:"column:is_correct" json:"is_correct"`
-	CreatedTimestamp time.Time `gorm:"column:created_timestamp" json:"created_timestamp"`
+	ID               int             `gorm:"primaryKey;column:learning_progression_id" json:"learning_progression_id"`
+	UserID           int             `gorm:"column:user_id" json:"user_id"`
+	ActivityID       int             `gorm:"column:activity_id" json:"activity_id"`
+	IsCorrect        bool            `gorm:"column:is_correct" json:"is_correct"`
+	Point            int             `gorm:"column:point" json:"point"`
+	Answer           json.RawMessage `gorm:"column:answer" json:"answer,omitempty"`
+	AnswerVersion    *int            `gorm:"column:answer_version" json:"answer_version,omitempty"`
+	CreatedTimestamp time.Time       `gorm:"column:created_timestamp" json:"created_timestamp"`
 }
 
 type LearningProgressionList []LearningProgression
@@ -0,0 +1,97 @@
+package content
+
+import (
+	"math"
+	"time"
+)
+
+// SM-2 constants, an answer of quality REVIEW_QUALITY_PASS or more is recalled
+const (
+	REVIEW_INITIAL_EASE_FACTOR = 2.5
+	REVIEW_MIN_EASE_FACTOR     = 1.3
+	REVIEW_QUALITY_PASS        = 3
+	REVIEW_QUALITY_PERFECT     = 5
+)
+
+// ReviewSchedule is the memory of a user for an activity the user has answered
+// correctly, scheduled with SM-2. The activity is due again at DueTimestamp.
+type ReviewSchedule struct {
+	UserID            int        `gorm:"primaryKey;column:user_id" json:"-"`
+	ActivityID        int        `gorm:"primaryKey;column:activity_id" json:"activity_id"`
+	Repetition        int        `gorm:"column:repetition" json:"repetition"`
+	IntervalDay       int        `gorm:"column:interval_day" json:"interval_day"`
+	EaseFactor        float64    `gorm:"column:ease_factor" json:"ease_factor"`
+	DueTimestamp      time.Time  `gorm:"column:due_timestamp" json:"due_timestamp"`
+	ReviewedTimestamp *time.Time `gorm:"column:reviewed_timestamp" json:"reviewed_timestamp"`
+	CreatedTimestamp  time.Time  `gorm:"column:created_timestamp" json:"created_timestamp"`
+}
+
+func NewReviewSchedule(userID int, activityID int, now time.Time) ReviewSchedule {
+	return ReviewSchedule{
+		UserID:           userID,
+		ActivityID:       activityID,
+		EaseFactor:       REVIEW_INITIAL_EASE_FACTOR,
+		DueTimestamp:     now,
+		CreatedTimestamp: now,
+	}
+}
+
+/**
+ * Quality of an answer on the SM-2 scale, a correct answer is perfect and a
+ * wrong one is a failure that is less bad the more of it is right
+ *
+ * @param isCorrect 	whether the answer is correct
+ * @param score 		fraction of the answer that is right
+ *
+ * @return quality from 0 to 5
+ */
+func ReviewQuality(isCorrect bool, score float64) int {
+	if isCorrect {
+		return REVIEW_QUALITY_PERFECT
+	}
+
+	return int(math.Round(math.Max(0, math.Min(score, 1)) * (REVIEW_QUALITY_PASS - 1)))
+}
+
+func (s ReviewSchedule) IsDue(now time.Time) bool {
+	return !now.Before(s.DueTimestamp)
+}
+
+/**
+ * Schedule the activity after an answer. A recalled answer before the
+ * activity is due is not a review and leaves the schedule as it is, a failed
+ * answer always starts the repetitions again.
+ *
+ * @param quality 	quality of the answer from ReviewQuality
+ * @param now 		time of the answer
+ *
+ * @return whether the schedule changed
+ */
+func (s *ReviewSchedule) Review(quality int, now time.Time) bool {
+	if quality >= REVIEW_QUALITY_PASS && !s.IsDue(now) {
+		return false
+	}
+
+	if quality >= REVIEW_QUALITY_PASS {
+		switch s.Repetition {
+		case 0:
+			s.IntervalDay = 1
+		case 1:
+			s.IntervalDay = 6
+		default:
+			s.IntervalDay = int(math.Round(float64(s.IntervalDay) * s.EaseFactor))
+		}
+		s.Repetition++
+	} else {
+		s.Repetition = 0
+		s.IntervalDay = 1
+	}
+
+	miss := float64(REVIEW_QUALITY_PERFECT - quality)
+	s.EaseFactor = math.Max(REVIEW_MIN_EASE_FACTOR, s.EaseFactor+0.1-miss*(0.08+miss*0.02))
+
+	s.DueTimestamp = now.AddDate(0, 0, s.IntervalDay)
+	s.ReviewedTimestamp = &now
+
+	return true
+}
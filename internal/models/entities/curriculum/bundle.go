@@ -0,0 +1,139 @@
+package curriculum
+
+import (
+	"database-camp/internal/models/entities/activity"
+	"database-camp/internal/models/entities/content"
+	"database-camp/internal/models/entities/exam"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// BUNDLE_VERSION is raised whenever the bundle layout changes, bundles of
+// another version are refused rather than imported half understood.
+const BUNDLE_VERSION = 2
+
+const (
+	FORMAT_YAML = "yaml"
+	FORMAT_JSON = "json"
+)
+
+// Bundle is a whole curriculum as kept in git. Rows keep their database ids,
+// so a bundle moved to another database links to the same badges, exams and
+// learner progressions.
+type Bundle struct {
+	Version       int                    `json:"version"`
+	Badges        []Badge                `json:"badges"`
+	ContentGroups []ContentGroup         `json:"content_groups"`
+	Activities    []Activity             `json:"activities"`
+	Exams         []Exam                 `json:"exams"`
+	Prerequisites []content.Prerequisite `json:"prerequisites"`
+}
+
+// Badge and Exam have their own layout, the entity structs of those tables are
+// shaped by the views and API responses they are read for.
+type Badge struct {
+	ID       int    `gorm:"primaryKey;column:badge_id" json:"badge_id"`
+	Name     string `gorm:"column:name" json:"name"`
+	IconPath string `gorm:"column:icon_path" json:"icon_path"`
+}
+
+type ContentGroup struct {
+	content.ContentGroup
+	Contents []Content `json:"contents"`
+}
+
+type Content struct {
+	content.Content
+	Activities []Activity `json:"activities"`
+}
+
+// Activity holds its choices in the shape of the activity type, see
+// request.ActivityRequest.ParseChoices.
+type Activity struct {
+	activity.Activity
+	Choices interface{}     `json:"choices"`
+	Hints   []activity.Hint `json:"hints"`
+}
+
+type ExamActivity struct {
+	ContentGroupID int `gorm:"column:content_group_id" json:"content_group_id"`
+	ActivityID     int `gorm:"column:activity_id" json:"activity_id"`
+}
+
+type Exam struct {
+	ID          int            `gorm:"primaryKey;column:exam_id" json:"exam_id"`
+	Type        exam.ExamType  `gorm:"column:type" json:"exam_type"`
+	Instruction string         `gorm:"column:instruction" json:"instruction"`
+	Activities  []ExamActivity `gorm:"-" json:"activities"`
+}
+
+/**
+ * List every activity of the bundle, those of the contents first
+ *
+ * @return the activities
+ */
+func (b Bundle) AllActivities() []Activity {
+	activities := make([]Activity, 0)
+	for _, group := range b.ContentGroups {
+		for _, _content := range group.Contents {
+			activities = append(activities, _content.Activities...)
+		}
+	}
+	return append(activities, b.Activities...)
+}
+
+func (b Bundle) AllContents() []Content {
+	contents := make([]Content, 0)
+	for _, group := range b.ContentGroups {
+		contents = append(contents, group.Contents...)
+	}
+	return contents
+}
+
+// Sort orders every list by id so an exported bundle diffs cleanly in git.
+func (b *Bundle) Sort() {
+	sort.Slice(b.Badges, func(i, j int) bool { return b.Badges[i].ID < b.Badges[j].ID })
+	sort.Slice(b.ContentGroups, func(i, j int) bool { return b.ContentGroups[i].ID < b.ContentGroups[j].ID })
+	sortActivities(b.Activities)
+	sort.Slice(b.Exams, func(i, j int) bool { return b.Exams[i].ID < b.Exams[j].ID })
+	sort.Slice(b.Prerequisites, func(i, j int) bool { return b.Prerequisites[i].ID < b.Prerequisites[j].ID })
+
+	for i := range b.ContentGroups {
+		contents := b.ContentGroups[i].Contents
+		sort.Slice(contents, func(i, j int) bool { return contents[i].ID < contents[j].ID })
+		for j := range contents {
+			sortActivities(contents[j].Activities)
+		}
+	}
+
+	for i := range b.Exams {
+		activities := b.Exams[i].Activities
+		sort.Slice(activities, func(i, j int) bool {
+			if activities[i].ContentGroupID != activities[j].ContentGroupID {
+				return activities[i].ContentGroupID < activities[j].ContentGroupID
+			}
+			return activities[i].ActivityID < activities[j].ActivityID
+		})
+	}
+}
+
+func sortActivities(activities []Activity) {
+	sort.Slice(activities, func(i, j int) bool { return activities[i].ID < activities[j].ID })
+	for i := range activities {
+		hints := activities[i].Hints
+		sort.Slice(hints, func(i, j int) bool { return hints[i].Level < hints[j].Level })
+	}
+}
+
+// ValidationErrors lists every problem found in a bundle at once, so a bundle
+// can be fixed in one pass.
+type ValidationErrors []string
+
+func (e ValidationErrors) Error() string {
+	return "curriculum: invalid bundle\n  " + strings.Join(e, "\n  ")
+}
+
+func (e *ValidationErrors) Add(format string, a ...interface{}) {
+	*e = append(*e, fmt.Sprintf(format, a...))
+}
@@ -0,0 +1,104 @@
+package curriculum
+
+import (
+	"database-camp/internal/models/entities/activity"
+	"fmt"
+	"sort"
+)
+
+/**
+ * Put the choices in a canonical order, the database returns vocab groups,
+ * dependencies and ER tables in no particular order and ER table ids are
+ * replaced by references local to the choice
+ *
+ * @param choices 	choices in the structure of their activity type
+ *
+ * @return the sorted copy of the choices
+ */
+func SortChoices(choices interface{}) interface{} {
+	switch choices := choices.(type) {
+	case activity.VocabGroupChoice:
+		return sortVocabGroupChoice(choices)
+	case activity.DependencyChoiceSpec:
+		return sortDependencyChoice(choices)
+	case activity.ERChoiceSpec:
+		return sortERChoice(choices)
+	}
+	return choices
+}
+
+func sortVocabGroupChoice(choice activity.VocabGroupChoice) activity.VocabGroupChoice {
+	sorted := activity.VocabGroupChoice{Groups: make([]activity.VocabGroup, 0, len(choice.Groups))}
+
+	for _, group := range choice.Groups {
+		vocabs := append([]string{}, group.Vocabs...)
+		sort.Strings(vocabs)
+		sorted.Groups = append(sorted.Groups, activity.VocabGroup{GroupName: group.GroupName, Vocabs: vocabs})
+	}
+
+	sort.Slice(sorted.Groups, func(i, j int) bool { return sorted.Groups[i].GroupName < sorted.Groups[j].GroupName })
+
+	return sorted
+}
+
+func sortDependencyChoice(choice activity.DependencyChoiceSpec) activity.DependencyChoiceSpec {
+	sorted := activity.DependencyChoiceSpec{Dependencies: make([]activity.DependencySpec, 0, len(choice.Dependencies))}
+
+	for _, dependency := range choice.Dependencies {
+		determinants := append([]activity.DeterminantSpec{}, dependency.Determinants...)
+		sort.Slice(determinants, func(i, j int) bool { return determinants[i].Value < determinants[j].Value })
+
+		dependency.Determinants = determinants
+		sorted.Dependencies = append(sorted.Dependencies, dependency)
+	}
+
+	sort.Slice(sorted.Dependencies, func(i, j int) bool {
+		return sorted.Dependencies[i].Dependent < sorted.Dependencies[j].Dependent
+	})
+
+	return sorted
+}
+
+// Tables are ordered by title and renamed table1, table2, ... in that order.
+func sortERChoice(choice activity.ERChoiceSpec) activity.ERChoiceSpec {
+	sorted := activity.ERChoiceSpec{
+		Type:          choice.Type,
+		Tables:        make([]activity.TableSpec, 0, len(choice.Tables)),
+		Relationships: make([]activity.RelationshipSpec, 0, len(choice.Relationships)),
+	}
+
+	for _, table := range choice.Tables {
+		attributes := append([]activity.AttributeSpec{}, table.Attributes...)
+		sort.Slice(attributes, func(i, j int) bool { return attributes[i].Value < attributes[j].Value })
+
+		table.Attributes = attributes
+		sorted.Tables = append(sorted.Tables, table)
+	}
+
+	sort.Slice(sorted.Tables, func(i, j int) bool { return sorted.Tables[i].Title < sorted.Tables[j].Title })
+
+	tableIDs := map[string]string{}
+	for i := range sorted.Tables {
+		tableIDs[sorted.Tables[i].ID] = fmt.Sprintf("table%d", i+1)
+		sorted.Tables[i].ID = tableIDs[sorted.Tables[i].ID]
+	}
+
+	for _, relationship := range choice.Relationships {
+		relationship.Table1ID = tableIDs[relationship.Table1ID]
+		relationship.Table2ID = tableIDs[relationship.Table2ID]
+		sorted.Relationships = append(sorted.Relationships, relationship)
+	}
+
+	sort.Slice(sorted.Relationships, func(i, j int) bool {
+		a, b := sorted.Relationships[i], sorted.Relationships[j]
+		if a.Table1ID != b.Table1ID {
+			return a.Table1ID < b.Table1ID
+		}
+		if a.Table2ID != b.Table2ID {
+			return a.Table2ID < b.Table2ID
+		}
+		return a.RelationshipType < b.RelationshipType
+	})
+
+	return sorted
+}
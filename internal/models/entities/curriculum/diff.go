@@ -0,0 +1,247 @@
+package curriculum
+
+import (
+	"database-camp/internal/models/entities/activity"
+	"database-camp/internal/models/entities/content"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	CHANGE_CREATE = "create"
+	CHANGE_UPDATE = "update"
+	CHANGE_ABSENT = "absent"
+)
+
+const (
+	KIND_BADGE           = "badge"
+	KIND_CONTENT_GROUP   = "content_group"
+	KIND_CONTENT         = "content"
+	KIND_ACTIVITY        = "activity"
+	KIND_CHOICES         = "choices"
+	KIND_HINT            = "hint"
+	KIND_EXAM            = "exam"
+	KIND_EXAM_ACTIVITIES = "exam_activities"
+	KIND_PREREQUISITE    = "prerequisite"
+)
+
+// Change is one row, or the choices or exam activities of one row, that an
+// import creates or updates. Rows only in the database are reported as absent
+// and left alone.
+type Change struct {
+	Action string `json:"action"`
+	Kind   string `json:"kind"`
+	ID     int    `json:"id"`
+}
+
+type Diff []Change
+
+func (d Diff) String() string {
+	if len(d) == 0 {
+		return "no changes"
+	}
+
+	symbols := map[string]string{CHANGE_CREATE: "+", CHANGE_UPDATE: "~", CHANGE_ABSENT: "?"}
+
+	lines := make([]string, 0, len(d))
+	for _, change := range d {
+		lines = append(lines, fmt.Sprintf("%s %s %d", symbols[change.Action], change.Kind, change.ID))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+/**
+ * Check whether the diff creates or updates the row
+ *
+ * @param kind 	kind of the row
+ * @param id 	id of the row
+ *
+ * @return true if the row is created or updated
+ */
+func (d Diff) Changes(kind string, id int) bool {
+	for _, change := range d {
+		if change.Kind == kind && change.ID == id && change.Action != CHANGE_ABSENT {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+ * Compare the bundle with the curriculum in the database, the choices of both
+ * must already be parsed into the structure of their activity type
+ *
+ * @param bundle 	bundle to import
+ * @param current 	curriculum exported from the database
+ *
+ * @return the changes the import makes
+ */
+func Compare(bundle Bundle, current Bundle) Diff {
+	diff := Diff{}
+
+	currentBadges := map[int]Badge{}
+	for _, badge := range current.Badges {
+		currentBadges[badge.ID] = badge
+	}
+	for _, badge := range bundle.Badges {
+		old, ok := currentBadges[badge.ID]
+		diff.compare(KIND_BADGE, badge.ID, ok, old, badge)
+		delete(currentBadges, badge.ID)
+	}
+
+	currentGroups := map[int]ContentGroup{}
+	for _, group := range current.ContentGroups {
+		currentGroups[group.ID] = group
+	}
+	for _, group := range bundle.ContentGroups {
+		old, ok := currentGroups[group.ID]
+		diff.compare(KIND_CONTENT_GROUP, group.ID, ok, old.ContentGroup, group.ContentGroup)
+		delete(currentGroups, group.ID)
+	}
+
+	currentContents := map[int]Content{}
+	for _, _content := range current.AllContents() {
+		currentContents[_content.ID] = _content
+	}
+	for _, _content := range bundle.AllContents() {
+		old, ok := currentContents[_content.ID]
+		diff.compare(KIND_CONTENT, _content.ID, ok, old.Content, _content.Content)
+		delete(currentContents, _content.ID)
+	}
+
+	currentActivities := map[int]Activity{}
+	currentHints := map[int]activity.Hint{}
+	for _, _activity := range current.AllActivities() {
+		currentActivities[_activity.ID] = _activity
+		for _, hint := range _activity.Hints {
+			currentHints[hint.ID] = hint
+		}
+	}
+	for _, _activity := range bundle.AllActivities() {
+		old, ok := currentActivities[_activity.ID]
+		diff.compare(KIND_ACTIVITY, _activity.ID, ok, old.Activity, _activity.Activity)
+		diff.compare(KIND_CHOICES, _activity.ID, ok, normalizeChoices(old.Choices), normalizeChoices(_activity.Choices))
+		delete(currentActivities, _activity.ID)
+
+		for _, hint := range _activity.Hints {
+			oldHint, ok := currentHints[hint.ID]
+			diff.compare(KIND_HINT, hint.ID, ok, oldHint, hint)
+			delete(currentHints, hint.ID)
+		}
+	}
+
+	currentExams := map[int]Exam{}
+	for _, exam := range current.Exams {
+		currentExams[exam.ID] = exam
+	}
+	for _, exam := range bundle.Exams {
+		old, ok := currentExams[exam.ID]
+		oldActivities := old.Activities
+		newActivities := exam.Activities
+		old.Activities, exam.Activities = nil, nil
+		diff.compare(KIND_EXAM, exam.ID, ok, old, exam)
+		diff.compare(KIND_EXAM_ACTIVITIES, exam.ID, ok, oldActivities, newActivities)
+		delete(currentExams, exam.ID)
+	}
+
+	currentPrerequisites := map[int]content.Prerequisite{}
+	for _, prerequisite := range current.Prerequisites {
+		currentPrerequisites[prerequisite.ID] = prerequisite
+	}
+	for _, prerequisite := range bundle.Prerequisites {
+		old, ok := currentPrerequisites[prerequisite.ID]
+		diff.compare(KIND_PREREQUISITE, prerequisite.ID, ok, old, prerequisite)
+		delete(currentPrerequisites, prerequisite.ID)
+	}
+
+	for id := range currentBadges {
+		diff = append(diff, Change{Action: CHANGE_ABSENT, Kind: KIND_BADGE, ID: id})
+	}
+	for id := range currentGroups {
+		diff = append(diff, Change{Action: CHANGE_ABSENT, Kind: KIND_CONTENT_GROUP, ID: id})
+	}
+	for id := range currentContents {
+		diff = append(diff, Change{Action: CHANGE_ABSENT, Kind: KIND_CONTENT, ID: id})
+	}
+	for id := range currentActivities {
+		diff = append(diff, Change{Action: CHANGE_ABSENT, Kind: KIND_ACTIVITY, ID: id})
+	}
+	for id := range currentHints {
+		diff = append(diff, Change{Action: CHANGE_ABSENT, Kind: KIND_HINT, ID: id})
+	}
+	for id := range currentExams {
+		diff = append(diff, Change{Action: CHANGE_ABSENT, Kind: KIND_EXAM, ID: id})
+	}
+	for id := range currentPrerequisites {
+		diff = append(diff, Change{Action: CHANGE_ABSENT, Kind: KIND_PREREQUISITE, ID: id})
+	}
+
+	diff.sort()
+
+	return diff
+}
+
+func (d *Diff) compare(kind string, id int, exists bool, old interface{}, new interface{}) {
+	if !exists {
+		*d = append(*d, Change{Action: CHANGE_CREATE, Kind: kind, ID: id})
+		return
+	}
+
+	oldData, _ := json.Marshal(old)
+	newData, _ := json.Marshal(new)
+
+	if string(oldData) != string(newData) {
+		*d = append(*d, Change{Action: CHANGE_UPDATE, Kind: kind, ID: id})
+	}
+}
+
+func (d Diff) sort() {
+	order := map[string]int{}
+	for i, kind := range []string{KIND_BADGE, KIND_CONTENT_GROUP, KIND_CONTENT, KIND_ACTIVITY, KIND_CHOICES, KIND_HINT, KIND_EXAM, KIND_EXAM_ACTIVITIES, KIND_PREREQUISITE} {
+		order[kind] = i
+	}
+
+	less := func(a Change, b Change) bool {
+		if a.Action != b.Action {
+			return a.Action < b.Action
+		}
+		if a.Kind != b.Kind {
+			return order[a.Kind] < order[b.Kind]
+		}
+		return a.ID < b.ID
+	}
+
+	sort.Slice(d, func(i, j int) bool { return less(d[i], d[j]) })
+}
+
+// normalizeChoices drops the ids of choice rows, they are given by the
+// database on insert and a bundle may leave them out, and sorts the rest.
+func normalizeChoices(choices interface{}) interface{} {
+	switch choices := choices.(type) {
+	case activity.MatchingChoices:
+		normalized := make(activity.MatchingChoices, len(choices))
+		for i, choice := range choices {
+			choice.ID = 0
+			normalized[i] = choice
+		}
+		return normalized
+	case activity.MultipleChoices:
+		normalized := make(activity.MultipleChoices, len(choices))
+		for i, choice := range choices {
+			choice.ID = 0
+			normalized[i] = choice
+		}
+		return normalized
+	case activity.CompletionChoices:
+		normalized := make(activity.CompletionChoices, len(choices))
+		for i, choice := range choices {
+			choice.ID = 0
+			normalized[i] = choice
+		}
+		return normalized
+	}
+	return SortChoices(choices)
+}
@@ -2,6 +2,7 @@ package exam
 
 import (
 	"database-camp/internal/models/entities/activity"
+	"database-camp/internal/models/entities/content"
 	"time"
 )
 
@@ -13,6 +14,7 @@ type ExamActivityAnswer struct {
 type ExamActivity struct {
 	ActivityID     int `gorm:"column:activity_id"`
 	ActivityTypeID int `gorm:"column:activity_type_id"`
+	ContentGroupID int `gorm:"column:content_group_id"`
 }
 
 type ExamActivities []ExamActivity
@@ -20,56 +22,80 @@ type ExamActivities []ExamActivity
 type Activity struct {
 	activity.Activity
 	activity.Choices
+	ContentGroupID int
 }
 
 type Activities []Activity
 
-func (activities Activities) CheckAnswers(examID int, userID int, answers []ExamActivityAnswer) (*Result, error) {
+// CheckAnswers grades every answer and keeps it with its result, an activity
+// scores its point scaled by the fraction of the answer that is right. An
+// activity of no content is evidence for every content of its exam group.
+func (activities Activities) CheckAnswers(examID int, userID int, answers []ExamActivityAnswer, groupContentIDs map[int][]int) (*Result, error) {
 	answerScore := 0
 	totalScore := 0
 
 	activitiesResult := make([]ResultActivity, 0)
+	observations := make([]content.MasteryObservation, 0)
 
 	for _, examActivity := range activities {
 		for _, answer := range answers {
 			if examActivity.Activity.ID == answer.ActivityID {
-				formatedAnswer, err := activity.FormatAnswer(answer.Answer, examActivity.Activity.TypeID)
+				grade, err := activity.GradeAnswer(answer.Answer, examActivity.Activity.TypeID, examActivity.Choices)
 				if err != nil {
 					return nil, err
 				}
 
-				isCorrect, err := formatedAnswer.IsCorrect(examActivity.Choices)
+				submitted, err := activity.EncodeAnswer(answer.Answer, examActivity.Activity.TypeID)
 				if err != nil {
 					return nil, err
 				}
 
-				if isCorrect {
-					answerScore += examActivity.Activity.Point
-				}
+				score := grade.Point(examActivity.Activity.Point)
+				version := activity.ANSWER_VERSION
 
+				answerScore += score
 				totalScore += examActivity.Activity.Point
 
 				activitiesResult = append(activitiesResult, ResultActivity{
-					ActivityID: examActivity.Activity.ID,
-					Score:      answerScore,
+					ActivityID:    examActivity.Activity.ID,
+					Score:         score,
+					Answer:        submitted,
+					AnswerVersion: &version,
+					IsCorrect:     grade.IsCorrect,
+					Items:         grade.Items,
 				})
+
+				contentIDs := groupContentIDs[examActivity.ContentGroupID]
+				if examActivity.Activity.ContentID != nil {
+					contentIDs = []int{*examActivity.Activity.ContentID}
+				}
+
+				for _, contentID := range contentIDs {
+					observations = append(observations, content.MasteryObservation{
+						ContentID: contentID,
+						Score:     grade.Score,
+					})
+				}
 			}
 		}
 	}
 
 	return &Result{
 		ActivitiesResult: activitiesResult,
+		Observations:     observations,
 		ExamResult: ExamResult{
 			ExamID:           examID,
 			UserID:           userID,
 			Score:            answerScore,
-			IsPassed:         isPassed(answerScore, totalScore),
+			IsPassed:         IsPassed(answerScore, totalScore),
 			CreatedTimestamp: time.Now().Local(),
 		},
 	}, nil
 }
 
-func isPassed(answerTotalScore int, activitiesTotalScore int) bool {
+// IsPassed tells whether an exam result is passed. The ratio is an integer
+// division, so only a result scoring every point of its activities passes.
+func IsPassed(answerTotalScore int, activitiesTotalScore int) bool {
 	passedRate := 0.5
 	if activitiesTotalScore == 0 {
 		return true
@@ -1,6 +1,11 @@
 package exam
 
-import "time"
+import (
+	"database-camp/internal/models/entities/activity"
+	"database-camp/internal/models/entities/content"
+	"encoding/json"
+	"time"
+)
 
 type ExamResult struct {
 	ID                   int       `gorm:"primaryKey;column:exam_result_id" json:"exam_result_id"`
@@ -39,15 +44,25 @@ func (results ExamResults) GetResultOverview(examID int) []ResultOverview {
 	return overview
 }
 
+// Result is a checked exam, the observations are the answers as evidence of
+// the mastery of the contents of the activities
 type Result struct {
 	ActivitiesResult ResultActivities
 	ExamResult       ExamResult
+	Observations     []content.MasteryObservation
 }
 
+// ResultActivity is the score of one activity of an exam result with the
+// answer submitted for it, whether it was correct and its items are only
+// known when the exam is checked
 type ResultActivity struct {
-	ExamResultID int `gorm:"primaryKey;column:exam_result_id" json:"exam_result_id"`
-	ActivityID   int `gorm:"primaryKey;column:activity_id" json:"activity_id"`
-	Score        int `gorm:"column:score" json:"score"`
+	ExamResultID  int                   `gorm:"primaryKey;column:exam_result_id" json:"exam_result_id"`
+	ActivityID    int                   `gorm:"primaryKey;column:activity_id" json:"activity_id"`
+	Score         int                   `gorm:"column:score" json:"score"`
+	Answer        json.RawMessage       `gorm:"column:answer" json:"answer,omitempty"`
+	AnswerVersion *int                  `gorm:"column:answer_version" json:"answer_version,omitempty"`
+	IsCorrect     bool                  `gorm:"-" json:"is_correct,omitempty"`
+	Items         []activity.ItemResult `gorm:"-" json:"items,omitempty"`
 }
 
 type ResultActivities []ResultActivity
@@ -57,9 +72,13 @@ func (activities *ResultActivities) SetExamResultID(id int) {
 
 	for _, activity := range *activities {
 		newActivities = append(newActivities, ResultActivity{
-			ExamResultID: id,
-			ActivityID:   activity.ActivityID,
-			Score:        activity.Score,
+			ExamResultID:  id,
+			ActivityID:    activity.ActivityID,
+			Score:         activity.Score,
+			Answer:        activity.Answer,
+			AnswerVersion: activity.AnswerVersion,
+			IsCorrect:     activity.IsCorrect,
+			Items:         activity.Items,
 		})
 	}
 
@@ -0,0 +1,23 @@
+package review
+
+import "time"
+
+const (
+	REGRADE_STATUS_RUNNING  = "running"
+	REGRADE_STATUS_FINISHED = "finished"
+	REGRADE_STATUS_FAILED   = "failed"
+)
+
+// RegradeJob grades the stored answers to one activity again, the counts
+// tell how many answers were graded, how many got a different grade and how
+// many could not be read
+type RegradeJob struct {
+	ID                string     `json:"regrade_job_id"`
+	ActivityID        int        `json:"activity_id"`
+	Status            string     `json:"status"`
+	Regraded          int        `json:"regraded"`
+	Changed           int        `json:"changed"`
+	Skipped           int        `json:"skipped"`
+	StartedTimestamp  time.Time  `json:"started_timestamp"`
+	FinishedTimestamp *time.Time `json:"finished_timestamp"`
+}
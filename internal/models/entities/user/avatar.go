@@ -0,0 +1,29 @@
+package user
+
+import "fmt"
+
+type AvatarSize struct {
+	Name  string
+	Pixel int
+}
+
+// Every uploaded avatar is stored once per size, as a square JPEG
+var AvatarSizes = []AvatarSize{
+	{Name: "small", Pixel: 64},
+	{Name: "medium", Pixel: 128},
+	{Name: "large", Pixel: 256},
+}
+
+// AvatarObjectName is the object name of one size of the avatar stored under
+// the prefix kept in User.Avatar.
+func AvatarObjectName(avatar string, size AvatarSize) string {
+	return fmt.Sprintf("%s_%d.jpg", avatar, size.Pixel)
+}
+
+func AvatarObjectNames(avatar string) []string {
+	objectNames := make([]string, 0, len(AvatarSizes))
+	for _, size := range AvatarSizes {
+		objectNames = append(objectNames, AvatarObjectName(avatar, size))
+	}
+	return objectNames
+}
@@ -0,0 +1,20 @@
+package user
+
+import "time"
+
+// Identity links an account at an OpenID Connect provider to a user.
+type Identity struct {
+	Provider         string    `gorm:"primaryKey;column:provider" json:"provider"`
+	Subject          string    `gorm:"primaryKey;column:subject" json:"-"`
+	UserID           int       `gorm:"column:user_id" json:"user_id"`
+	Email            string    `gorm:"column:email" json:"email"`
+	CreatedTimestamp time.Time `gorm:"column:created_timestamp" json:"created_timestamp"`
+}
+
+// OIDCState is kept between redirecting the user to the provider and the
+// provider redirecting back.
+type OIDCState struct {
+	Provider     string `json:"provider"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+}
@@ -0,0 +1,34 @@
+package user
+
+import "time"
+
+// Reasons of a point transaction, the reference is the activity for the
+// rewards, the hint for a hint debit and the admin for an adjustment
+const (
+	POINT_REASON_OPENING_BALANCE = "opening_balance"
+	POINT_REASON_ACTIVITY        = "activity"
+	POINT_REASON_PEER_REVIEW     = "peer_review"
+	POINT_REASON_REGRADE         = "regrade"
+	POINT_REASON_HINT            = "hint"
+	POINT_REASON_ADJUSTMENT      = "adjustment"
+)
+
+// PointTransaction is one change of the point of a user, the point of a user
+// is the sum of the amounts and the balance is the point after the change
+type PointTransaction struct {
+	ID               int       `gorm:"primaryKey;column:point_transaction_id" json:"point_transaction_id"`
+	UserID           int       `gorm:"column:user_id" json:"-"`
+	Amount           int       `gorm:"column:amount" json:"amount"`
+	Balance          int       `gorm:"column:balance" json:"balance"`
+	Reason           string    `gorm:"column:reason" json:"reason"`
+	ReferenceID      *int      `gorm:"column:reference_id" json:"reference_id"`
+	Note             string    `gorm:"column:note" json:"note,omitempty"`
+	CreatedTimestamp time.Time `gorm:"column:created_timestamp" json:"created_timestamp"`
+}
+
+// PointDiscrepancy is a user whose point is not the sum of the ledger
+type PointDiscrepancy struct {
+	UserID      int `gorm:"column:user_id" json:"user_id"`
+	Point       int `gorm:"column:point" json:"point"`
+	LedgerPoint int `gorm:"column:ledger_point" json:"ledger_point"`
+}
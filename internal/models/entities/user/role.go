@@ -0,0 +1,25 @@
+package user
+
+type Role string
+
+const (
+	STUDENT    Role = "student"
+	INSTRUCTOR Role = "instructor"
+	ADMIN      Role = "admin"
+)
+
+var roles = []Role{STUDENT, INSTRUCTOR, ADMIN}
+
+type UserRole struct {
+	UserID int    `gorm:"primaryKey;column:user_id" json:"user_id"`
+	Role   string `gorm:"primaryKey;column:role" json:"role"`
+}
+
+func IsValidRole(role string) bool {
+	for _, r := range roles {
+		if string(r) == role {
+			return true
+		}
+	}
+	return false
+}
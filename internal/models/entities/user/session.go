@@ -0,0 +1,18 @@
+package user
+
+import "time"
+
+type Session struct {
+	ID               int       `gorm:"primaryKey;column:session_id" json:"session_id"`
+	UserID           int       `gorm:"column:user_id" json:"-"`
+	UserAgent        string    `gorm:"column:user_agent" json:"user_agent"`
+	IPAddress        string    `gorm:"column:ip_address" json:"ip_address"`
+	CreatedTimestamp time.Time `gorm:"column:created_timestamp" json:"created_timestamp"`
+}
+
+type Sessions []Session
+
+type SessionRef struct {
+	UserID    int `json:"user_id"`
+	SessionID int `json:"session_id"`
+}
@@ -4,29 +4,97 @@ import (
 	"time"
 )
 
+const (
+	LANGUAGE_TH = "th"
+	LANGUAGE_EN = "en"
+)
+
+var languages = []string{LANGUAGE_TH, LANGUAGE_EN}
+
+// Who can see the profile of a user, the owner always can
+const (
+	PROFILE_PUBLIC    = "public"
+	PROFILE_LOGGED_IN = "logged_in"
+	PROFILE_PRIVATE   = "private"
+)
+
+var profileVisibilities = []string{PROFILE_PUBLIC, PROFILE_LOGGED_IN, PROFILE_PRIVATE}
+
 type User struct {
-	ID                    int       `gorm:"primaryKey;column:user_id"`
-	Name                  string    `gorm:"column:name"`
-	Email                 string    `gorm:"column:email"`
-	Password              string    `gorm:"column:password"`
-	AccessToken           string    `gorm:"column:access_token"`
-	Point                 int       `gorm:"column:point"`
-	ExpiredTokenTimestamp time.Time `gorm:"column:expired_token_timestamp"`
-	CreatedTimestamp      time.Time `gorm:"column:created_timestamp"`
-	UpdatedTimestamp      time.Time `gorm:"column:updated_timestamp"`
+	ID                int        `gorm:"primaryKey;column:user_id"`
+	Name              string     `gorm:"column:name"`
+	Email             string     `gorm:"column:email"`
+	Password          string     `gorm:"column:password"`
+	Point             int        `gorm:"column:point"`
+	Avatar            string     `gorm:"column:avatar"`
+	Bio               string     `gorm:"column:bio"`
+	Language          string     `gorm:"column:language;default:th"`
+	ProfileVisibility string     `gorm:"column:profile_visibility;default:logged_in"`
+	HideFromRanking   bool       `gorm:"column:hide_from_ranking"`
+	VerifiedTimestamp *time.Time `gorm:"column:verified_timestamp"`
+	DeletionTimestamp *time.Time `gorm:"column:deletion_timestamp"`
+	CreatedTimestamp  time.Time  `gorm:"column:created_timestamp"`
+	UpdatedTimestamp  time.Time  `gorm:"column:updated_timestamp"`
+}
+
+func (u User) IsVerified() bool {
+	return u.VerifiedTimestamp != nil
+}
+
+func (u User) IsDeletionScheduled() bool {
+	return u.DeletionTimestamp != nil
+}
+
+func IsValidLanguage(language string) bool {
+	for _, l := range languages {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
+
+func IsValidProfileVisibility(visibility string) bool {
+	for _, v := range profileVisibilities {
+		if v == visibility {
+			return true
+		}
+	}
+	return false
 }
 
 type Profile struct {
-	ID               int       `gorm:"primaryKey;column:user_id"`
-	Name             string    `gorm:"column:name"`
-	Point            int       `gorm:"column:point"`
-	ActivityCount    int       `gorm:"column:activity_count"`
-	CreatedTimestamp time.Time `gorm:"column:created_timestamp"`
+	ID                int       `gorm:"primaryKey;column:user_id"`
+	Name              string    `gorm:"column:name"`
+	Point             int       `gorm:"column:point"`
+	Avatar            string    `gorm:"column:avatar"`
+	Bio               string    `gorm:"column:bio"`
+	Language          string    `gorm:"column:language"`
+	ProfileVisibility string    `gorm:"column:profile_visibility"`
+	HideFromRanking   bool      `gorm:"column:hide_from_ranking"`
+	ActivityCount     int       `gorm:"column:activity_count"`
+	CreatedTimestamp  time.Time `gorm:"column:created_timestamp"`
+}
+
+// IsVisibleTo reports whether the viewer may see the profile, viewerID is zero
+// for a visitor who is not logged in.
+func (p Profile) IsVisibleTo(viewerID int) bool {
+	switch {
+	case viewerID != 0 && viewerID == p.ID:
+		return true
+	case p.ProfileVisibility == PROFILE_PUBLIC:
+		return true
+	case p.ProfileVisibility == PROFILE_LOGGED_IN:
+		return viewerID != 0
+	default:
+		return false
+	}
 }
 
 type Ranking struct {
 	ID      int    `gorm:"primaryKey;column:user_id" json:"user_id"`
 	Name    string `gorm:"column:name" json:"name"`
 	Point   int    `gorm:"column:point" json:"point"`
+	Avatar  string `gorm:"column:avatar" json:"avatar"`
 	Ranking int    `gorm:"column:ranking" json:"ranking"`
 }
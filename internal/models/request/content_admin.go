@@ -0,0 +1,196 @@
+package request
+
+// request.content_admin.go
+/**
+ * 	This file is a part of models, used to collect request of content authoring
+ */
+
+import (
+	"database-camp/internal/errs"
+	"database-camp/internal/models/entities/activity"
+	"database-camp/internal/models/entities/content"
+	"strings"
+)
+
+/**
+ * 	This class represent request to create or replace a content group
+ */
+type ContentGroupRequest struct {
+	Name       *string `json:"name"`
+	BadgeID    *int    `json:"badge_id"`
+	MiniExamID *int    `json:"mini_exam_id"`
+}
+
+/**
+ * Validate content group request
+ *
+ * @return the error of validating request
+ */
+func (r ContentGroupRequest) Validate() error {
+	if r.Name == nil || strings.TrimSpace(*r.Name) == "" {
+		return errs.ErrNameNotFound
+	} else if r.BadgeID == nil {
+		return errs.ErrBadgeIDNotFound
+	} else if r.MiniExamID == nil {
+		return errs.ErrMiniExamIDNotFound
+	}
+	return nil
+}
+
+/**
+ * 	This class represent request to create or replace a content
+ */
+type ContentRequest struct {
+	GroupID   *int    `json:"content_group_id"`
+	Name      *string `json:"name"`
+	VideoPath string  `json:"video_path"`
+	SlidePath string  `json:"slide"`
+}
+
+/**
+ * Validate content request
+ *
+ * @return the error of validating request
+ */
+func (r ContentRequest) Validate() error {
+	if r.GroupID == nil {
+		return errs.ErrContentGroupIDNotFound
+	} else if r.Name == nil || strings.TrimSpace(*r.Name) == "" {
+		return errs.ErrNameNotFound
+	}
+	return nil
+}
+
+/**
+ * 	This class represent request to create or replace an activity with its choices
+ */
+type ActivityRequest struct {
+	TypeID    *int        `json:"activity_type_id"`
+	ContentID *int        `json:"content_id"`
+	Order     int         `json:"activity_order"`
+	Story     string      `json:"story"`
+	Point     *int        `json:"point"`
+	Question  *string     `json:"question"`
+	Choices   interface{} `json:"choices"`
+}
+
+/**
+ * Validate activity request, the choices must match the activity type
+ *
+ * @return the error of validating request
+ */
+func (r ActivityRequest) Validate() error {
+	if r.TypeID == nil {
+		return errs.ErrActivittyTypeIDNotFound
+	} else if r.Point == nil || *r.Point < 0 {
+		return errs.ErrPointInvalid
+	} else if r.Question == nil || strings.TrimSpace(*r.Question) == "" {
+		return errs.ErrQuestionNotFound
+	}
+
+	_, err := r.ParseChoices()
+	return err
+}
+
+/**
+ * Decode the choices into the structure of the activity type and check it
+ *
+ * @return the choices the activity type is authored with, or nil if the type
+ *         has no choices of its own
+ */
+func (r ActivityRequest) ParseChoices() (interface{}, error) {
+	return activity.ParseChoices(r.Choices, *r.TypeID)
+}
+
+func isBlank(s string) bool {
+	return strings.TrimSpace(s) == ""
+}
+
+/**
+ * 	This class represent request to create or replace a hint of an activity
+ */
+type HintRequest struct {
+	ActivityID  *int    `json:"activity_id"`
+	Content     *string `json:"content"`
+	PointReduce *int    `json:"point_reduce"`
+	Level       *int    `json:"level"`
+}
+
+/**
+ * Validate hint request
+ *
+ * @return the error of validating request
+ */
+func (r HintRequest) Validate() error {
+	if r.ActivityID == nil {
+		return errs.ErrActivittyIDNotFound
+	} else if r.Content == nil || isBlank(*r.Content) {
+		return errs.ErrHintContentNotFound
+	} else if r.PointReduce == nil || *r.PointReduce < 0 {
+		return errs.ErrPointInvalid
+	} else if r.Level == nil || *r.Level < 1 {
+		return errs.ErrHintLevelInvalid
+	}
+	return nil
+}
+
+/**
+ * 	This class represent request to lock a content or content group behind a
+ * 	prerequisite
+ */
+type PrerequisiteRequest struct {
+	TargetType *string `json:"target_type"`
+	TargetID   *int    `json:"target_id"`
+	Rule       *string `json:"rule"`
+	RequiredID *int    `json:"required_id"`
+	Count      int     `json:"count"`
+}
+
+/**
+ * Validate prerequisite request, only a complete activities rule has a count
+ *
+ * @return the error of validating request
+ */
+func (r PrerequisiteRequest) Validate() error {
+	if r.TargetType == nil || !content.IsValidPrerequisiteTarget(*r.TargetType) || r.TargetID == nil {
+		return errs.ErrPrerequisiteInvalid
+	} else if r.Rule == nil || !content.IsValidUnlockRule(*r.Rule) || r.RequiredID == nil {
+		return errs.ErrPrerequisiteInvalid
+	} else if r.Count < 0 || (r.Count > 0 && *r.Rule != content.UNLOCK_RULE_COMPLETE_ACTIVITIES) {
+		return errs.ErrPrerequisiteInvalid
+	}
+	return nil
+}
+
+// Attachments are read into memory before they are uploaded
+const MAX_ATTACHMENT_BYTES = 16 * 1024 * 1024
+
+// Room for an attachment and the rest of its form
+const MAX_ATTACHMENT_FORM_BYTES = MAX_ATTACHMENT_BYTES + 1024*1024
+
+/**
+ * 	This class represent request to attach a file to a content
+ */
+type AttachmentRequest struct {
+	Type        string
+	Name        string
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+/**
+ * Validate attachment request
+ *
+ * @return the error of validating request
+ */
+func (r AttachmentRequest) Validate() error {
+	if len(r.Data) == 0 || r.FileName == "" {
+		return errs.ErrAttachmentFileNotFound
+	} else if len(r.Data) > MAX_ATTACHMENT_BYTES {
+		return errs.ErrAttachmentTooLarge
+	} else if !content.IsValidAttachmentType(r.Type) || strings.TrimSpace(r.Name) == "" {
+		return errs.ErrAttachmentInvalid
+	}
+	return nil
+}
@@ -34,3 +34,41 @@ func (r PeerReviewRequest) Validate() error {
 		return nil
 	}
 }
+
+const (
+	DEFAULT_REVIEW_LIMIT = 10
+	MAX_REVIEW_LIMIT     = 50
+)
+
+type ReviewQueueRequest struct {
+	Limit int
+}
+
+func (r *ReviewQueueRequest) Validate() error {
+	if r.Limit == 0 {
+		r.Limit = DEFAULT_REVIEW_LIMIT
+	}
+	if r.Limit < 0 || r.Limit > MAX_REVIEW_LIMIT {
+		return errs.ErrBadRequestError
+	}
+	return nil
+}
+
+const (
+	DEFAULT_RECOMMEND_LIMIT = 5
+	MAX_RECOMMEND_LIMIT     = 20
+)
+
+type RecommendRequest struct {
+	Limit int
+}
+
+func (r *RecommendRequest) Validate() error {
+	if r.Limit == 0 {
+		r.Limit = DEFAULT_RECOMMEND_LIMIT
+	}
+	if r.Limit < 0 || r.Limit > MAX_RECOMMEND_LIMIT {
+		return errs.ErrBadRequestError
+	}
+	return nil
+}
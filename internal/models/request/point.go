@@ -0,0 +1,49 @@
+package request
+
+import (
+	"database-camp/internal/errs"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	DEFAULT_POINT_HISTORY_LIMIT = 20
+	MAX_POINT_HISTORY_LIMIT     = 100
+	MAX_POINT_NOTE_LENGTH       = 255
+)
+
+type PointHistoryRequest struct {
+	Limit  int
+	Offset int
+}
+
+func (r *PointHistoryRequest) Validate() error {
+	if r.Limit == 0 {
+		r.Limit = DEFAULT_POINT_HISTORY_LIMIT
+	}
+	if r.Limit < 0 || r.Limit > MAX_POINT_HISTORY_LIMIT || r.Offset < 0 {
+		return errs.ErrBadRequestError
+	}
+	return nil
+}
+
+/**
+ * 	This class represent an admin changing the point of a user
+ */
+type PointAdjustmentRequest struct {
+	Amount int    `json:"amount"`
+	Note   string `json:"note"`
+}
+
+/**
+ * Validate point adjustment request, every adjustment has to say why
+ *
+ * @return the error of validating request
+ */
+func (r *PointAdjustmentRequest) Validate() error {
+	r.Note = strings.TrimSpace(r.Note)
+	if r.Amount == 0 || r.Note == "" || utf8.RuneCountInString(r.Note) > MAX_POINT_NOTE_LENGTH {
+		return errs.ErrPointAdjustmentInvalid
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package request
+
+import "database-camp/internal/errs"
+
+const (
+	EXPORT_FORMAT_JSON = "json"
+	EXPORT_FORMAT_ZIP  = "zip"
+)
+
+type DataExportRequest struct {
+	Format string
+}
+
+func (r *DataExportRequest) Validate() error {
+	if r.Format == "" {
+		r.Format = EXPORT_FORMAT_JSON
+	}
+	if r.Format != EXPORT_FORMAT_JSON && r.Format != EXPORT_FORMAT_ZIP {
+		return errs.ErrExportFormatInvalid
+	}
+	return nil
+}
+
+func (r DataExportRequest) IsZip() bool {
+	return r.Format == EXPORT_FORMAT_ZIP
+}
@@ -0,0 +1,24 @@
+package request
+
+import "database-camp/internal/errs"
+
+const (
+	DEFAULT_ATTEMPTS_LIMIT = 20
+	MAX_ATTEMPTS_LIMIT     = 100
+)
+
+type AttemptsRequest struct {
+	UserID *int
+	Limit  int
+	Offset int
+}
+
+func (r *AttemptsRequest) Validate() error {
+	if r.Limit == 0 {
+		r.Limit = DEFAULT_ATTEMPTS_LIMIT
+	}
+	if r.Limit < 0 || r.Limit > MAX_ATTEMPTS_LIMIT || r.Offset < 0 {
+		return errs.ErrBadRequestError
+	}
+	return nil
+}
@@ -7,7 +7,12 @@ package request
 
 import (
 	"database-camp/internal/errs"
+	"database-camp/internal/models/entities/user"
 	"database-camp/internal/utils"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 /**
@@ -28,8 +33,8 @@ func (r UserRequest) ValidateRegister() error {
 	var err error
 	if r.Name == "" {
 		err = errs.NewBadRequestError("ไม่พบชื่อในคำร้องขอ", "Name Not Found")
-	} else {
-		err = r.ValidateLogin()
+	} else if err = r.validateEmail(); err == nil {
+		err = validatePassword(r.Password)
 	}
 	return err
 }
@@ -40,28 +45,305 @@ func (r UserRequest) ValidateRegister() error {
  * @return the error of validating request
  */
 func (r UserRequest) ValidateLogin() error {
+	err := r.validateEmail()
+	if err == nil && r.Password == "" {
+		err = errs.ErrPasswordNotFound
+	}
+	return err
+}
+
+func (r UserRequest) validateEmail() error {
 	var err error
 	if r.Email == "" {
 		err = errs.NewBadRequestError("ไม่พบอีเมลในคำร้องขอ", "Email Not Found")
 	} else if !utils.IsEmailValid(r.Email) {
 		err = errs.NewBadRequestError("รูปแบบ email ไม่ถูกต้อง", "Email Invalid")
-	} else if r.Password == "" {
-		err = errs.NewBadRequestError("ไม่พบรหัสผ่านในคำร้องขอ", "Password Not Found")
-	} else if len(r.Password) < 8 {
-		err = errs.NewBadRequestError("ความยาวของรหัสผ่านต้องมีอย่างน้อย 8 ตัวอักษร", "Password length must be at least 8 characters")
 	}
 	return err
 }
 
+/**
+ * Validate new password against the password policy, login only checks that
+ * a password is given so tightening the policy never locks anyone out
+ *
+ * @param password password to validate
+ *
+ * @return the error of validating password
+ */
+func validatePassword(password string) error {
+	policy := utils.GetPasswordPolicy()
+
+	var err error
+	if password == "" {
+		err = errs.ErrPasswordNotFound
+	} else if length := utf8.RuneCountInString(password); length < policy.MinLength {
+		err = errs.NewBadRequestError(
+			fmt.Sprintf("ความยาวของรหัสผ่านต้องมีอย่างน้อย %d ตัวอักษร", policy.MinLength),
+			fmt.Sprintf("Password length must be at least %d characters", policy.MinLength),
+		)
+	} else if len(password) > policy.MaxBytes {
+		err = errs.NewBadRequestError(
+			fmt.Sprintf("รหัสผ่านต้องยาวไม่เกิน %d ไบต์", policy.MaxBytes),
+			fmt.Sprintf("Password must be at most %d bytes long", policy.MaxBytes),
+		)
+	} else if policy.RequireUpper && !strings.ContainsAny(password, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+		err = errs.NewBadRequestError("รหัสผ่านต้องมีตัวอักษรพิมพ์ใหญ่", "Password must contain an uppercase letter")
+	} else if policy.RequireLower && !strings.ContainsAny(password, "abcdefghijklmnopqrstuvwxyz") {
+		err = errs.NewBadRequestError("รหัสผ่านต้องมีตัวอักษรพิมพ์เล็ก", "Password must contain a lowercase letter")
+	} else if policy.RequireDigit && !strings.ContainsAny(password, "0123456789") {
+		err = errs.NewBadRequestError("รหัสผ่านต้องมีตัวเลข", "Password must contain a digit")
+	} else if policy.RequireSymbol && strings.IndexFunc(password, isSymbol) < 0 {
+		err = errs.NewBadRequestError("รหัสผ่านต้องมีอักขระพิเศษ", "Password must contain a symbol")
+	}
+	return err
+}
+
+func isSymbol(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r)
+}
+
+const (
+	MAX_BIO_LENGTH   = 500
+	MAX_AVATAR_BYTES = 2 * 1024 * 1024
+)
+
+/**
+ * 	This class represent edit user profile request, fields left out are not
+ *  changed
+ */
+type EditProfileRequest struct {
+	Name              *string `json:"name"`
+	Bio               *string `json:"bio"`
+	Language          *string `json:"language"`
+	ProfileVisibility *string `json:"profile_visibility"`
+	HideFromRanking   *bool   `json:"hide_from_ranking"`
+}
+
 /**
  * Validate edit user profile request
  *
  * @return the error of validating request
  */
-func (r UserRequest) ValidateEdit() error {
+func (r EditProfileRequest) Validate() error {
 	var err error
-	if r.Name == "" {
+	if r.Name == nil && r.Bio == nil && r.Language == nil && r.ProfileVisibility == nil && r.HideFromRanking == nil {
+		err = errs.ErrProfileNotChanged
+	} else if r.Name != nil && strings.TrimSpace(*r.Name) == "" {
 		err = errs.NewBadRequestError("ไม่พบชื่อในคำร้องขอ", "Name Not Found")
+	} else if r.Bio != nil && utf8.RuneCountInString(*r.Bio) > MAX_BIO_LENGTH {
+		err = errs.ErrBioTooLong
+	} else if r.Language != nil && !user.IsValidLanguage(*r.Language) {
+		err = errs.ErrLanguageInvalid
+	} else if r.ProfileVisibility != nil && !user.IsValidProfileVisibility(*r.ProfileVisibility) {
+		err = errs.ErrProfileVisibilityInvalid
+	}
+	return err
+}
+
+/**
+ * 	This class represent avatar upload request
+ */
+type AvatarRequest struct {
+	Data []byte
+}
+
+/**
+ * Validate avatar upload request
+ *
+ * @return the error of validating request
+ */
+func (r AvatarRequest) Validate() error {
+	var err error
+	if len(r.Data) == 0 {
+		err = errs.ErrAvatarNotFound
+	} else if len(r.Data) > MAX_AVATAR_BYTES {
+		err = errs.ErrAvatarTooLarge
+	}
+	return err
+}
+
+/**
+ * 	This class represent the client that sent the request
+ */
+type Client struct {
+	UserAgent string
+	IPAddress string
+}
+
+/**
+ * 	This class represent refresh token request
+ */
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+/**
+ * Validate refresh token request
+ *
+ * @return the error of validating request
+ */
+func (r RefreshTokenRequest) Validate() error {
+	if r.RefreshToken == "" {
+		return errs.ErrRefreshTokenNotFound
+	}
+	return nil
+}
+
+/**
+ * 	This class represent password reset request
+ */
+type PasswordResetRequest struct {
+	Email string `json:"email"`
+}
+
+/**
+ * Validate password reset request
+ *
+ * @return the error of validating request
+ */
+func (r PasswordResetRequest) Validate() error {
+	var err error
+	if r.Email == "" {
+		err = errs.NewBadRequestError("ไม่พบอีเมลในคำร้องขอ", "Email Not Found")
+	} else if !utils.IsEmailValid(r.Email) {
+		err = errs.NewBadRequestError("รูปแบบ email ไม่ถูกต้อง", "Email Invalid")
+	}
+	return err
+}
+
+/**
+ * 	This class represent password reset confirmation request
+ */
+type PasswordResetConfirmRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+/**
+ * Validate password reset confirmation request
+ *
+ * @return the error of validating request
+ */
+func (r PasswordResetConfirmRequest) Validate() error {
+	if r.Token == "" {
+		return errs.ErrPasswordResetTokenNotFound
+	}
+	return validatePassword(r.Password)
+}
+
+/**
+ * 	This class represent email verification request
+ */
+type EmailVerificationRequest struct {
+	Token string `json:"token"`
+}
+
+/**
+ * Validate email verification request
+ *
+ * @return the error of validating request
+ */
+func (r EmailVerificationRequest) Validate() error {
+	if r.Token == "" {
+		return errs.ErrEmailVerificationTokenNotFound
+	}
+	return nil
+}
+
+/**
+ * 	This class represent request to replace roles of the user
+ */
+type UserRolesRequest struct {
+	Roles []string `json:"roles"`
+}
+
+/**
+ * Validate user roles request
+ *
+ * @return the error of validating request
+ */
+func (r UserRolesRequest) Validate() error {
+	seen := map[string]bool{}
+	for _, role := range r.Roles {
+		if !user.IsValidRole(role) || seen[role] {
+			return errs.ErrRoleInvalid
+		}
+		seen[role] = true
+	}
+	return nil
+}
+
+/**
+ * 	This class represent the callback of OpenID Connect login
+ */
+type OIDCCallbackRequest struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
+/**
+ * Validate OpenID Connect callback request
+ *
+ * @return the error of validating request
+ */
+func (r OIDCCallbackRequest) Validate() error {
+	var err error
+	if r.Code == "" {
+		err = errs.ErrOIDCCodeNotFound
+	} else if r.State == "" {
+		err = errs.ErrOIDCStateInvalid
+	}
+	return err
+}
+
+/**
+ * 	This class represent request to unlock an account locked by failed logins
+ */
+type LoginUnlockRequest struct {
+	Token string `json:"token"`
+}
+
+/**
+ * Validate login unlock request
+ *
+ * @return the error of validating request
+ */
+func (r LoginUnlockRequest) Validate() error {
+	if r.Token == "" {
+		return errs.ErrUnlockTokenNotFound
+	}
+	return nil
+}
+
+/**
+ * 	This class represent change password request
+ */
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+/**
+ * Validate change password request
+ *
+ * @return the error of validating request
+ */
+func (r ChangePasswordRequest) Validate() error {
+	var err error
+	if r.OldPassword == "" {
+		err = errs.ErrPasswordNotFound
+	} else if r.OldPassword == r.NewPassword {
+		err = errs.ErrPasswordNotChanged
+	} else {
+		err = validatePassword(r.NewPassword)
 	}
 	return err
 }
+
+/**
+ * 	This class represent account deletion request, the password is required
+ *  only when the account has one
+ */
+type AccountDeletionRequest struct {
+	Password string `json:"password"`
+}
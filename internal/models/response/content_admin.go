@@ -0,0 +1,40 @@
+package response
+
+import (
+	"database-camp/internal/models/entities/activity"
+	"database-camp/internal/models/entities/content"
+)
+
+// Thai and english message about content authoring
+const (
+	CONTENT_DELETED_TH = "ลบข้อมูลเนื้อหาสำเร็จ"
+	CONTENT_DELETED_EN = "Curriculum data has been deleted"
+)
+
+type ContentGroupListResponse struct {
+	ContentGroups []content.ContentGroup `json:"content_groups"`
+}
+
+type ContentListResponse struct {
+	Contents []content.Content `json:"contents"`
+}
+
+type ActivityListResponse struct {
+	Activities []activity.Activity `json:"activities"`
+}
+
+// AuthoredActivityResponse is the activity as an admin edits it, the choices
+// keep the answers and fixed flags in the shape ActivityRequest accepts.
+type AuthoredActivityResponse struct {
+	Activity activity.Activity `json:"activity"`
+	Choices  interface{}       `json:"choices"`
+	Hints    []activity.Hint   `json:"hints"`
+}
+
+type PrerequisiteListResponse struct {
+	Prerequisites content.Prerequisites `json:"prerequisites"`
+}
+
+type AttachmentStatisticResponse struct {
+	Attachments []content.AttachmentStatistic `json:"attachments"`
+}
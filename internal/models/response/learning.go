@@ -3,8 +3,27 @@ package response
 import (
 	"database-camp/internal/models/entities/activity"
 	"database-camp/internal/models/entities/content"
+	"time"
 )
 
+// Thai and english message about why a content is recommended
+const (
+	RECOMMEND_STRUGGLING_TH = "คุณยังตอบกิจกรรมในบทเรียนนี้ผิดบ่อย ลองทบทวนอีกครั้ง"
+	RECOMMEND_STRUGGLING_EN = "You often answer the activities of this content wrong, try it again"
+
+	RECOMMEND_IN_PROGRESS_TH = "คุณเข้าใจบทเรียนนี้มากขึ้นแล้ว ทำต่ออีกนิดเพื่อให้เชี่ยวชาญ"
+	RECOMMEND_IN_PROGRESS_EN = "You are getting there, a little more practice to master this content"
+
+	RECOMMEND_NOT_STARTED_TH = "บทเรียนถัดไปที่คุณยังไม่ได้เริ่ม"
+	RECOMMEND_NOT_STARTED_EN = "Next content you have not started"
+)
+
+var recommendMessages = map[string][2]string{
+	content.RECOMMEND_REASON_STRUGGLING:  {RECOMMEND_STRUGGLING_TH, RECOMMEND_STRUGGLING_EN},
+	content.RECOMMEND_REASON_IN_PROGRESS: {RECOMMEND_IN_PROGRESS_TH, RECOMMEND_IN_PROGRESS_EN},
+	content.RECOMMEND_REASON_NOT_STARTED: {RECOMMEND_NOT_STARTED_TH, RECOMMEND_NOT_STARTED_EN},
+}
+
 type ContentOverviewResponse struct {
 	PreExam              *int                           `json:"pre_exam_id"`
 	LastedGroup          *content.LastedGroupOverview   `json:"lasted_group"`
@@ -12,9 +31,11 @@ type ContentOverviewResponse struct {
 }
 
 type ContentRoadmapResponse struct {
-	ContentID   int                           `json:"content_id"`
-	ContentName string                        `json:"content_name"`
-	Items       []activity.ContentRoadmapItem `json:"items"`
+	ContentID          int                           `json:"content_id"`
+	ContentName        string                        `json:"content_name"`
+	IsLocked           bool                          `json:"is_locked"`
+	UnmetPrerequisites content.Prerequisites         `json:"unmet_prerequisites,omitempty"`
+	Items              []activity.ContentRoadmapItem `json:"items"`
 }
 
 type VideoLectureResponse struct {
@@ -23,6 +44,16 @@ type VideoLectureResponse struct {
 	VideoLink   string `json:"video_link"`
 }
 
+type AttachmentListResponse struct {
+	ContentID   int                  `json:"content_id"`
+	Attachments []content.Attachment `json:"attachments"`
+}
+
+type AttachmentLinkResponse struct {
+	content.Attachment
+	DownloadLink string `json:"download_link"`
+}
+
 type ActivityResponse struct {
 	Activity activity.Activity      `json:"activity"`
 	Choices  interface{}            `json:"choice"`
@@ -30,12 +61,55 @@ type ActivityResponse struct {
 }
 
 type AnswerResponse struct {
-	ActivityID   int     `json:"activity_id"`
-	IsCorrect    bool    `json:"is_correct"`
-	UpdatedPoint int     `json:"updated_point"`
-	ErrMessage   *string `json:"err_message"`
+	ActivityID   int                   `json:"activity_id"`
+	IsCorrect    bool                  `json:"is_correct"`
+	Score        float64               `json:"score"`
+	Items        []activity.ItemResult `json:"items,omitempty"`
+	UpdatedPoint int                   `json:"updated_point"`
+	ErrMessage   *string               `json:"err_message"`
+	Feedback     interface{}           `json:"feedback,omitempty"`
+	NextReview   *time.Time            `json:"next_review_timestamp,omitempty"`
 }
 
 type UsedHintResponse struct {
 	HintDB activity.Hint `json:"hint"`
 }
+
+// ReviewItem is a due activity rendered like GetActivity renders it, it is
+// answered through the check answer endpoint
+type ReviewItem struct {
+	Activity activity.Activity      `json:"activity"`
+	Choices  interface{}            `json:"choice"`
+	Schedule content.ReviewSchedule `json:"schedule"`
+}
+
+type ReviewQueueResponse struct {
+	TotalDue   int64        `json:"total_due"`
+	Items      []ReviewItem `json:"items"`
+	NextReview *time.Time   `json:"next_review_timestamp"`
+}
+
+type RecommendItem struct {
+	content.Recommendation
+	ThMessage string `json:"th_message"`
+	EnMessage string `json:"en_message"`
+}
+
+type RecommendResponse struct {
+	Recommendations []RecommendItem `json:"recommendations"`
+}
+
+// NewRecommendResponse explains each recommendation in Thai and english
+func NewRecommendResponse(recommendations []content.Recommendation) RecommendResponse {
+	items := make([]RecommendItem, 0, len(recommendations))
+	for _, recommendation := range recommendations {
+		messages := recommendMessages[recommendation.Reason]
+		items = append(items, RecommendItem{
+			Recommendation: recommendation,
+			ThMessage:      messages[0],
+			EnMessage:      messages[1],
+		})
+	}
+
+	return RecommendResponse{Recommendations: items}
+}
@@ -0,0 +1,8 @@
+package response
+
+import "database-camp/internal/models/entities/user"
+
+type PointHistoryResponse struct {
+	Point        int                     `json:"point"`
+	Transactions []user.PointTransaction `json:"transactions"`
+}
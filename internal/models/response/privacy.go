@@ -0,0 +1,63 @@
+package response
+
+import (
+	"database-camp/internal/models/entities/activity"
+	"database-camp/internal/models/entities/badge"
+	"database-camp/internal/models/entities/content"
+	"database-camp/internal/models/entities/exam"
+	"database-camp/internal/models/entities/user"
+	"time"
+)
+
+// Thai and english message about account deletion
+const (
+	ACCOUNT_DELETION_SCHEDULED_TH = "บัญชีจะถูกลบตามวันที่กำหนด หากเปลี่ยนใจสามารถเข้าสู่ระบบอีกครั้งเพื่อยกเลิกการลบ"
+	ACCOUNT_DELETION_SCHEDULED_EN = "Account will be deleted on the given date, log in again before then to cancel"
+)
+
+// ExportedUser is the account as exported to its owner, without the password hash.
+type ExportedUser struct {
+	ID                int        `json:"user_id"`
+	Name              string     `json:"name"`
+	Email             string     `json:"email"`
+	Point             int        `json:"point"`
+	Bio               string     `json:"bio"`
+	Language          string     `json:"language"`
+	ProfileVisibility string     `json:"profile_visibility"`
+	HideFromRanking   bool       `json:"hide_from_ranking"`
+	Roles             []string   `json:"roles"`
+	VerifiedTimestamp *time.Time `json:"verified_timestamp"`
+	DeletionTimestamp *time.Time `json:"deletion_timestamp"`
+	CreatedTimestamp  time.Time  `json:"created_timestamp"`
+	UpdatedTimestamp  time.Time  `json:"updated_timestamp"`
+}
+
+type DataExportResponse struct {
+	User                 ExportedUser                  `json:"user"`
+	Identities           []user.Identity               `json:"identities"`
+	Sessions             []user.Session                `json:"sessions"`
+	LearningProgressions []content.LearningProgression `json:"learning_progressions"`
+	ReviewSchedules      []content.ReviewSchedule      `json:"review_schedules"`
+	Masteries            []content.Mastery             `json:"masteries"`
+	AttachmentDownloads  []content.AttachmentDownload  `json:"attachment_downloads"`
+	UserHints            []activity.UserHint           `json:"user_hints"`
+	PointTransactions    []user.PointTransaction       `json:"point_transactions"`
+	UserBadges           []badge.UserBadge             `json:"user_badges"`
+	ExamResults          []exam.ExamResult             `json:"exam_results"`
+	ExamResultActivities []exam.ResultActivity         `json:"exam_result_activities"`
+	ERAnswers            []activity.ERAnswer           `json:"er_answers"`
+	ExportedTimestamp    time.Time                     `json:"exported_timestamp"`
+}
+
+// FileResponse is sent as a download instead of a JSON body.
+type FileResponse struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+type AccountDeletionResponse struct {
+	ThMessage         string    `json:"th_message"`
+	EnMessage         string    `json:"en_message"`
+	DeletionTimestamp time.Time `json:"deletion_timestamp"`
+}
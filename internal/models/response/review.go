@@ -0,0 +1,8 @@
+package response
+
+import "database-camp/internal/models/entities/content"
+
+type AttemptsResponse struct {
+	ActivityID int                           `json:"activity_id"`
+	Attempts   []content.LearningProgression `json:"attempts"`
+}
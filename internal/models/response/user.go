@@ -6,35 +6,123 @@ import (
 	"time"
 )
 
+// Thai and english message about password reset
+const (
+	PASSWORD_RESET_REQUESTED_TH = "หากอีเมลนี้มีอยู่ในระบบ เราได้ส่งลิงก์สำหรับรีเซ็ตรหัสผ่านไปแล้ว"
+	PASSWORD_RESET_REQUESTED_EN = "If the email exists, a password reset link has been sent"
+
+	PASSWORD_RESET_TH = "รีเซ็ตรหัสผ่านสำเร็จ"
+	PASSWORD_RESET_EN = "Password has been reset"
+
+	PASSWORD_CHANGED_TH = "เปลี่ยนรหัสผ่านสำเร็จ"
+	PASSWORD_CHANGED_EN = "Password has been changed"
+)
+
+// Thai and english message about email verification
+const (
+	EMAIL_VERIFIED_TH = "ยืนยันอีเมลสำเร็จ"
+	EMAIL_VERIFIED_EN = "Email has been verified"
+
+	EMAIL_VERIFICATION_SENT_TH = "ส่งลิงก์ยืนยันอีเมลแล้ว"
+	EMAIL_VERIFICATION_SENT_EN = "Verification email has been sent"
+)
+
+// Thai and english message about account unlock
+const (
+	ACCOUNT_UNLOCKED_TH = "ปลดล็อกบัญชีสำเร็จ"
+	ACCOUNT_UNLOCKED_EN = "Account has been unlocked"
+)
+
+// Thai and english message about avatar
+const (
+	AVATAR_DELETED_TH = "ลบรูปโปรไฟล์สำเร็จ"
+	AVATAR_DELETED_EN = "Avatar has been deleted"
+)
+
+type MessageResponse struct {
+	ThMessage string `json:"th_message"`
+	EnMessage string `json:"en_message"`
+}
+
 type UserResponse struct {
 	ID               int       `json:"user_id"`
 	Name             string    `json:"name"`
 	Email            string    `json:"email"`
 	Point            int       `json:"point"`
+	IsVerified       bool      `json:"is_verified"`
 	AccessToken      string    `json:"access_token"`
+	RefreshToken     string    `json:"refresh_token"`
 	CreatedTimestamp time.Time `json:"created_timestamp"`
 	UpdatedTimestamp time.Time `json:"updated_timestamp"`
 }
 
+type OIDCLoginResponse struct {
+	AuthURL string `json:"auth_url"`
+	State   string `json:"state"`
+}
+
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type SessionResponse struct {
+	user.Session
+	IsCurrent bool `json:"is_current"`
+}
+
+type SessionsResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
+}
+
+type RevokeSessionResponse struct {
+	SessionID int `json:"session_id"`
+}
+
+type UserRolesResponse struct {
+	UserID int      `json:"user_id"`
+	Roles  []string `json:"roles"`
+}
+
+// AvatarResponse maps every avatar size name to a signed link.
+type AvatarResponse map[string]string
+
 type GetProfileResponse struct {
 	ID               int                `json:"user_id"`
 	Name             string             `json:"name"`
 	Point            int                `json:"point"`
+	Avatar           AvatarResponse     `json:"avatar"`
+	Bio              string             `json:"bio"`
+	Language         string             `json:"language"`
+	Privacy          *PrivacyResponse   `json:"privacy,omitempty"`
 	ActivityCount    int                `json:"activity_count"`
 	Badges           []badge.Badge      `json:"badges"`
 	SpiderDataset    user.SpiderDataset `json:"spider"`
 	CreatedTimestamp time.Time          ` json:"created_timestamp"`
 }
 
+// PrivacyResponse is only shown to the owner of the profile.
+type PrivacyResponse struct {
+	ProfileVisibility string `json:"profile_visibility"`
+	HideFromRanking   bool   `json:"hide_from_ranking"`
+}
+
 type EditProfileResponse struct {
-	UpdatedName string `json:"updated_name"`
+	UpdatedName     string          `json:"updated_name"`
+	UpdatedBio      string          `json:"updated_bio"`
+	UpdatedLanguage string          `json:"updated_language"`
+	UpdatedPrivacy  PrivacyResponse `json:"updated_privacy"`
 }
 
-type RankingResponse struct {
-	UserRanking user.Ranking   `json:"user_ranking"`
-	LeaderBoard []user.Ranking `json:"leader_board"`
+type RankingUserResponse struct {
+	ID      int            `json:"user_id"`
+	Name    string         `json:"name"`
+	Point   int            `json:"point"`
+	Avatar  AvatarResponse `json:"avatar"`
+	Ranking int            `json:"ranking"` // zero while hidden from the ranking
 }
 
-type RecommendResponse struct {
-	Recommend []user.RecommendGroup `json:"recommend_group"`
+type RankingResponse struct {
+	UserRanking RankingUserResponse   `json:"user_ranking"`
+	LeaderBoard []RankingUserResponse `json:"leader_board"`
 }
@@ -1,64 +1,114 @@
 package registry
 
 import (
+	"context"
+	_ "database-camp/internal/activities"
 	"database-camp/internal/handler"
 	"database-camp/internal/infrastructure/cache"
 	"database-camp/internal/infrastructure/database"
+	"database-camp/internal/infrastructure/mailer"
+	"database-camp/internal/infrastructure/oidc"
+	"database-camp/internal/logs"
 	"database-camp/internal/middleware/jwt"
 	"database-camp/internal/repositories"
 	"database-camp/internal/services"
+	"database-camp/internal/utils"
+	"os"
+	"time"
 )
 
+const defaultAccountPurgeIntervalMinute = 60
+
 type middlewares struct {
 	Jwt jwt.Jwt
 }
 
 type handlers struct {
-	UserHandler     handler.UserHandler
-	LearningHandler handler.LearningHandler
-	ExamHandler     handler.ExamHandler
+	UserHandler         handler.UserHandler
+	LearningHandler     handler.LearningHandler
+	ExamHandler         handler.ExamHandler
+	PrivacyHandler      handler.PrivacyHandler
+	ContentAdminHandler handler.ContentAdminHandler
+	ReviewHandler       handler.ReviewHandler
+	PointHandler        handler.PointHandler
 }
 
 type Registry interface {
 	GetMiddlewares() *middlewares
 	GetHandlers() *handlers
+	StartJobs(ctx context.Context)
 }
 
 type registry struct {
 	middlewares middlewares
 	handlers    handlers
+
+	privacyService services.PrivacyService
 }
 
-func Regis() *registry {
+func Regis() (*registry, error) {
 
 	db := database.GetMySqlDBInstance()
 
 	cache := cache.NewRedisClient()
 
+	mailer := mailer.New()
+
 	userRepo := repositories.NewUserRepository(db, cache)
 	learningRepo := repositories.NewLearningRepository(db, cache)
 	examRepo := repositories.NewExamRepository(db, cache)
+	sessionRepo := repositories.NewSessionRepository(db, cache)
+	loginAttemptRepo := repositories.NewLoginAttemptRepository(cache)
+	privacyRepo := repositories.NewPrivacyRepository(db, cache)
+	avatarRepo := repositories.NewAvatarRepository(cache)
+	contentAdminRepo := repositories.NewContentAdminRepository(db, cache)
+	reviewRepo := repositories.NewReviewRepository(db, cache)
+	pointRepo := repositories.NewPointRepository(db, cache)
+	attachmentRepo := repositories.NewAttachmentRepository(cache)
+
+	keyring, err := jwt.LoadKeyring()
+	if err != nil {
+		return nil, err
+	}
+
+	jwt := jwt.New(sessionRepo, userRepo, keyring)
+
+	providers, err := oidc.LoadProviders()
+	if err != nil {
+		return nil, err
+	}
 
-	userService := services.NewUserService(userRepo, learningRepo)
-	learningService := services.NewLearningService(learningRepo, userRepo)
+	userService := services.NewUserService(userRepo, learningRepo, sessionRepo, loginAttemptRepo, avatarRepo, jwt, mailer, providers)
+	learningService := services.NewLearningService(learningRepo, userRepo, examRepo, attachmentRepo)
 	examService := services.NewExamService(examRepo, userRepo, learningRepo, cache)
+	privacyService := services.NewPrivacyService(userRepo, examRepo, sessionRepo, privacyRepo, avatarRepo)
+	contentAdminService := services.NewContentAdminService(contentAdminRepo, learningRepo, attachmentRepo)
+	reviewService := services.NewReviewService(reviewRepo, learningRepo, examRepo, cache)
+	pointService := services.NewPointService(pointRepo, userRepo)
 
 	userHandler := handler.NewUserHandler(userService)
 	learningHandler := handler.NewLearningHandler(learningService)
 	examHandler := handler.NewExamHandler(examService)
-
-	jwt := jwt.New(userRepo)
+	privacyHandler := handler.NewPrivacyHandler(privacyService)
+	contentAdminHandler := handler.NewContentAdminHandler(contentAdminService)
+	reviewHandler := handler.NewReviewHandler(reviewService)
+	pointHandler := handler.NewPointHandler(pointService)
 
 	return &registry{
 		middlewares: middlewares{
 			Jwt: jwt,
 		},
 		handlers: handlers{
-			UserHandler:     userHandler,
-			LearningHandler: learningHandler,
-			ExamHandler:     examHandler,
+			UserHandler:         userHandler,
+			LearningHandler:     learningHandler,
+			ExamHandler:         examHandler,
+			PrivacyHandler:      privacyHandler,
+			ContentAdminHandler: contentAdminHandler,
+			ReviewHandler:       reviewHandler,
+			PointHandler:        pointHandler,
 		},
-	}
+		privacyService: privacyService,
+	}, nil
 }
 
 func (r registry) GetMiddlewares() *middlewares {
@@ -68,3 +118,27 @@ func (r registry) GetMiddlewares() *middlewares {
 func (r registry) GetHandlers() *handlers {
 	return &r.handlers
 }
+
+// StartJobs runs the background jobs until the context is done.
+func (r registry) StartJobs(ctx context.Context) {
+	interval := utils.ParseDuration(os.Getenv("ACCOUNT_PURGE_INTERVAL_MINUTE"))
+	if interval <= 0 {
+		interval = defaultAccountPurgeIntervalMinute
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval * time.Minute)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := r.privacyService.PurgeDueAccounts(); err != nil {
+					logs.GetInstance().Error(err)
+				}
+			}
+		}
+	}()
+}
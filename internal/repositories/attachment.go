@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"database-camp/internal/infrastructure/cache"
+	"database-camp/internal/infrastructure/storage"
+	"time"
+)
+
+// AttachmentRepository keeps the attachment files of contents in the cloud
+// storage bucket.
+type AttachmentRepository interface {
+	GetAttachmentLink(objectName string, fileName string) (string, error)
+	UploadAttachment(objectName string, contentType string, data []byte) error
+	DeleteAttachment(objectNames ...string) error
+}
+
+type attachmentRepository struct {
+	cache cache.Cache
+}
+
+func NewAttachmentRepository(cache cache.Cache) *attachmentRepository {
+	return &attachmentRepository{cache: cache}
+}
+
+// GetAttachmentLink caches the signed link for less than its 15 minutes
+// lifetime, so a cached link is never handed out already expired.
+func (r attachmentRepository) GetAttachmentLink(objectName string, fileName string) (string, error) {
+	key := "attachmentRepository::GetAttachmentLink::" + objectName
+
+	if cacheData, err := r.cache.Get(key); err == nil {
+		return cacheData, nil
+	}
+
+	link, err := storage.GetCloudStorageServiceInstance().GetDownloadLink(objectName, fileName)
+	if err != nil {
+		return "", err
+	}
+
+	err = r.cache.Set(key, link, time.Minute*10)
+	if err != nil {
+		return "", err
+	}
+
+	return link, nil
+}
+
+func (r attachmentRepository) UploadAttachment(objectName string, contentType string, data []byte) error {
+	return storage.GetCloudStorageServiceInstance().Upload(objectName, contentType, data)
+}
+
+func (r attachmentRepository) DeleteAttachment(objectNames ...string) error {
+	err := storage.GetCloudStorageServiceInstance().Delete(objectNames...)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(objectNames))
+	for _, objectName := range objectNames {
+		keys = append(keys, "attachmentRepository::GetAttachmentLink::"+objectName)
+	}
+
+	return r.cache.Delete(keys...)
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"database-camp/internal/infrastructure/cache"
+	"database-camp/internal/infrastructure/storage"
+	"time"
+)
+
+// AvatarRepository keeps the avatar pictures in the cloud storage bucket.
+type AvatarRepository interface {
+	GetAvatarLink(objectName string) (string, error)
+	UploadAvatar(objectName string, data []byte) error
+	DeleteAvatar(objectNames ...string) error
+}
+
+type avatarRepository struct {
+	cache cache.Cache
+}
+
+func NewAvatarRepository(cache cache.Cache) *avatarRepository {
+	return &avatarRepository{cache: cache}
+}
+
+// GetAvatarLink caches the signed link for less than its 15 minutes lifetime,
+// so a cached link is never handed out already expired.
+func (r avatarRepository) GetAvatarLink(objectName string) (string, error) {
+	key := "avatarRepository::GetAvatarLink::" + objectName
+
+	if cacheData, err := r.cache.Get(key); err == nil {
+		return cacheData, nil
+	}
+
+	link, err := storage.GetCloudStorageServiceInstance().GetFileLink(objectName)
+	if err != nil {
+		return "", err
+	}
+
+	err = r.cache.Set(key, link, time.Minute*10)
+	if err != nil {
+		return "", err
+	}
+
+	return link, nil
+}
+
+func (r avatarRepository) UploadAvatar(objectName string, data []byte) error {
+	return storage.GetCloudStorageServiceInstance().Upload(objectName, "image/jpeg", data)
+}
+
+func (r avatarRepository) DeleteAvatar(objectNames ...string) error {
+	err := storage.GetCloudStorageServiceInstance().Delete(objectNames...)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(objectNames))
+	for _, objectName := range objectNames {
+		keys = append(keys, "avatarRepository::GetAvatarLink::"+objectName)
+	}
+
+	return r.cache.Delete(keys...)
+}
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"database-camp/internal/errs"
+	"database-camp/internal/infrastructure/cache"
+	"database-camp/internal/infrastructure/database"
+	"database-camp/internal/models/entities/activity"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// ChoiceLoader loads the choices of one activity. Every activity.ActivityType
+// registers its loader with RegisterChoiceLoader, the loaders of the built-in
+// types are the learningRepository methods below.
+type ChoiceLoader func(db database.MysqlDB, cache cache.Cache, activityID int) (activity.Choices, error)
+
+var (
+	choiceLoaders = map[int]ChoiceLoader{
+		activity.TYPE_MATCHING: func(db database.MysqlDB, cache cache.Cache, activityID int) (activity.Choices, error) {
+			return NewLearningRepository(db, cache).getMatchingChoice(activityID)
+		},
+		activity.TYPE_MULTIPLE: func(db database.MysqlDB, cache cache.Cache, activityID int) (activity.Choices, error) {
+			return NewLearningRepository(db, cache).getMultipleChoice(activityID)
+		},
+		activity.TYPE_COMPLETION: func(db database.MysqlDB, cache cache.Cache, activityID int) (activity.Choices, error) {
+			return NewLearningRepository(db, cache).getCompletionChoice(activityID)
+		},
+		activity.TYPE_VOCAB_GROUP: func(db database.MysqlDB, cache cache.Cache, activityID int) (activity.Choices, error) {
+			return NewLearningRepository(db, cache).getVocabGroupChoice(activityID)
+		},
+		activity.TYPE_DEPENDENCY: func(db database.MysqlDB, cache cache.Cache, activityID int) (activity.Choices, error) {
+			return NewLearningRepository(db, cache).getDependencyChoice(activityID)
+		},
+		activity.TYPE_ER: func(db database.MysqlDB, cache cache.Cache, activityID int) (activity.Choices, error) {
+			return NewLearningRepository(db, cache).GetERChoice(activityID)
+		},
+		// A peer review shows the ER answer of a random learner
+		activity.TYPE_PEER_REVIEW: func(db database.MysqlDB, cache cache.Cache, activityID int) (activity.Choices, error) {
+			return NewLearningRepository(db, cache).GetPeerChoice(nil)
+		},
+	}
+	choiceLoadersMutex sync.RWMutex
+)
+
+// ChoiceWriter stores the choices of the activity types that are not built in,
+// the content authoring repositories write through it in their transaction.
+type ChoiceWriter struct {
+	// Insert stores the choices ActivityType.ParseChoices returned
+	Insert func(tx *gorm.DB, activityID int, choices interface{}) error
+
+	// Delete removes the choices of the activity, it is called for every
+	// activity whose choices are replaced whatever its type was
+	Delete func(tx *gorm.DB, activityID int) error
+}
+
+var (
+	choiceWriters      = map[int]ChoiceWriter{}
+	choiceWritersMutex sync.RWMutex
+)
+
+/**
+ * Register the choice loader of an activity type
+ *
+ * @param activityTypeID 	activity_type_id of the type
+ * @param loader 			loader of the choices
+ */
+func RegisterChoiceLoader(activityTypeID int, loader ChoiceLoader) {
+	choiceLoadersMutex.Lock()
+	defer choiceLoadersMutex.Unlock()
+
+	choiceLoaders[activityTypeID] = loader
+}
+
+func getChoiceLoader(activityTypeID int) (ChoiceLoader, error) {
+	choiceLoadersMutex.RLock()
+	defer choiceLoadersMutex.RUnlock()
+
+	loader, ok := choiceLoaders[activityTypeID]
+	if !ok {
+		return nil, errs.ErrActivityTypeInvalid
+	}
+
+	return loader, nil
+}
+
+/**
+ * Register the choice writer of an activity type
+ *
+ * @param activityTypeID 	activity_type_id of the type
+ * @param writer 			writer of the choices
+ */
+func RegisterChoiceWriter(activityTypeID int, writer ChoiceWriter) {
+	choiceWritersMutex.Lock()
+	defer choiceWritersMutex.Unlock()
+
+	choiceWriters[activityTypeID] = writer
+}
+
+func getChoiceWriter(activityTypeID int) (ChoiceWriter, bool) {
+	choiceWritersMutex.RLock()
+	defer choiceWritersMutex.RUnlock()
+
+	writer, ok := choiceWriters[activityTypeID]
+	return writer, ok
+}
+
+func getChoiceWriters() []ChoiceWriter {
+	choiceWritersMutex.RLock()
+	defer choiceWritersMutex.RUnlock()
+
+	writers := make([]ChoiceWriter, 0, len(choiceWriters))
+	for _, writer := range choiceWriters {
+		writers = append(writers, writer)
+	}
+	return writers
+}
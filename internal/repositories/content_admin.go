@@ -0,0 +1,936 @@
+package repositories
+
+import (
+	"database-camp/internal/infrastructure/cache"
+	"database-camp/internal/infrastructure/database"
+	"database-camp/internal/models/entities/activity"
+	"database-camp/internal/models/entities/content"
+	"database-camp/internal/utils"
+	"fmt"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+)
+
+// ContentAdminRepository writes the curriculum, every write clears the cache
+// entries of learningRepository and examRepository that hold the changed rows.
+type ContentAdminRepository interface {
+	GetContentGroups() ([]content.ContentGroup, error)
+	GetContentGroup(id int) (*content.ContentGroup, error)
+	InsertContentGroup(group *content.ContentGroup) error
+	UpdateContentGroup(group content.ContentGroup) error
+	DeleteContentGroup(id int) error
+	HasBadge(id int) (bool, error)
+	HasExam(id int) (bool, error)
+	GetContents(groupID int) ([]content.Content, error)
+	GetContent(id int) (*content.Content, error)
+	InsertContent(_content *content.Content) error
+	UpdateContent(_content content.Content) error
+	DeleteContent(id int) error
+	CountContents(groupID int) (int64, error)
+	GetActivities(contentID int) ([]activity.Activity, error)
+	GetActivity(id int) (*activity.Activity, error)
+	InsertActivity(_activity *activity.Activity, choices interface{}) error
+	UpdateActivity(_activity activity.Activity, oldContentID *int, choices interface{}) error
+	DeleteActivity(_activity activity.Activity) error
+	CountActivities(contentID int) (int64, error)
+	IsActivityInUse(id int) (bool, error)
+	GetHint(id int) (*activity.Hint, error)
+	InsertHint(hint *activity.Hint) error
+	UpdateHint(hint activity.Hint, oldActivityID int) error
+	DeleteHint(hint activity.Hint) error
+	GetPrerequisite(id int) (*content.Prerequisite, error)
+	InsertPrerequisite(prerequisite *content.Prerequisite) error
+	DeletePrerequisite(id int) error
+	HasTarget(targetType string, id int) (bool, error)
+	GetAttachmentStatistics(contentID int) ([]content.AttachmentStatistic, error)
+	InsertAttachment(attachment *content.Attachment) error
+	DeleteAttachment(id int) error
+}
+
+type contentAdminRepository struct {
+	db    database.MysqlDB
+	cache cache.Cache
+}
+
+func NewContentAdminRepository(db database.MysqlDB, cache cache.Cache) *contentAdminRepository {
+	return &contentAdminRepository{db: db, cache: cache}
+}
+
+type matchingChoiceRow struct {
+	ActivityID int `gorm:"column:activity_id"`
+	activity.MatchingChoice
+}
+
+type multipleChoiceRow struct {
+	ActivityID int `gorm:"column:activity_id"`
+	activity.MultipleChoice
+}
+
+type completionChoiceRow struct {
+	ActivityID int `gorm:"column:activity_id"`
+	activity.CompletionChoice
+}
+
+type vocabGroupRow struct {
+	ID   int    `gorm:"primaryKey;column:vocab_group_id"`
+	Name string `gorm:"column:name"`
+}
+
+type vocabGroupChoiceRow struct {
+	ActivityID   int    `gorm:"column:activity_id"`
+	VocabGroupID int    `gorm:"column:vocab_group_id"`
+	Vocab        string `gorm:"column:vocab"`
+}
+
+type dependencyChoiceRow struct {
+	ID         int `gorm:"primaryKey;column:dependency_choice_id"`
+	ActivityID int `gorm:"column:activity_id"`
+}
+
+type dependencyRow struct {
+	ID                 int    `gorm:"primaryKey;column:dependency_id"`
+	DependencyChoiceID int    `gorm:"column:dependency_choice_id"`
+	Dependent          string `gorm:"column:dependent"`
+	Fixed              bool   `gorm:"column:fixed"`
+}
+
+type determinantRow struct {
+	DependencyID int    `gorm:"column:dependency_id"`
+	Value        string `gorm:"column:value"`
+	Fixed        bool   `gorm:"column:fixed"`
+}
+
+type erChoiceRow struct {
+	ID         int    `gorm:"primaryKey;column:er_choice_id"`
+	ActivityID int    `gorm:"column:activity_id"`
+	Type       string `gorm:"column:type"`
+}
+
+type erChoiceTableRow struct {
+	ERChoiceID int    `gorm:"column:er_choice_id"`
+	TableID    string `gorm:"column:table_id"`
+}
+
+func (r contentAdminRepository) GetContentGroups() ([]content.ContentGroup, error) {
+	groups := make([]content.ContentGroup, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.ContentGroup).
+		Order(IDName.ContentGroup).
+		Find(&groups).
+		Error
+
+	return groups, err
+}
+
+func (r contentAdminRepository) GetContentGroup(id int) (*content.ContentGroup, error) {
+	group := content.ContentGroup{}
+
+	err := r.db.GetDB().
+		Table(TableName.ContentGroup).
+		Where(IDName.ContentGroup+" = ?", id).
+		Find(&group).
+		Error
+
+	return &group, err
+}
+
+func (r contentAdminRepository) InsertContentGroup(group *content.ContentGroup) error {
+	err := r.db.GetDB().Table(TableName.ContentGroup).Create(group).Error
+	if err != nil {
+		return err
+	}
+
+	return r.cache.Delete(r.contentGroupKeys()...)
+}
+
+func (r contentAdminRepository) UpdateContentGroup(group content.ContentGroup) error {
+	err := r.db.GetDB().
+		Table(TableName.ContentGroup).
+		Where(IDName.ContentGroup+" = ?", group.ID).
+		Updates(map[string]interface{}{
+			"name":          group.Name,
+			IDName.Badge:    group.BadgeID,
+			IDName.MiniExam: group.MiniExamID,
+		}).
+		Error
+	if err != nil {
+		return err
+	}
+
+	return r.cache.Delete(r.contentGroupKeys()...)
+}
+
+func (r contentAdminRepository) DeleteContentGroup(id int) error {
+	tx := r.db.GetDB().Begin()
+
+	err := tx.Table(TableName.ContentGroup).
+		Where(IDName.ContentGroup+" = ?", id).
+		Delete(map[string]interface{}{}).
+		Error
+	if err == nil {
+		err = r.deletePrerequisites(tx, content.PREREQUISITE_TARGET_CONTENT_GROUP, content.UNLOCK_RULE_PASS_MINI_EXAM, id)
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return r.cache.Delete(append(r.contentGroupKeys(), r.prerequisiteKey())...)
+}
+
+func (r contentAdminRepository) HasBadge(id int) (bool, error) {
+	return r.exists(TableName.Badge, IDName.Badge, id)
+}
+
+func (r contentAdminRepository) HasExam(id int) (bool, error) {
+	return r.exists(TableName.Exam, IDName.Exam, id)
+}
+
+func (r contentAdminRepository) GetContents(groupID int) ([]content.Content, error) {
+	contents := make([]content.Content, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.Content).
+		Where(IDName.ContentGroup+" = ?", groupID).
+		Order(IDName.Content).
+		Find(&contents).
+		Error
+
+	return contents, err
+}
+
+func (r contentAdminRepository) GetContent(id int) (*content.Content, error) {
+	_content := content.Content{}
+
+	err := r.db.GetDB().
+		Table(TableName.Content).
+		Where(IDName.Content+" = ?", id).
+		Find(&_content).
+		Error
+
+	return &_content, err
+}
+
+func (r contentAdminRepository) InsertContent(_content *content.Content) error {
+	err := r.db.GetDB().Table(TableName.Content).Create(_content).Error
+	if err != nil {
+		return err
+	}
+
+	return r.cache.Delete(r.contentKeys(_content.ID)...)
+}
+
+func (r contentAdminRepository) UpdateContent(_content content.Content) error {
+	err := r.db.GetDB().
+		Table(TableName.Content).
+		Where(IDName.Content+" = ?", _content.ID).
+		Updates(map[string]interface{}{
+			IDName.ContentGroup: _content.GroupID,
+			"name":              _content.Name,
+			"video_path":        _content.VideoPath,
+			"slide_path":        _content.SlidePath,
+		}).
+		Error
+	if err != nil {
+		return err
+	}
+
+	return r.cache.Delete(r.contentKeys(_content.ID)...)
+}
+
+func (r contentAdminRepository) DeleteContent(id int) error {
+	tx := r.db.GetDB().Begin()
+
+	err := tx.Table(TableName.Content).
+		Where(IDName.Content+" = ?", id).
+		Delete(map[string]interface{}{}).
+		Error
+	if err == nil {
+		err = r.deletePrerequisites(tx, content.PREREQUISITE_TARGET_CONTENT, content.UNLOCK_RULE_COMPLETE_ACTIVITIES, id)
+	}
+	if err == nil {
+		err = r.deleteAttachments(tx, IDName.Content, id)
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return r.cache.Delete(append(r.contentKeys(id), r.prerequisiteKey())...)
+}
+
+// deletePrerequisites removes the prerequisites of a deleted target and the
+// ones that require it
+func (r contentAdminRepository) deletePrerequisites(tx *gorm.DB, targetType string, rule string, id int) error {
+	return tx.Table(TableName.Prerequisite).
+		Where("(target_type = ? AND target_id = ?) OR (rule = ? AND required_id = ?)", targetType, id, rule, id).
+		Delete(map[string]interface{}{}).
+		Error
+}
+
+// deleteAttachments removes the attachments whose column is the id and their
+// downloads
+func (r contentAdminRepository) deleteAttachments(tx *gorm.DB, column string, id int) error {
+	attachmentIDs := tx.Table(TableName.Attachment).Select("attachment_id").Where(column+" = ?", id)
+
+	err := tx.Table(TableName.AttachmentDownload).
+		Where("attachment_id IN (?)", attachmentIDs).
+		Delete(map[string]interface{}{}).
+		Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Table(TableName.Attachment).
+		Where(column+" = ?", id).
+		Delete(map[string]interface{}{}).
+		Error
+}
+
+func (r contentAdminRepository) CountContents(groupID int) (int64, error) {
+	var count int64
+
+	err := r.db.GetDB().
+		Table(TableName.Content).
+		Where(IDName.ContentGroup+" = ?", groupID).
+		Count(&count).
+		Error
+
+	return count, err
+}
+
+func (r contentAdminRepository) GetActivities(contentID int) ([]activity.Activity, error) {
+	activities := make([]activity.Activity, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.Activity).
+		Where(IDName.Content+" = ?", contentID).
+		Order("activity_order").
+		Find(&activities).
+		Error
+
+	return activities, err
+}
+
+func (r contentAdminRepository) GetActivity(id int) (*activity.Activity, error) {
+	_activity := activity.Activity{}
+
+	err := r.db.GetDB().
+		Table(TableName.Activity).
+		Where(IDName.Activity+" = ?", id).
+		Find(&_activity).
+		Error
+
+	return &_activity, err
+}
+
+func (r contentAdminRepository) InsertActivity(_activity *activity.Activity, choices interface{}) error {
+	tx := r.db.GetDB().Begin()
+
+	err := tx.Table(TableName.Activity).Create(_activity).Error
+	if err == nil {
+		err = r.insertChoices(tx, _activity.ID, _activity.TypeID, choices)
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return err
+	}
+
+	return r.clearActivityCache(_activity.ID, _activity.ContentID)
+}
+
+// UpdateActivity replaces the activity and all of its choices, the type of
+// the activity may change with it.
+func (r contentAdminRepository) UpdateActivity(_activity activity.Activity, oldContentID *int, choices interface{}) error {
+	tx := r.db.GetDB().Begin()
+
+	err := tx.Table(TableName.Activity).
+		Where(IDName.Activity+" = ?", _activity.ID).
+		Updates(map[string]interface{}{
+			"activity_type_id": _activity.TypeID,
+			IDName.Content:     _activity.ContentID,
+			"activity_order":   _activity.Order,
+			"story":            _activity.Story,
+			"point":            _activity.Point,
+			"question":         _activity.Question,
+		}).
+		Error
+	if err == nil {
+		err = r.deleteChoices(tx, _activity.ID)
+	}
+	if err == nil {
+		err = r.insertChoices(tx, _activity.ID, _activity.TypeID, choices)
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return err
+	}
+
+	return r.clearActivityCache(_activity.ID, _activity.ContentID, oldContentID)
+}
+
+// DeleteActivity removes the activity with its choices and hints. Activities
+// that learners have answered are kept, see IsActivityInUse.
+func (r contentAdminRepository) DeleteActivity(_activity activity.Activity) error {
+	tx := r.db.GetDB().Begin()
+
+	err := r.deleteChoices(tx, _activity.ID)
+	if err == nil {
+		err = tx.Table(TableName.Hint).
+			Where(IDName.Activity+" = ?", _activity.ID).
+			Delete(map[string]interface{}{}).
+			Error
+	}
+	if err == nil {
+		err = tx.Table(TableName.Activity).
+			Where(IDName.Activity+" = ?", _activity.ID).
+			Delete(map[string]interface{}{}).
+			Error
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return err
+	}
+
+	return r.clearActivityCache(_activity.ID, _activity.ContentID)
+}
+
+func (r contentAdminRepository) CountActivities(contentID int) (int64, error) {
+	var count int64
+
+	err := r.db.GetDB().
+		Table(TableName.Activity).
+		Where(IDName.Content+" = ?", contentID).
+		Count(&count).
+		Error
+
+	return count, err
+}
+
+// IsActivityInUse reports whether the activity is part of an exam or has been
+// answered, or its hints used, by a learner.
+func (r contentAdminRepository) IsActivityInUse(id int) (bool, error) {
+	hintIDs := r.db.GetDB().
+		Table(TableName.Hint).
+		Select(IDName.Hint).
+		Where(IDName.Activity+" = ?", id)
+
+	queries := []*gorm.DB{
+		r.db.GetDB().Table(TableName.ContentExam).Where(IDName.Activity+" = ?", id),
+		r.db.GetDB().Table(TableName.LearningProgression).Where(IDName.Activity+" = ?", id),
+		r.db.GetDB().Table(TableName.ExamResultActivity).Where(IDName.Activity+" = ?", id),
+		r.db.GetDB().Table(TableName.UserHint).Where(IDName.Hint+" IN (?)", hintIDs),
+	}
+
+	for _, query := range queries {
+		var count int64
+
+		err := query.Count(&count).Error
+		if err != nil {
+			return false, err
+		}
+
+		if count > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r contentAdminRepository) GetHint(id int) (*activity.Hint, error) {
+	hint := activity.Hint{}
+
+	err := r.db.GetDB().
+		Table(TableName.Hint).
+		Where(IDName.Hint+" = ?", id).
+		Find(&hint).
+		Error
+
+	return &hint, err
+}
+
+func (r contentAdminRepository) InsertHint(hint *activity.Hint) error {
+	err := r.db.GetDB().Table(TableName.Hint).Create(hint).Error
+	if err != nil {
+		return err
+	}
+
+	return r.cache.Delete(r.hintKey(hint.ActivityID))
+}
+
+func (r contentAdminRepository) UpdateHint(hint activity.Hint, oldActivityID int) error {
+	err := r.db.GetDB().
+		Table(TableName.Hint).
+		Where(IDName.Hint+" = ?", hint.ID).
+		Updates(map[string]interface{}{
+			IDName.Activity: hint.ActivityID,
+			"content":       hint.Content,
+			"point_reduce":  hint.PointReduce,
+			"level":         hint.Level,
+		}).
+		Error
+	if err != nil {
+		return err
+	}
+
+	return r.cache.Delete(r.hintKey(hint.ActivityID), r.hintKey(oldActivityID))
+}
+
+func (r contentAdminRepository) DeleteHint(hint activity.Hint) error {
+	err := r.db.GetDB().
+		Table(TableName.Hint).
+		Where(IDName.Hint+" = ?", hint.ID).
+		Delete(map[string]interface{}{}).
+		Error
+	if err != nil {
+		return err
+	}
+
+	return r.cache.Delete(r.hintKey(hint.ActivityID))
+}
+
+func (r contentAdminRepository) insertChoices(tx *gorm.DB, activityID int, activityTypeID int, choices interface{}) error {
+	switch choices := choices.(type) {
+	case activity.MatchingChoices:
+		rows := make([]matchingChoiceRow, 0, len(choices))
+		for _, choice := range choices {
+			choice.ID = 0
+			rows = append(rows, matchingChoiceRow{ActivityID: activityID, MatchingChoice: choice})
+		}
+		return tx.Table(TableName.MatchingChoice).Create(&rows).Error
+
+	case activity.MultipleChoices:
+		rows := make([]multipleChoiceRow, 0, len(choices))
+		for _, choice := range choices {
+			choice.ID = 0
+			rows = append(rows, multipleChoiceRow{ActivityID: activityID, MultipleChoice: choice})
+		}
+		return tx.Table(TableName.MultipleChoice).Create(&rows).Error
+
+	case activity.CompletionChoices:
+		rows := make([]completionChoiceRow, 0, len(choices))
+		for _, choice := range choices {
+			choice.ID = 0
+			rows = append(rows, completionChoiceRow{ActivityID: activityID, CompletionChoice: choice})
+		}
+		return tx.Table(TableName.CompletionChoice).Create(&rows).Error
+
+	case activity.VocabGroupChoice:
+		return r.insertVocabGroupChoice(tx, activityID, choices)
+
+	case activity.DependencyChoiceSpec:
+		return InsertDependencyChoice(tx, activityID, choices)
+
+	case activity.ERChoiceSpec:
+		return r.insertERChoice(tx, activityID, choices)
+	}
+
+	if writer, ok := getChoiceWriter(activityTypeID); ok {
+		return writer.Insert(tx, activityID, choices)
+	}
+
+	return nil
+}
+
+func (r contentAdminRepository) insertVocabGroupChoice(tx *gorm.DB, activityID int, choice activity.VocabGroupChoice) error {
+	rows := make([]vocabGroupChoiceRow, 0)
+
+	for _, group := range choice.Groups {
+		groupRow := vocabGroupRow{Name: group.GroupName}
+
+		err := tx.Table(TableName.VocabGroup).Create(&groupRow).Error
+		if err != nil {
+			return err
+		}
+
+		for _, vocab := range group.Vocabs {
+			rows = append(rows, vocabGroupChoiceRow{
+				ActivityID:   activityID,
+				VocabGroupID: groupRow.ID,
+				Vocab:        vocab,
+			})
+		}
+	}
+
+	return tx.Table(TableName.VocabGroupChoice).Create(&rows).Error
+}
+
+/**
+ * Store functional dependencies of an activity, activity types built on the
+ * dependency tables write theirs through it. The dependencies are removed
+ * with the other choices of the activity.
+ *
+ * @param tx 			transaction to write in
+ * @param activityID 	id of the activity
+ * @param choice 		dependencies to store
+ *
+ * @return the error of the database
+ */
+func InsertDependencyChoice(tx *gorm.DB, activityID int, choice activity.DependencyChoiceSpec) error {
+	choiceRow := dependencyChoiceRow{ActivityID: activityID}
+
+	err := tx.Table(TableName.DependencyChoice).Create(&choiceRow).Error
+	if err != nil {
+		return err
+	}
+
+	for _, dependency := range choice.Dependencies {
+		dependencyRow := dependencyRow{
+			DependencyChoiceID: choiceRow.ID,
+			Dependent:          dependency.Dependent,
+			Fixed:              dependency.Fixed,
+		}
+
+		err = tx.Table(TableName.Dependency).Create(&dependencyRow).Error
+		if err != nil {
+			return err
+		}
+
+		determinantRows := make([]determinantRow, 0, len(dependency.Determinants))
+		for _, determinant := range dependency.Determinants {
+			determinantRows = append(determinantRows, determinantRow{
+				DependencyID: dependencyRow.ID,
+				Value:        determinant.Value,
+				Fixed:        determinant.Fixed,
+			})
+		}
+
+		err = tx.Table(TableName.Determinant).Create(&determinantRows).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertERChoice stores the tables under new ids, table ids are shared with
+// the ER answers of learners so the ids of the spec are only used to link
+// the relationships.
+func (r contentAdminRepository) insertERChoice(tx *gorm.DB, activityID int, choice activity.ERChoiceSpec) error {
+	choiceRow := erChoiceRow{ActivityID: activityID, Type: choice.Type}
+
+	err := tx.Table(TableName.ERChoice).Create(&choiceRow).Error
+	if err != nil {
+		return err
+	}
+
+	tableIDs := map[string]string{}
+	tables := make([]activity.Table, 0, len(choice.Tables))
+	choiceTables := make([]erChoiceTableRow, 0, len(choice.Tables))
+	attributes := make([]activity.Attribute, 0)
+
+	for _, tableSpec := range choice.Tables {
+		tableID := uuid.NewV4().String()
+		tableIDs[tableSpec.ID] = tableID
+
+		tables = append(tables, activity.Table{ID: tableID, Title: tableSpec.Title, Fixed: tableSpec.Fixed})
+		choiceTables = append(choiceTables, erChoiceTableRow{ERChoiceID: choiceRow.ID, TableID: tableID})
+
+		for _, attributeSpec := range tableSpec.Attributes {
+			attributes = append(attributes, activity.Attribute{
+				TableID: tableID,
+				Key:     attributeSpec.Key,
+				Value:   attributeSpec.Value,
+				Fixed:   attributeSpec.Fixed,
+			})
+		}
+	}
+
+	relationships := make([]activity.Relationship, 0, len(choice.Relationships))
+	for _, relationshipSpec := range choice.Relationships {
+		relationships = append(relationships, activity.Relationship{
+			RelationshipType: relationshipSpec.RelationshipType,
+			Table1ID:         tableIDs[relationshipSpec.Table1ID],
+			Table2ID:         tableIDs[relationshipSpec.Table2ID],
+			Fixed:            relationshipSpec.Fixed,
+		})
+	}
+
+	err = tx.Table(TableName.Tables).Create(&tables).Error
+	if err == nil {
+		err = tx.Table(TableName.ERChoiceTables).Create(&choiceTables).Error
+	}
+	if err == nil && len(attributes) > 0 {
+		err = tx.Table(TableName.Attributes).Create(&attributes).Error
+	}
+	if err == nil && len(relationships) > 0 {
+		err = tx.Table(TableName.Relationship).Create(&relationships).Error
+	}
+
+	return err
+}
+
+// deleteChoices removes the choices of every activity type, so a change of
+// type leaves no rows of the old type behind.
+func (r contentAdminRepository) deleteChoices(tx *gorm.DB, activityID int) error {
+	vocabGroupIDs := make([]int, 0)
+	err := tx.Table(TableName.VocabGroupChoice).
+		Where(IDName.Activity+" = ?", activityID).
+		Distinct().
+		Pluck(IDName.VocabGroup, &vocabGroupIDs).
+		Error
+	if err != nil {
+		return err
+	}
+
+	tableIDs := make([]string, 0)
+	err = tx.Table(TableName.ERChoiceTables).
+		Where(IDName.ERChoice+" IN (?)", r.db.GetDB().Table(TableName.ERChoice).Select(IDName.ERChoice).Where(IDName.Activity+" = ?", activityID)).
+		Pluck(IDName.Table, &tableIDs).
+		Error
+	if err != nil {
+		return err
+	}
+
+	dependencyChoiceIDs := r.db.GetDB().
+		Table(TableName.DependencyChoice).
+		Select(IDName.DependencyChoice).
+		Where(IDName.Activity+" = ?", activityID)
+
+	dependencyIDs := r.db.GetDB().
+		Table(TableName.Dependency).
+		Select(IDName.Dependency).
+		Where(IDName.DependencyChoice+" IN (?)", dependencyChoiceIDs)
+
+	deletes := []*gorm.DB{
+		tx.Table(TableName.MatchingChoice).Where(IDName.Activity+" = ?", activityID),
+		tx.Table(TableName.MultipleChoice).Where(IDName.Activity+" = ?", activityID),
+		tx.Table(TableName.CompletionChoice).Where(IDName.Activity+" = ?", activityID),
+		tx.Table(TableName.VocabGroupChoice).Where(IDName.Activity+" = ?", activityID),
+		tx.Table(TableName.Determinant).Where(IDName.Dependency+" IN (?)", dependencyIDs),
+		tx.Table(TableName.Dependency).Where(IDName.DependencyChoice+" IN (?)", dependencyChoiceIDs),
+		tx.Table(TableName.DependencyChoice).Where(IDName.Activity+" = ?", activityID),
+	}
+
+	// Groups are created for one activity, only unused ones are removed
+	if len(vocabGroupIDs) > 0 {
+		usedGroupIDs := r.db.GetDB().
+			Table(TableName.VocabGroupChoice).
+			Select(IDName.VocabGroup)
+
+		deletes = append(deletes, tx.Table(TableName.VocabGroup).
+			Where(IDName.VocabGroup+" IN ? AND "+IDName.VocabGroup+" NOT IN (?)", vocabGroupIDs, usedGroupIDs))
+	}
+
+	if len(tableIDs) > 0 {
+		deletes = append(deletes,
+			tx.Table(TableName.Relationship).Where("table1_id IN ? OR table2_id IN ?", tableIDs, tableIDs),
+			tx.Table(TableName.Attributes).Where(IDName.Table+" IN ?", tableIDs),
+			tx.Table(TableName.ERChoiceTables).Where(IDName.Table+" IN ?", tableIDs),
+			tx.Table(TableName.Tables).Where(IDName.Table+" IN ?", tableIDs),
+		)
+	}
+
+	deletes = append(deletes, tx.Table(TableName.ERChoice).Where(IDName.Activity+" = ?", activityID))
+
+	for _, query := range deletes {
+		err = query.Delete(map[string]interface{}{}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, writer := range getChoiceWriters() {
+		err = writer.Delete(tx, activityID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r contentAdminRepository) GetPrerequisite(id int) (*content.Prerequisite, error) {
+	prerequisite := content.Prerequisite{}
+
+	err := r.db.GetDB().
+		Table(TableName.Prerequisite).
+		Where("prerequisite_id = ?", id).
+		Find(&prerequisite).
+		Error
+
+	return &prerequisite, err
+}
+
+func (r contentAdminRepository) InsertPrerequisite(prerequisite *content.Prerequisite) error {
+	err := r.db.GetDB().Table(TableName.Prerequisite).Create(prerequisite).Error
+	if err != nil {
+		return err
+	}
+
+	return r.cache.Delete(r.prerequisiteKey())
+}
+
+func (r contentAdminRepository) DeletePrerequisite(id int) error {
+	err := r.db.GetDB().
+		Table(TableName.Prerequisite).
+		Where("prerequisite_id = ?", id).
+		Delete(map[string]interface{}{}).
+		Error
+	if err != nil {
+		return err
+	}
+
+	return r.cache.Delete(r.prerequisiteKey())
+}
+
+// HasTarget tells whether the content or content group a prerequisite names
+// exists
+func (r contentAdminRepository) HasTarget(targetType string, id int) (bool, error) {
+	if targetType == content.PREREQUISITE_TARGET_CONTENT_GROUP {
+		return r.exists(TableName.ContentGroup, IDName.ContentGroup, id)
+	}
+	return r.exists(TableName.Content, IDName.Content, id)
+}
+
+func (r contentAdminRepository) GetAttachmentStatistics(contentID int) ([]content.AttachmentStatistic, error) {
+	statistics := make([]content.AttachmentStatistic, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.Attachment).
+		Select(
+			TableName.Attachment+".*",
+			fmt.Sprintf("COUNT(%s.attachment_download_id) AS downloads", TableName.AttachmentDownload),
+			fmt.Sprintf("COUNT(DISTINCT %s.%s) AS downloaders", TableName.AttachmentDownload, IDName.User),
+		).
+		Joins(fmt.Sprintf("LEFT JOIN %s ON %s.attachment_id = %s.attachment_id",
+			TableName.AttachmentDownload,
+			TableName.AttachmentDownload,
+			TableName.Attachment,
+		)).
+		Where(TableName.Attachment+"."+IDName.Content+" = ?", contentID).
+		Group(TableName.Attachment + ".attachment_id").
+		Order(TableName.Attachment + ".attachment_id").
+		Find(&statistics).
+		Error
+
+	return statistics, err
+}
+
+func (r contentAdminRepository) InsertAttachment(attachment *content.Attachment) error {
+	attachment.CreatedTimestamp = time.Now().Local()
+
+	return r.db.GetDB().Table(TableName.Attachment).Create(attachment).Error
+}
+
+func (r contentAdminRepository) DeleteAttachment(id int) error {
+	tx := r.db.GetDB().Begin()
+
+	err := r.deleteAttachments(tx, "attachment_id", id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (r contentAdminRepository) exists(table string, idName string, id int) (bool, error) {
+	var count int64
+
+	err := r.db.GetDB().
+		Table(table).
+		Where(idName+" = ?", id).
+		Count(&count).
+		Error
+
+	return count > 0, err
+}
+
+func (r contentAdminRepository) contentGroupKeys() []string {
+	return []string{
+		"learningRepository::GetOverview",
+		"examRepository::GetExams",
+	}
+}
+
+func (r contentAdminRepository) contentKeys(contentID int) []string {
+	return []string{
+		"learningRepository::GetOverview",
+		"learningRepository::GetContent::" + utils.ParseString(contentID),
+	}
+}
+
+func (r contentAdminRepository) prerequisiteKey() string {
+	return "learningRepository::GetPrerequisites"
+}
+
+func (r contentAdminRepository) hintKey(activityID int) string {
+	return "learningRepository::GetActivityHints::" + utils.ParseString(activityID)
+}
+
+// activityKeys are the cache entries of the activity, its choices and hints.
+func (r contentAdminRepository) activityKeys(activityID int) []string {
+	id := utils.ParseString(activityID)
+
+	return []string{
+		"learningRepository::GetActivity::" + id,
+		"learningRepository::getMatchingChoice::" + id,
+		"learningRepository::getMultipleChoice::" + id,
+		"learningRepository::getCompletionChoice::" + id,
+		"learningRepository::getVocabGroupChoice::" + id,
+		r.hintKey(activityID),
+	}
+}
+
+// clearActivityCache clears the activity, its choices and hints, the content
+// activity lists it was or is part of, and the exams that include it.
+func (r contentAdminRepository) clearActivityCache(activityID int, contentIDs ...*int) error {
+	keys := append([]string{"learningRepository::GetOverview"}, r.activityKeys(activityID)...)
+
+	for _, contentID := range contentIDs {
+		if contentID != nil {
+			keys = append(keys, "learningRepository::GetContentActivity::"+utils.ParseString(*contentID))
+		}
+	}
+
+	examIDs := make([]int, 0)
+	err := r.db.GetDB().
+		Table(TableName.ContentExam).
+		Where(IDName.Activity+" = ?", activityID).
+		Distinct().
+		Pluck(IDName.Exam, &examIDs).
+		Error
+	if err != nil {
+		return err
+	}
+
+	for _, examID := range examIDs {
+		keys = append(keys, "examRepository::GetExamActivities::"+utils.ParseString(examID))
+	}
+
+	return r.cache.Delete(keys...)
+}
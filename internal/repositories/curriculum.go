@@ -0,0 +1,453 @@
+package repositories
+
+import (
+	"database-camp/internal/infrastructure/cache"
+	"database-camp/internal/infrastructure/database"
+	"database-camp/internal/models/entities/activity"
+	"database-camp/internal/models/entities/content"
+	"database-camp/internal/models/entities/curriculum"
+	"database-camp/internal/utils"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CurriculumRepository reads and writes the whole curriculum as a bundle. It
+// reads the database directly, a bundle must not be exported from stale cache
+// entries.
+type CurriculumRepository interface {
+	GetBundle() (*curriculum.Bundle, error)
+	ImportBundle(bundle curriculum.Bundle, diff curriculum.Diff) error
+}
+
+type curriculumRepository struct {
+	db       database.MysqlDB
+	cache    cache.Cache
+	admin    contentAdminRepository
+	learning learningRepository
+}
+
+func NewCurriculumRepository(db database.MysqlDB, cache cache.Cache) *curriculumRepository {
+	return &curriculumRepository{
+		db:       db,
+		cache:    cache,
+		admin:    *NewContentAdminRepository(db, cache),
+		learning: *NewLearningRepository(db, cache),
+	}
+}
+
+type contentExamRow struct {
+	ExamID int `gorm:"column:exam_id"`
+	curriculum.ExamActivity
+}
+
+func (r curriculumRepository) GetBundle() (*curriculum.Bundle, error) {
+	bundle := curriculum.Bundle{
+		Version:       curriculum.BUNDLE_VERSION,
+		Badges:        make([]curriculum.Badge, 0),
+		ContentGroups: make([]curriculum.ContentGroup, 0),
+		Activities:    make([]curriculum.Activity, 0),
+		Exams:         make([]curriculum.Exam, 0),
+		Prerequisites: make([]content.Prerequisite, 0),
+	}
+
+	groups := make([]content.ContentGroup, 0)
+	contents := make([]content.Content, 0)
+	activities := make([]activity.Activity, 0)
+	hints := make([]activity.Hint, 0)
+	examActivities := make([]contentExamRow, 0)
+
+	queries := []struct {
+		table string
+		order string
+		dest  interface{}
+	}{
+		{TableName.Badge, IDName.Badge, &bundle.Badges},
+		{TableName.ContentGroup, IDName.ContentGroup, &groups},
+		{TableName.Content, IDName.Content, &contents},
+		{TableName.Activity, IDName.Activity, &activities},
+		{TableName.Hint, IDName.Hint, &hints},
+		{TableName.Exam, IDName.Exam, &bundle.Exams},
+		{TableName.ContentExam, IDName.Exam, &examActivities},
+		{TableName.Prerequisite, "prerequisite_id", &bundle.Prerequisites},
+	}
+
+	for _, query := range queries {
+		err := r.db.GetDB().Table(query.table).Order(query.order).Find(query.dest).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	choices, err := r.getChoices(activities)
+	if err != nil {
+		return nil, err
+	}
+
+	activityHints := map[int][]activity.Hint{}
+	for _, hint := range hints {
+		activityHints[hint.ActivityID] = append(activityHints[hint.ActivityID], hint)
+	}
+
+	contentActivities := map[int][]curriculum.Activity{}
+	for _, _activity := range activities {
+		bundleActivity := curriculum.Activity{
+			Activity: _activity,
+			Choices:  choices[_activity.ID],
+			Hints:    activityHints[_activity.ID],
+		}
+		if bundleActivity.Hints == nil {
+			bundleActivity.Hints = make([]activity.Hint, 0)
+		}
+
+		if _activity.ContentID == nil {
+			bundle.Activities = append(bundle.Activities, bundleActivity)
+		} else {
+			contentActivities[*_activity.ContentID] = append(contentActivities[*_activity.ContentID], bundleActivity)
+		}
+	}
+
+	groupContents := map[int][]curriculum.Content{}
+	for _, _content := range contents {
+		bundleContent := curriculum.Content{
+			Content:    _content,
+			Activities: contentActivities[_content.ID],
+		}
+		if bundleContent.Activities == nil {
+			bundleContent.Activities = make([]curriculum.Activity, 0)
+		}
+
+		delete(contentActivities, _content.ID)
+		groupContents[_content.GroupID] = append(groupContents[_content.GroupID], bundleContent)
+	}
+
+	// Activities of a missing content are kept, the import reports them
+	for _, orphans := range contentActivities {
+		bundle.Activities = append(bundle.Activities, orphans...)
+	}
+
+	for _, group := range groups {
+		bundleGroup := curriculum.ContentGroup{
+			ContentGroup: group,
+			Contents:     groupContents[group.ID],
+		}
+		if bundleGroup.Contents == nil {
+			bundleGroup.Contents = make([]curriculum.Content, 0)
+		}
+
+		bundle.ContentGroups = append(bundle.ContentGroups, bundleGroup)
+	}
+
+	examActivityMap := map[int][]curriculum.ExamActivity{}
+	for _, row := range examActivities {
+		examActivityMap[row.ExamID] = append(examActivityMap[row.ExamID], row.ExamActivity)
+	}
+
+	for i, exam := range bundle.Exams {
+		bundle.Exams[i].Activities = examActivityMap[exam.ID]
+		if bundle.Exams[i].Activities == nil {
+			bundle.Exams[i].Activities = make([]curriculum.ExamActivity, 0)
+		}
+	}
+
+	bundle.Sort()
+
+	return &bundle, nil
+}
+
+// getChoices loads the choices of every activity in the structure its type is
+// authored in, see activity.ActivityType. The choices of the built-in types
+// with one table are read at once instead of through the cache.
+func (r curriculumRepository) getChoices(activities []activity.Activity) (map[int]interface{}, error) {
+	matchingRows := make([]matchingChoiceRow, 0)
+	multipleRows := make([]multipleChoiceRow, 0)
+	completionRows := make([]completionChoiceRow, 0)
+
+	queries := []struct {
+		table string
+		order string
+		dest  interface{}
+	}{
+		{TableName.MatchingChoice, "matching_choice_id", &matchingRows},
+		{TableName.MultipleChoice, "multiple_choice_id", &multipleRows},
+		{TableName.CompletionChoice, "completion_choice_id", &completionRows},
+	}
+
+	for _, query := range queries {
+		err := r.db.GetDB().Table(query.table).Order(query.order).Find(query.dest).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	matchingChoices := map[int]activity.MatchingChoices{}
+	for _, row := range matchingRows {
+		matchingChoices[row.ActivityID] = append(matchingChoices[row.ActivityID], row.MatchingChoice)
+	}
+
+	multipleChoices := map[int]activity.MultipleChoices{}
+	for _, row := range multipleRows {
+		multipleChoices[row.ActivityID] = append(multipleChoices[row.ActivityID], row.MultipleChoice)
+	}
+
+	completionChoices := map[int]activity.CompletionChoices{}
+	for _, row := range completionRows {
+		completionChoices[row.ActivityID] = append(completionChoices[row.ActivityID], row.CompletionChoice)
+	}
+
+	vocabGroupChoices, err := r.getVocabGroupChoices()
+	if err != nil {
+		return nil, err
+	}
+
+	choices := map[int]interface{}{}
+
+	for _, _activity := range activities {
+		var choice interface{}
+
+		switch _activity.TypeID {
+		case activity.TYPE_MATCHING:
+			choice = matchingChoices[_activity.ID]
+		case activity.TYPE_MULTIPLE:
+			choice = multipleChoices[_activity.ID]
+		case activity.TYPE_COMPLETION:
+			choice = completionChoices[_activity.ID]
+		case activity.TYPE_VOCAB_GROUP:
+			choice = vocabGroupChoices[_activity.ID]
+		default:
+			if !activity.HasChoices(_activity.TypeID) {
+				break
+			}
+
+			activityChoices, err := r.learning.GetActivityChoices(_activity.ID, _activity.TypeID)
+			if err != nil {
+				return nil, err
+			}
+			choice = activity.AuthoredChoices(activityChoices, _activity.TypeID)
+		}
+
+		choices[_activity.ID] = curriculum.SortChoices(choice)
+	}
+
+	return choices, nil
+}
+
+func (r curriculumRepository) getVocabGroupChoices() (map[int]activity.VocabGroupChoice, error) {
+	rows, err := r.db.GetDB().
+		Select(
+			TableName.VocabGroupChoice+"."+IDName.Activity,
+			TableName.VocabGroup+"."+IDName.VocabGroup,
+			TableName.VocabGroup+".name",
+			TableName.VocabGroupChoice+".vocab",
+		).
+		Table(TableName.VocabGroupChoice).
+		Joins(fmt.Sprintf("INNER JOIN %s ON %s.%s = %s.%s",
+			TableName.VocabGroup,
+			TableName.VocabGroup,
+			IDName.VocabGroup,
+			TableName.VocabGroupChoice,
+			IDName.VocabGroup,
+		)).
+		Order(TableName.VocabGroupChoice + "." + IDName.Activity).
+		Order(TableName.VocabGroup + "." + IDName.VocabGroup).
+		Rows()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	choices := map[int]activity.VocabGroupChoice{}
+	lastGroupIDs := map[int]int{}
+
+	for rows.Next() {
+		var activityID, groupID int
+		var name, vocab string
+
+		err = rows.Scan(&activityID, &groupID, &name, &vocab)
+		if err != nil {
+			return nil, err
+		}
+
+		choice := choices[activityID]
+		if lastGroupIDs[activityID] != groupID {
+			lastGroupIDs[activityID] = groupID
+			choice.Groups = append(choice.Groups, activity.VocabGroup{GroupName: name})
+		}
+
+		last := &choice.Groups[len(choice.Groups)-1]
+		last.Vocabs = append(last.Vocabs, vocab)
+
+		choices[activityID] = choice
+	}
+
+	return choices, rows.Err()
+}
+
+// ImportBundle writes the rows that the diff creates or updates in one
+// transaction. Rows that are only in the database are left alone, learners may
+// have progress on them.
+func (r curriculumRepository) ImportBundle(bundle curriculum.Bundle, diff curriculum.Diff) error {
+	tx := r.db.GetDB().Begin()
+
+	err := r.importBundle(tx, bundle, diff)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return err
+	}
+
+	return r.clearCache(bundle)
+}
+
+func (r curriculumRepository) importBundle(tx *gorm.DB, bundle curriculum.Bundle, diff curriculum.Diff) error {
+	for _, badge := range bundle.Badges {
+		if diff.Changes(curriculum.KIND_BADGE, badge.ID) {
+			if err := r.upsert(tx, TableName.Badge, &badge); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Content groups refer to their mini exam
+	for _, exam := range bundle.Exams {
+		if diff.Changes(curriculum.KIND_EXAM, exam.ID) {
+			if err := r.upsert(tx, TableName.Exam, &exam); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, group := range bundle.ContentGroups {
+		if diff.Changes(curriculum.KIND_CONTENT_GROUP, group.ID) {
+			if err := r.upsert(tx, TableName.ContentGroup, &group.ContentGroup); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, _content := range bundle.AllContents() {
+		if diff.Changes(curriculum.KIND_CONTENT, _content.ID) {
+			if err := r.upsert(tx, TableName.Content, &_content.Content); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, _activity := range bundle.AllActivities() {
+		if diff.Changes(curriculum.KIND_ACTIVITY, _activity.ID) {
+			if err := r.upsert(tx, TableName.Activity, &_activity.Activity); err != nil {
+				return err
+			}
+		}
+
+		if diff.Changes(curriculum.KIND_CHOICES, _activity.ID) {
+			err := r.admin.deleteChoices(tx, _activity.ID)
+			if err == nil {
+				err = r.admin.insertChoices(tx, _activity.ID, _activity.TypeID, _activity.Choices)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, hint := range _activity.Hints {
+			if diff.Changes(curriculum.KIND_HINT, hint.ID) {
+				if err := r.upsert(tx, TableName.Hint, &hint); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, exam := range bundle.Exams {
+		if !diff.Changes(curriculum.KIND_EXAM_ACTIVITIES, exam.ID) {
+			continue
+		}
+
+		err := tx.Table(TableName.ContentExam).
+			Where(IDName.Exam+" = ?", exam.ID).
+			Delete(map[string]interface{}{}).
+			Error
+		if err != nil {
+			return err
+		}
+
+		if len(exam.Activities) == 0 {
+			continue
+		}
+
+		rows := make([]contentExamRow, 0, len(exam.Activities))
+		for _, examActivity := range exam.Activities {
+			rows = append(rows, contentExamRow{ExamID: exam.ID, ExamActivity: examActivity})
+		}
+
+		err = tx.Table(TableName.ContentExam).Create(&rows).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	// Prerequisites refer to contents, content groups and badges
+	for _, prerequisite := range bundle.Prerequisites {
+		if diff.Changes(curriculum.KIND_PREREQUISITE, prerequisite.ID) {
+			if err := r.upsert(tx, TableName.Prerequisite, &prerequisite); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// upsert inserts the row with its id, or replaces the row of that id
+func (r curriculumRepository) upsert(tx *gorm.DB, table string, value interface{}) error {
+	return tx.Table(table).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(value).
+		Error
+}
+
+// clearCache clears the cached curriculum as a whole, activities may have
+// moved from contents and exams that are not in the bundle.
+func (r curriculumRepository) clearCache(bundle curriculum.Bundle) error {
+	contentIDs := make([]int, 0)
+	err := r.db.GetDB().Table(TableName.Content).Pluck(IDName.Content, &contentIDs).Error
+	if err != nil {
+		return err
+	}
+
+	examIDs := make([]int, 0)
+	err = r.db.GetDB().Table(TableName.Exam).Pluck(IDName.Exam, &examIDs).Error
+	if err != nil {
+		return err
+	}
+
+	keys := append(r.admin.contentGroupKeys(), r.admin.prerequisiteKey())
+
+	for _, contentID := range contentIDs {
+		keys = append(keys,
+			"learningRepository::GetContent::"+utils.ParseString(contentID),
+			"learningRepository::GetContentActivity::"+utils.ParseString(contentID),
+		)
+	}
+
+	for _, examID := range examIDs {
+		keys = append(keys,
+			"examRepository::GetExam::"+utils.ParseString(examID),
+			"examRepository::GetExamActivities::"+utils.ParseString(examID),
+		)
+	}
+
+	for _, _activity := range bundle.AllActivities() {
+		keys = append(keys, r.admin.activityKeys(_activity.ID)...)
+	}
+
+	return r.cache.Delete(keys...)
+}
@@ -30,6 +30,15 @@ var TableName = struct {
 	Relationship        string
 	ERAnswer            string
 	ERAnswerTables      string
+	Session             string
+	UserRole            string
+	UserIdentity        string
+	PointTransaction    string
+	ReviewSchedule      string
+	ContentMastery      string
+	Prerequisite        string
+	Attachment          string
+	AttachmentDownload  string
 }{
 	"User",
 	"Content",
@@ -60,6 +69,15 @@ var TableName = struct {
 	"Relationship",
 	"ERAnswer",
 	"ERAnswerTables",
+	"Session",
+	"UserRole",
+	"UserIdentity",
+	"PointTransaction",
+	"ReviewSchedule",
+	"ContentMastery",
+	"Prerequisite",
+	"Attachment",
+	"AttachmentDownload",
 }
 
 var IDName = struct {
@@ -81,6 +99,7 @@ var IDName = struct {
 	Attribute        string
 	Relationship     string
 	ERAnswer         string
+	Session          string
 }{
 	"user_id",
 	"activity_id",
@@ -100,6 +119,7 @@ var IDName = struct {
 	"attribute_id",
 	"relationship_id",
 	"er_answer_id",
+	"session_id",
 }
 
 var ViewName = struct {
@@ -49,7 +49,7 @@ func (r examRepository) GetExam(id int) (*exam.Exam, error) {
 	if data, err := json.Marshal(exam); err != nil {
 		return nil, err
 	} else {
-		if err = r.cache.Set(key, string(data), time.Minute*300); err != nil {
+		if err = r.cache.Set(key, string(data), time.Second*10); err != nil {
 			return nil, err
 		}
 	}
@@ -94,7 +94,7 @@ func (r examRepository) GetExams() ([]exam.Exam, error) {
 	if data, err := json.Marshal(exam); err != nil {
 		return nil, err
 	} else {
-		if err = r.cache.Set(key, string(data), time.Minute*300); err != nil {
+		if err = r.cache.Set(key, string(data), time.Second*10); err != nil {
 			return nil, err
 		}
 	}
@@ -118,6 +118,7 @@ func (r examRepository) GetExamActivities(id int) ([]exam.ExamActivity, error) {
 		Select(
 			TableName.ContentExam+".activity_id AS activity_id",
 			TableName.Activity+".activity_type_id AS activity_type_id",
+			TableName.ContentExam+".content_group_id AS content_group_id",
 		).
 		Joins(fmt.Sprintf(
 			"INNER JOIN %s ON %s.%s = %s.%s",
@@ -134,7 +135,7 @@ func (r examRepository) GetExamActivities(id int) ([]exam.ExamActivity, error) {
 	if data, err := json.Marshal(activities); err != nil {
 		return nil, err
 	} else {
-		if err = r.cache.Set(key, string(data), time.Minute*300); err != nil {
+		if err = r.cache.Set(key, string(data), time.Second*10); err != nil {
 			return nil, err
 		}
 	}
@@ -163,7 +164,7 @@ func (r examRepository) GetExamResult(userID int, examResultID int) (*exam.ExamR
 	if data, err := json.Marshal(result); err != nil {
 		return nil, err
 	} else {
-		if err = r.cache.Set(key, string(data), time.Minute*300); err != nil {
+		if err = r.cache.Set(key, string(data), time.Second*10); err != nil {
 			return nil, err
 		}
 	}
@@ -190,7 +191,7 @@ func (r examRepository) GetExamResults(userID int) ([]exam.ExamResult, error) {
 			TableName.ExamResult+".user_id AS user_id",
 			TableName.ExamResult+".is_passed AS is_passed",
 			TableName.ExamResult+".created_timestamp AS created_timestamp",
-			fmt.Sprintf("COUNT(%s.score) AS score", TableName.ExamResultActivity),
+			fmt.Sprintf("COALESCE(SUM(%s.score), 0) AS score", TableName.ExamResultActivity),
 		).
 		Joins(fmt.Sprintf("LEFT JOIN %s ON %s.%s = %s.%s",
 			TableName.ExamResultActivity,
@@ -207,7 +208,7 @@ func (r examRepository) GetExamResults(userID int) ([]exam.ExamResult, error) {
 	if data, err := json.Marshal(examResults); err != nil {
 		return nil, err
 	} else {
-		if err = r.cache.Set(key, string(data), time.Minute*300); err != nil {
+		if err = r.cache.Set(key, string(data), time.Second*10); err != nil {
 			return nil, err
 		}
 	}
@@ -235,7 +236,7 @@ func (r examRepository) GetActivitiesResult(examResultID int) ([]exam.ResultActi
 	if data, err := json.Marshal(activities); err != nil {
 		return nil, err
 	} else {
-		if err = r.cache.Set(key, string(data), time.Minute*300); err != nil {
+		if err = r.cache.Set(key, string(data), time.Second*10); err != nil {
 			return nil, err
 		}
 	}
@@ -7,11 +7,15 @@ import (
 	"database-camp/internal/infrastructure/storage"
 	"database-camp/internal/models/entities/activity"
 	"database-camp/internal/models/entities/content"
+	"database-camp/internal/models/entities/user"
 	"database-camp/internal/utils"
 	"encoding/json"
 	"fmt"
 	"sync"
 	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type LearningRepository interface {
@@ -23,11 +27,20 @@ type LearningRepository interface {
 	GetVideoFileLink(imagekey string) (string, error)
 	GetActivityChoices(activityID int, activityTypeID int) (activity.Choices, error)
 	GetContentGroups() (groups content.ContentGroups, err error)
-	GetCorrectProgression(activityID int) (progression *content.LearningProgression, err error)
+	GetPrerequisites() (content.Prerequisites, error)
 	GetPeerChoice(erAnswerID *int) (activity.ERAnswer, error)
 	GetERChoice(activityID int) (activity.ERChoice, error)
-	UseHint(userID int, reducePoint int, hintID int) error
+	UseHint(userID int, reducePoint int, hintID int) (bool, error)
 	InsertERAnswer(answer activity.ERAnswer, userID int) error
+	GetDueReviews(userID int, now time.Time, limit int, excludedActivityIDs []int) ([]content.ReviewSchedule, error)
+	CountDueReviews(userID int, now time.Time, excludedActivityIDs []int) (int64, error)
+	GetNextReview(userID int, now time.Time, excludedActivityIDs []int) (*content.ReviewSchedule, error)
+	ReviewActivity(userID int, activityID int, quality int, now time.Time) (*content.ReviewSchedule, error)
+	GetMasteries(userID int) (content.Masteries, error)
+	UpdateMasteries(userID int, observations []content.MasteryObservation, now time.Time) error
+	GetContentAttachments(contentID int) ([]content.Attachment, error)
+	GetAttachment(id int) (*content.Attachment, error)
+	InsertAttachmentDownload(download *content.AttachmentDownload) error
 }
 
 type learningRepository struct {
@@ -59,7 +72,7 @@ func (r learningRepository) GetContent(id int) (*content.Content, error) {
 	if data, err := json.Marshal(content); err != nil {
 		return nil, err
 	} else {
-		if err = r.cache.Set(key, string(data), time.Minute*300); err != nil {
+		if err = r.cache.Set(key, string(data), time.Second*10); err != nil {
 			return nil, err
 		}
 	}
@@ -95,7 +108,7 @@ func (r learningRepository) GetOverview() ([]content.Overview, error) {
 	if data, err := json.Marshal(overview); err != nil {
 		return nil, err
 	} else {
-		if err = r.cache.Set(key, string(data), time.Minute*300); err != nil {
+		if err = r.cache.Set(key, string(data), time.Second*10); err != nil {
 			return nil, err
 		}
 	}
@@ -123,7 +136,7 @@ func (r learningRepository) GetContentActivity(contentID int) ([]activity.Activi
 	if data, err := json.Marshal(activity); err != nil {
 		return nil, err
 	} else {
-		if err = r.cache.Set(key, string(data), time.Minute*300); err != nil {
+		if err = r.cache.Set(key, string(data), time.Second*10); err != nil {
 			return nil, err
 		}
 	}
@@ -151,7 +164,7 @@ func (r learningRepository) GetActivity(id int) (*activity.Activity, error) {
 	if data, err := json.Marshal(activity); err != nil {
 		return nil, err
 	} else {
-		if err = r.cache.Set(key, string(data), time.Minute*300); err != nil {
+		if err = r.cache.Set(key, string(data), time.Second*10); err != nil {
 			return nil, err
 		}
 	}
@@ -179,7 +192,7 @@ func (r learningRepository) getMatchingChoice(activityID int) (activity.Matching
 	if data, err := json.Marshal(matchingChoice); err != nil {
 		return nil, err
 	} else {
-		if err = r.cache.Set(key, string(data), time.Minute*300); err != nil {
+		if err = r.cache.Set(key, string(data), time.Second*10); err != nil {
 			return nil, err
 		}
 	}
@@ -207,7 +220,7 @@ func (r learningRepository) getMultipleChoice(activityID int) (activity.Multiple
 	if data, err := json.Marshal(multipleChoice); err != nil {
 		return nil, err
 	} else {
-		if err = r.cache.Set(key, string(data), time.Minute*300); err != nil {
+		if err = r.cache.Set(key, string(data), time.Second*10); err != nil {
 			return nil, err
 		}
 	}
@@ -235,7 +248,7 @@ func (r learningRepository) getCompletionChoice(activityID int) (activity.Comple
 	if data, err := json.Marshal(completionChoice); err != nil {
 		return nil, err
 	} else {
-		if err = r.cache.Set(key, string(data), time.Minute*300); err != nil {
+		if err = r.cache.Set(key, string(data), time.Second*10); err != nil {
 			return nil, err
 		}
 	}
@@ -294,7 +307,7 @@ func (r learningRepository) getVocabGroupChoice(activityID int) (activity.VocabG
 	if data, err := json.Marshal(vocalGroupChoice); err != nil {
 		return vocalGroupChoice, err
 	} else {
-		if err = r.cache.Set(key, string(data), time.Minute*300); err != nil {
+		if err = r.cache.Set(key, string(data), time.Second*10); err != nil {
 			return vocalGroupChoice, err
 		}
 	}
@@ -579,7 +592,7 @@ func (r learningRepository) GetActivityHints(activityID int) ([]activity.Hint, e
 	if data, err := json.Marshal(hints); err != nil {
 		return nil, err
 	} else {
-		if err = r.cache.Set(key, string(data), time.Minute*300); err != nil {
+		if err = r.cache.Set(key, string(data), time.Second*10); err != nil {
 			return nil, err
 		}
 	}
@@ -604,7 +617,7 @@ func (r learningRepository) GetVideoFileLink(objectName string) (string, error)
 		return "", err
 	}
 
-	err = r.cache.Set(key, link, time.Minute*15)
+	err = r.cache.Set(key, link, time.Minute*10)
 	if err != nil {
 		return "", err
 	}
@@ -613,24 +626,12 @@ func (r learningRepository) GetVideoFileLink(objectName string) (string, error)
 }
 
 func (r learningRepository) GetActivityChoices(activityID int, activityTypeID int) (activity.Choices, error) {
-	switch activityTypeID {
-	case 1:
-		return r.getMatchingChoice(activityID)
-	case 2:
-		return r.getMultipleChoice(activityID)
-	case 3:
-		return r.getCompletionChoice(activityID)
-	case 4:
-		return r.getVocabGroupChoice(activityID)
-	case 5:
-		return r.getDependencyChoice(activityID)
-	case 6:
-		return r.GetERChoice(activityID)
-	case 7:
-		return r.GetPeerChoice(nil)
-	default:
-		return nil, errs.ErrActivityTypeInvalid
+	loader, err := getChoiceLoader(activityTypeID)
+	if err != nil {
+		return nil, err
 	}
+
+	return loader(r.db, r.cache, activityID)
 }
 
 func (r learningRepository) GetContentGroups() (groups content.ContentGroups, err error) {
@@ -641,54 +642,70 @@ func (r learningRepository) GetContentGroups() (groups content.ContentGroups, er
 	return
 }
 
-func (r learningRepository) GetCorrectProgression(activityID int) (progression *content.LearningProgression, err error) {
-	err = r.db.GetDB().
-		Table(TableName.LearningProgression).
-		Where("is_correct = 1").
-		Find(&progression).
+func (r learningRepository) GetPrerequisites() (content.Prerequisites, error) {
+	prerequisites := make(content.Prerequisites, 0)
+
+	key := "learningRepository::GetPrerequisites"
+
+	if cacheData, err := r.cache.Get(key); err == nil {
+		if err = json.Unmarshal([]byte(cacheData), &prerequisites); err == nil {
+			return prerequisites, nil
+		}
+	}
+
+	err := r.db.GetDB().
+		Table(TableName.Prerequisite).
+		Order("prerequisite_id").
+		Find(&prerequisites).
 		Error
-	return
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(prerequisites); err != nil {
+		return nil, err
+	} else {
+		if err = r.cache.Set(key, string(data), time.Second*10); err != nil {
+			return nil, err
+		}
+	}
+
+	return prerequisites, nil
 }
 
-func (r learningRepository) UseHint(userID int, reducePoint int, hintID int) error {
-	routine := 0
-	errs := make(chan error, 2)
+// UseHint debits the point of the hint and records the hint as used, false
+// when the user does not have the point
+func (r learningRepository) UseHint(userID int, reducePoint int, hintID int) (bool, error) {
 	tx := r.db.GetDB().Begin()
 
-	go func() {
-		statement := fmt.Sprintf("UPDATE %s SET point = point - %d WHERE %s = %d",
-			TableName.User,
-			reducePoint,
-			IDName.User,
-			userID,
-		)
-		temp := map[string]interface{}{}
-		errs <- tx.Raw(statement).Find(&temp).Error
-	}()
+	ok, err := addPoint(tx, &user.PointTransaction{
+		UserID:      userID,
+		Amount:      -reducePoint,
+		Reason:      user.POINT_REASON_HINT,
+		ReferenceID: &hintID,
+	})
+	if err != nil || !ok {
+		tx.Rollback()
+		return false, err
+	}
 
-	go func() {
-		hint := activity.UserHint{
-			UserID:           userID,
-			HintID:           hintID,
-			CreatedTimestamp: time.Now().Local(),
-		}
-		errs <- tx.Table(TableName.UserHint).Create(&hint).Error
-	}()
+	hint := activity.UserHint{
+		UserID:           userID,
+		HintID:           hintID,
+		CreatedTimestamp: time.Now().Local(),
+	}
 
-	for err := range errs {
-		routine++
-		if err != nil {
-			close(errs)
-			tx.Rollback()
-			return err
-		}
-		if routine == 2 {
-			close(errs)
-		}
+	err = tx.Table(TableName.UserHint).Create(&hint).Error
+	if err != nil {
+		tx.Rollback()
+		return false, err
 	}
 
-	tx.Commit()
-	return nil
+	if err = tx.Commit().Error; err != nil {
+		return false, err
+	}
+
+	return true, NewUserRepository(r.db, r.cache).ClearRankingCache(userID)
 }
 
 func (r learningRepository) InsertERAnswer(answer activity.ERAnswer, userID int) error {
@@ -799,3 +816,207 @@ func (r learningRepository) InsertERAnswer(answer activity.ERAnswer, userID int)
 	tx.Commit()
 	return nil
 }
+
+// excludeActivities leaves the activities out of a query, NOT IN with no
+// values would leave everything out
+func excludeActivities(db *gorm.DB, activityIDs []int) *gorm.DB {
+	if len(activityIDs) == 0 {
+		return db
+	}
+	return db.Where(IDName.Activity+" NOT IN ?", activityIDs)
+}
+
+func (r learningRepository) GetDueReviews(userID int, now time.Time, limit int, excludedActivityIDs []int) ([]content.ReviewSchedule, error) {
+	schedules := make([]content.ReviewSchedule, 0)
+
+	err := excludeActivities(r.db.GetDB().Table(TableName.ReviewSchedule), excludedActivityIDs).
+		Where(IDName.User+" = ? AND due_timestamp <= ?", userID, now).
+		Order("due_timestamp").
+		Limit(limit).
+		Find(&schedules).
+		Error
+
+	return schedules, err
+}
+
+func (r learningRepository) CountDueReviews(userID int, now time.Time, excludedActivityIDs []int) (int64, error) {
+	var count int64
+
+	err := excludeActivities(r.db.GetDB().Table(TableName.ReviewSchedule), excludedActivityIDs).
+		Where(IDName.User+" = ? AND due_timestamp <= ?", userID, now).
+		Count(&count).
+		Error
+
+	return count, err
+}
+
+// GetNextReview returns the schedule that is due next after now, nil when
+// the user has nothing scheduled
+func (r learningRepository) GetNextReview(userID int, now time.Time, excludedActivityIDs []int) (*content.ReviewSchedule, error) {
+	schedules := make([]content.ReviewSchedule, 0)
+
+	err := excludeActivities(r.db.GetDB().Table(TableName.ReviewSchedule), excludedActivityIDs).
+		Where(IDName.User+" = ? AND due_timestamp > ?", userID, now).
+		Order("due_timestamp").
+		Limit(1).
+		Find(&schedules).
+		Error
+	if err != nil || len(schedules) == 0 {
+		return nil, err
+	}
+
+	return &schedules[0], nil
+}
+
+/**
+ * Schedule an activity of a user again after an answer to it, the schedule
+ * starts with the first answer that is recalled
+ *
+ * @param userID 		user who answered
+ * @param activityID 	activity answered
+ * @param quality 		quality of the answer from content.ReviewQuality
+ * @param now 			time of the answer
+ *
+ * @return the schedule, nil when the activity is not scheduled
+ */
+func (r learningRepository) ReviewActivity(userID int, activityID int, quality int, now time.Time) (*content.ReviewSchedule, error) {
+	tx := r.db.GetDB().Begin()
+
+	schedules := make([]content.ReviewSchedule, 0)
+	err := tx.Table(TableName.ReviewSchedule).
+		Clauses(lockForUpdate).
+		Where(IDName.User+" = ? AND "+IDName.Activity+" = ?", userID, activityID).
+		Find(&schedules).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var schedule content.ReviewSchedule
+	if len(schedules) > 0 {
+		schedule = schedules[0]
+	} else if quality >= content.REVIEW_QUALITY_PASS {
+		schedule = content.NewReviewSchedule(userID, activityID, now)
+	} else {
+		tx.Rollback()
+		return nil, nil
+	}
+
+	if !schedule.Review(quality, now) {
+		tx.Rollback()
+		return &schedule, nil
+	}
+
+	err = tx.Table(TableName.ReviewSchedule).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&schedule).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return &schedule, tx.Commit().Error
+}
+
+func (r learningRepository) GetMasteries(userID int) (content.Masteries, error) {
+	masteries := make(content.Masteries, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.ContentMastery).
+		Where(IDName.User+" = ?", userID).
+		Find(&masteries).
+		Error
+
+	return masteries, err
+}
+
+// UpdateMasteries updates the masteries of the user with the answers in the
+// order they are given
+func (r learningRepository) UpdateMasteries(userID int, observations []content.MasteryObservation, now time.Time) error {
+	if len(observations) == 0 {
+		return nil
+	}
+
+	contentIDs := make([]int, 0, len(observations))
+	for _, observation := range observations {
+		contentIDs = append(contentIDs, observation.ContentID)
+	}
+
+	tx := r.db.GetDB().Begin()
+
+	masteries := make(content.Masteries, 0)
+	err := tx.Table(TableName.ContentMastery).
+		Clauses(lockForUpdate).
+		Where(IDName.User+" = ? AND "+IDName.Content+" IN ?", userID, contentIDs).
+		Find(&masteries).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	masteryMap := map[int]*content.Mastery{}
+	for i := range masteries {
+		masteryMap[masteries[i].ContentID] = &masteries[i]
+	}
+
+	updated := make(content.Masteries, 0, len(observations))
+	for _, observation := range observations {
+		mastery := masteryMap[observation.ContentID]
+		if mastery == nil {
+			newMastery := content.NewMastery(userID, observation.ContentID)
+			mastery = &newMastery
+			masteryMap[observation.ContentID] = mastery
+		}
+
+		mastery.Observe(observation.Score, now)
+	}
+
+	for _, mastery := range masteryMap {
+		updated = append(updated, *mastery)
+	}
+
+	err = tx.Table(TableName.ContentMastery).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&updated).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (r learningRepository) GetContentAttachments(contentID int) ([]content.Attachment, error) {
+	attachments := make([]content.Attachment, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.Attachment).
+		Where(IDName.Content+" = ?", contentID).
+		Order("attachment_id").
+		Find(&attachments).
+		Error
+
+	return attachments, err
+}
+
+func (r learningRepository) GetAttachment(id int) (*content.Attachment, error) {
+	attachment := content.Attachment{}
+
+	err := r.db.GetDB().
+		Table(TableName.Attachment).
+		Where("attachment_id = ?", id).
+		Find(&attachment).
+		Error
+
+	return &attachment, err
+}
+
+func (r learningRepository) InsertAttachmentDownload(download *content.AttachmentDownload) error {
+	download.CreatedTimestamp = time.Now().Local()
+
+	return r.db.GetDB().Table(TableName.AttachmentDownload).Create(download).Error
+}
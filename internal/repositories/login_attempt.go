@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"database-camp/internal/infrastructure/cache"
+	"strings"
+	"time"
+)
+
+// LoginAttemptRepository keeps failed login counters, delays and lockouts in
+// the cache. A subject is either an email or a client IP.
+type LoginAttemptRepository interface {
+	IncrLoginFailure(subject string, window time.Duration) (int, error)
+	ResetLoginFailure(subject string) error
+	GetLoginDelay(subject string) (time.Duration, error)
+	SetLoginDelay(subject string, delay time.Duration) error
+	GetLoginLock(email string) (time.Duration, error)
+	SetLoginLock(email string, duration time.Duration) error
+	DeleteLoginLock(email string) error
+	SetUnlockToken(unlockToken string, email string, expiration time.Duration) error
+	PopUnlockToken(unlockToken string) (string, error)
+}
+
+type loginAttemptRepository struct {
+	cache cache.Cache
+}
+
+func NewLoginAttemptRepository(cache cache.Cache) *loginAttemptRepository {
+	return &loginAttemptRepository{cache: cache}
+}
+
+func (r loginAttemptRepository) key(name string, subject string) string {
+	return "loginAttemptRepository::" + name + "::" + strings.ToLower(subject)
+}
+
+func (r loginAttemptRepository) IncrLoginFailure(subject string, window time.Duration) (int, error) {
+	count, err := r.cache.Incr(r.key("Failure", subject), window)
+	return int(count), err
+}
+
+func (r loginAttemptRepository) ResetLoginFailure(subject string) error {
+	return r.cache.Delete(r.key("Failure", subject), r.key("Delay", subject))
+}
+
+func (r loginAttemptRepository) GetLoginDelay(subject string) (time.Duration, error) {
+	return r.remaining(r.key("Delay", subject))
+}
+
+func (r loginAttemptRepository) SetLoginDelay(subject string, delay time.Duration) error {
+	return r.cache.Set(r.key("Delay", subject), time.Now().Local().Unix(), delay)
+}
+
+func (r loginAttemptRepository) GetLoginLock(email string) (time.Duration, error) {
+	return r.remaining(r.key("Lock", email))
+}
+
+func (r loginAttemptRepository) SetLoginLock(email string, duration time.Duration) error {
+	return r.cache.Set(r.key("Lock", email), time.Now().Local().Unix(), duration)
+}
+
+// DeleteLoginLock also clears the failures, so an unlocked account starts over.
+func (r loginAttemptRepository) DeleteLoginLock(email string) error {
+	return r.cache.Delete(r.key("Lock", email), r.key("Failure", email), r.key("Delay", email))
+}
+
+func (r loginAttemptRepository) SetUnlockToken(unlockToken string, email string, expiration time.Duration) error {
+	return r.cache.Set(r.key("UnlockToken", unlockToken), strings.ToLower(email), expiration)
+}
+
+func (r loginAttemptRepository) PopUnlockToken(unlockToken string) (string, error) {
+	return r.cache.GetDel(r.key("UnlockToken", unlockToken))
+}
+
+// remaining returns how long the key lives on, zero when it does not exist.
+func (r loginAttemptRepository) remaining(key string) (time.Duration, error) {
+	ttl, err := r.cache.TTL(key)
+	if err != nil || ttl < 0 {
+		return 0, err
+	}
+	return ttl, nil
+}
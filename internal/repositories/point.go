@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"database-camp/internal/infrastructure/cache"
+	"database-camp/internal/infrastructure/database"
+	"database-camp/internal/models/entities/user"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// lockForUpdate locks the rows read until the transaction ends
+var lockForUpdate = clause.Locking{Strength: "UPDATE"}
+
+// PointRepository reads the point ledger of the users, the point of a user
+// only changes through addPoint so that every change has its transaction.
+type PointRepository interface {
+	GetPointTransactions(userID int, limit int, offset int) ([]user.PointTransaction, error)
+	AddPoint(transaction user.PointTransaction) (*user.PointTransaction, bool, error)
+	GetPointDiscrepancies() ([]user.PointDiscrepancy, error)
+	ReconcilePoint(userID int) error
+}
+
+type pointRepository struct {
+	db    database.MysqlDB
+	cache cache.Cache
+}
+
+func NewPointRepository(db database.MysqlDB, cache cache.Cache) *pointRepository {
+	return &pointRepository{db: db, cache: cache}
+}
+
+func (r pointRepository) GetPointTransactions(userID int, limit int, offset int) ([]user.PointTransaction, error) {
+	transactions := make([]user.PointTransaction, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.PointTransaction).
+		Where(IDName.User+" = ?", userID).
+		Order("point_transaction_id desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&transactions).
+		Error
+
+	return transactions, err
+}
+
+// AddPoint records a transaction on its own, false when a debit is more than
+// the user has
+func (r pointRepository) AddPoint(transaction user.PointTransaction) (*user.PointTransaction, bool, error) {
+	tx := r.db.GetDB().Begin()
+
+	ok, err := addPoint(tx, &transaction)
+	if err != nil || !ok {
+		tx.Rollback()
+		return nil, ok, err
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return nil, false, err
+	}
+
+	return &transaction, true, r.clearRankingCache(transaction.UserID)
+}
+
+func (r pointRepository) GetPointDiscrepancies() ([]user.PointDiscrepancy, error) {
+	discrepancies := make([]user.PointDiscrepancy, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.User).
+		Select(
+			TableName.User+".user_id AS user_id",
+			TableName.User+".point AS point",
+			fmt.Sprintf("COALESCE(SUM(%s.amount), 0) AS ledger_point", TableName.PointTransaction),
+		).
+		Joins(fmt.Sprintf("LEFT JOIN %s ON %s.%s = %s.%s",
+			TableName.PointTransaction,
+			TableName.PointTransaction,
+			IDName.User,
+			TableName.User,
+			IDName.User,
+		)).
+		Group(TableName.User + "." + IDName.User).
+		Having(fmt.Sprintf("%s.point <> COALESCE(SUM(%s.amount), 0)", TableName.User, TableName.PointTransaction)).
+		Find(&discrepancies).
+		Error
+
+	return discrepancies, err
+}
+
+// ReconcilePoint sets the point of the user to the sum of the ledger
+func (r pointRepository) ReconcilePoint(userID int) error {
+	tx := r.db.GetDB().Begin()
+
+	var point int
+	err := tx.Table(TableName.User).
+		Select("point").
+		Where(IDName.User+" = ?", userID).
+		Clauses(lockForUpdate).
+		Take(&point).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var ledgerPoint int
+	err = tx.Table(TableName.PointTransaction).
+		Select("COALESCE(SUM(amount), 0)").
+		Where(IDName.User+" = ?", userID).
+		Take(&ledgerPoint).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Table(TableName.User).
+		Where(IDName.User+" = ?", userID).
+		Update("point", ledgerPoint).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return r.clearRankingCache(userID)
+}
+
+func (r pointRepository) clearRankingCache(userID int) error {
+	return NewUserRepository(r.db, r.cache).ClearRankingCache(userID)
+}
+
+/**
+ * Change the point of a user and record the transaction in the same database
+ * transaction. A debit only happens if the user has the point, so concurrent
+ * debits can never take the point below zero.
+ *
+ * @param tx 			database transaction the change belongs to
+ * @param transaction 	change to make, its balance and timestamp are set
+ *
+ * @return false when a debit is more than the user has
+ */
+func addPoint(tx *gorm.DB, transaction *user.PointTransaction) (bool, error) {
+	if transaction.Amount == 0 {
+		return true, nil
+	}
+
+	update := tx.Table(TableName.User).Where(IDName.User+" = ?", transaction.UserID)
+	if transaction.Amount < 0 {
+		update = update.Where("point >= ?", -transaction.Amount)
+	}
+
+	result := update.Update("point", gorm.Expr("point + ?", transaction.Amount))
+	if result.Error != nil {
+		return false, result.Error
+	} else if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	err := tx.Table(TableName.User).
+		Select("point").
+		Where(IDName.User+" = ?", transaction.UserID).
+		Take(&transaction.Balance).
+		Error
+	if err != nil {
+		return false, err
+	}
+
+	transaction.CreatedTimestamp = time.Now().Local()
+
+	return true, tx.Table(TableName.PointTransaction).Create(transaction).Error
+}
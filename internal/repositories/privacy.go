@@ -0,0 +1,358 @@
+package repositories
+
+import (
+	"database-camp/internal/infrastructure/cache"
+	"database-camp/internal/infrastructure/database"
+	"database-camp/internal/models/entities/activity"
+	"database-camp/internal/models/entities/badge"
+	"database-camp/internal/models/entities/content"
+	"database-camp/internal/models/entities/exam"
+	"database-camp/internal/models/entities/user"
+	"database-camp/internal/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PrivacyRepository reads the rows held about a user that no other repository
+// exposes in full, and removes every row of the user when the account is deleted.
+type PrivacyRepository interface {
+	GetLearningProgressions(userID int) ([]content.LearningProgression, error)
+	GetReviewSchedules(userID int) ([]content.ReviewSchedule, error)
+	GetMasteries(userID int) ([]content.Mastery, error)
+	GetAttachmentDownloads(userID int) ([]content.AttachmentDownload, error)
+	GetUserHints(userID int) ([]activity.UserHint, error)
+	GetPointTransactions(userID int) ([]user.PointTransaction, error)
+	GetUserBadges(userID int) ([]badge.UserBadge, error)
+	GetExamResultActivities(userID int) ([]exam.ResultActivity, error)
+	GetERAnswers(userID int) ([]activity.ERAnswer, error)
+	GetIdentities(userID int) ([]user.Identity, error)
+	GetDueDeletions(now time.Time) ([]int, error)
+	DeleteUser(userID int) error
+}
+
+type privacyRepository struct {
+	db    database.MysqlDB
+	cache cache.Cache
+}
+
+func NewPrivacyRepository(db database.MysqlDB, cache cache.Cache) *privacyRepository {
+	return &privacyRepository{db: db, cache: cache}
+}
+
+func (r privacyRepository) GetLearningProgressions(userID int) ([]content.LearningProgression, error) {
+	progressions := make([]content.LearningProgression, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.LearningProgression).
+		Where(IDName.User+" = ?", userID).
+		Order("created_timestamp").
+		Find(&progressions).
+		Error
+
+	return progressions, err
+}
+
+func (r privacyRepository) GetReviewSchedules(userID int) ([]content.ReviewSchedule, error) {
+	schedules := make([]content.ReviewSchedule, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.ReviewSchedule).
+		Where(IDName.User+" = ?", userID).
+		Order("created_timestamp").
+		Find(&schedules).
+		Error
+
+	return schedules, err
+}
+
+func (r privacyRepository) GetMasteries(userID int) ([]content.Mastery, error) {
+	masteries := make([]content.Mastery, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.ContentMastery).
+		Where(IDName.User+" = ?", userID).
+		Find(&masteries).
+		Error
+
+	return masteries, err
+}
+
+func (r privacyRepository) GetAttachmentDownloads(userID int) ([]content.AttachmentDownload, error) {
+	downloads := make([]content.AttachmentDownload, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.AttachmentDownload).
+		Where(IDName.User+" = ?", userID).
+		Order("created_timestamp").
+		Find(&downloads).
+		Error
+
+	return downloads, err
+}
+
+func (r privacyRepository) GetUserHints(userID int) ([]activity.UserHint, error) {
+	userHints := make([]activity.UserHint, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.UserHint).
+		Where(IDName.User+" = ?", userID).
+		Order("created_timestamp").
+		Find(&userHints).
+		Error
+
+	return userHints, err
+}
+
+func (r privacyRepository) GetPointTransactions(userID int) ([]user.PointTransaction, error) {
+	transactions := make([]user.PointTransaction, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.PointTransaction).
+		Where(IDName.User+" = ?", userID).
+		Order("created_timestamp").
+		Find(&transactions).
+		Error
+
+	return transactions, err
+}
+
+func (r privacyRepository) GetUserBadges(userID int) ([]badge.UserBadge, error) {
+	userBadges := make([]badge.UserBadge, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.UserBadge).
+		Where(IDName.User+" = ?", userID).
+		Find(&userBadges).
+		Error
+
+	return userBadges, err
+}
+
+func (r privacyRepository) GetExamResultActivities(userID int) ([]exam.ResultActivity, error) {
+	activities := make([]exam.ResultActivity, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.ExamResultActivity).
+		Where(IDName.ExamResult+" IN (?)", r.examResultIDs(r.db.GetDB(), userID)).
+		Find(&activities).
+		Error
+
+	return activities, err
+}
+
+func (r privacyRepository) GetERAnswers(userID int) ([]activity.ERAnswer, error) {
+	answers := make([]activity.ERAnswer, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.ERAnswer).
+		Where(IDName.User+" = ?", userID).
+		Find(&answers).
+		Error
+	if err != nil || len(answers) == 0 {
+		return answers, err
+	}
+
+	answerIDs := make([]int, 0, len(answers))
+	for _, answer := range answers {
+		answerIDs = append(answerIDs, answer.ID)
+	}
+
+	answerTables := make([]activity.ERAnswerTables, 0)
+	err = r.db.GetDB().
+		Table(TableName.ERAnswerTables).
+		Where(IDName.ERAnswer+" IN ?", answerIDs).
+		Find(&answerTables).
+		Error
+	if err != nil || len(answerTables) == 0 {
+		return answers, err
+	}
+
+	tableIDs := make([]string, 0, len(answerTables))
+	for _, answerTable := range answerTables {
+		tableIDs = append(tableIDs, answerTable.TableID)
+	}
+
+	tables := make([]activity.Table, 0)
+	err = r.db.GetDB().
+		Table(TableName.Tables).
+		Where(IDName.Table+" IN ?", tableIDs).
+		Find(&tables).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := make([]activity.Attribute, 0)
+	err = r.db.GetDB().
+		Table(TableName.Attributes).
+		Where(IDName.Table+" IN ?", tableIDs).
+		Find(&attributes).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	relationships := make([]activity.Relationship, 0)
+	err = r.db.GetDB().
+		Table(TableName.Relationship).
+		Where("table1_id IN ? OR table2_id IN ?", tableIDs, tableIDs).
+		Find(&relationships).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	tableMap := map[string]activity.Table{}
+	for _, table := range tables {
+		tableMap[table.ID] = table
+	}
+
+	for _, attribute := range attributes {
+		table := tableMap[attribute.TableID]
+		table.Attributes = append(table.Attributes, attribute)
+		tableMap[attribute.TableID] = table
+	}
+
+	answerIndex := map[int]int{}
+	tableAnswer := map[string]int{}
+	for i, answer := range answers {
+		answerIndex[answer.ID] = i
+	}
+
+	for _, answerTable := range answerTables {
+		i := answerIndex[answerTable.ERAnswerID]
+		answers[i].Tables = append(answers[i].Tables, tableMap[answerTable.TableID])
+		tableAnswer[answerTable.TableID] = i
+	}
+
+	for _, relationship := range relationships {
+		i := tableAnswer[relationship.Table1ID]
+		answers[i].Relationships = append(answers[i].Relationships, relationship)
+	}
+
+	return answers, nil
+}
+
+func (r privacyRepository) GetIdentities(userID int) ([]user.Identity, error) {
+	identities := make([]user.Identity, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.UserIdentity).
+		Where(IDName.User+" = ?", userID).
+		Find(&identities).
+		Error
+
+	return identities, err
+}
+
+func (r privacyRepository) GetDueDeletions(now time.Time) ([]int, error) {
+	userIDs := make([]int, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.User).
+		Where("deletion_timestamp <= ?", now).
+		Pluck(IDName.User, &userIDs).
+		Error
+
+	return userIDs, err
+}
+
+// DeleteUser removes the user and every row that belongs to them in one
+// transaction. Sessions must be removed through SessionRepository first so
+// their cache entries go with them.
+func (r privacyRepository) DeleteUser(userID int) error {
+	tx := r.db.GetDB().Begin()
+
+	answerIDs := r.db.GetDB().
+		Table(TableName.ERAnswer).
+		Select(IDName.ERAnswer).
+		Where(IDName.User+" = ?", userID)
+
+	tableIDs := make([]string, 0)
+	err := tx.Table(TableName.ERAnswerTables).
+		Where(IDName.ERAnswer+" IN (?)", answerIDs).
+		Pluck(IDName.Table, &tableIDs).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	examResultIDs := make([]int, 0)
+	err = tx.Table(TableName.ExamResult).
+		Where(IDName.User+" = ?", userID).
+		Pluck(IDName.ExamResult, &examResultIDs).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	deletes := make([]*gorm.DB, 0)
+
+	if len(tableIDs) > 0 {
+		deletes = append(deletes,
+			tx.Table(TableName.Relationship).Where("table1_id IN ? OR table2_id IN ?", tableIDs, tableIDs),
+			tx.Table(TableName.Attributes).Where(IDName.Table+" IN ?", tableIDs),
+		)
+	}
+
+	deletes = append(deletes, tx.Table(TableName.ERAnswerTables).Where(IDName.ERAnswer+" IN (?)", answerIDs))
+
+	if len(tableIDs) > 0 {
+		deletes = append(deletes, tx.Table(TableName.Tables).Where(IDName.Table+" IN ?", tableIDs))
+	}
+
+	deletes = append(deletes,
+		tx.Table(TableName.ERAnswer).Where(IDName.User+" = ?", userID),
+		tx.Table(TableName.ExamResultActivity).Where(IDName.ExamResult+" IN ?", examResultIDs),
+		tx.Table(TableName.ExamResult).Where(IDName.User+" = ?", userID),
+		tx.Table(TableName.LearningProgression).Where(IDName.User+" = ?", userID),
+		tx.Table(TableName.ReviewSchedule).Where(IDName.User+" = ?", userID),
+		tx.Table(TableName.ContentMastery).Where(IDName.User+" = ?", userID),
+		tx.Table(TableName.AttachmentDownload).Where(IDName.User+" = ?", userID),
+		tx.Table(TableName.UserHint).Where(IDName.User+" = ?", userID),
+		tx.Table(TableName.PointTransaction).Where(IDName.User+" = ?", userID),
+		tx.Table(TableName.UserBadge).Where(IDName.User+" = ?", userID),
+		tx.Table(TableName.UserIdentity).Where(IDName.User+" = ?", userID),
+		tx.Table(TableName.UserRole).Where(IDName.User+" = ?", userID),
+		tx.Table(TableName.Session).Where(IDName.User+" = ?", userID),
+		tx.Table(TableName.User).Where(IDName.User+" = ?", userID),
+	)
+
+	for _, query := range deletes {
+		err = query.Delete(map[string]interface{}{}).Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return err
+	}
+
+	keys := []string{
+		"userRepository::GetRankingLeaderBoard",
+		"userRepository::GetPointRanking::" + utils.ParseString(userID),
+		"examRepository::GetExamResults::" + utils.ParseString(userID),
+	}
+
+	for _, examResultID := range examResultIDs {
+		keys = append(keys,
+			"examRepository::GetExamResult::"+utils.ParseString(userID)+"::"+utils.ParseString(examResultID),
+			"examRepository::GetActivitiesResult::"+utils.ParseString(examResultID),
+		)
+	}
+
+	return r.cache.Delete(keys...)
+}
+
+func (r privacyRepository) examResultIDs(db *gorm.DB, userID int) *gorm.DB {
+	return db.
+		Table(TableName.ExamResult).
+		Select(IDName.ExamResult).
+		Where(IDName.User+" = ?", userID)
+}
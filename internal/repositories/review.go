@@ -0,0 +1,270 @@
+package repositories
+
+import (
+	"database-camp/internal/infrastructure/cache"
+	"database-camp/internal/infrastructure/database"
+	"database-camp/internal/models/entities/content"
+	"database-camp/internal/models/entities/exam"
+	"database-camp/internal/models/entities/user"
+	"database-camp/internal/utils"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ReviewRepository reads the answers learners submitted and stores the
+// grades they get when they are graded again.
+type ReviewRepository interface {
+	GetAttempts(activityID int, userID *int, limit int, offset int) ([]content.LearningProgression, error)
+	GetExamResult(examResultID int) (*exam.ExamResult, error)
+	GetSubmittedProgressions(activityID int) ([]content.LearningProgression, error)
+	GetSubmittedResultActivities(activityID int) ([]exam.ResultActivity, error)
+	RegradeProgressions(activityID int, progressions []content.LearningProgression) error
+	RegradeResultActivities(activityID int, resultActivities []exam.ResultActivity) error
+}
+
+type reviewRepository struct {
+	db    database.MysqlDB
+	cache cache.Cache
+}
+
+func NewReviewRepository(db database.MysqlDB, cache cache.Cache) *reviewRepository {
+	return &reviewRepository{db: db, cache: cache}
+}
+
+func (r reviewRepository) GetAttempts(activityID int, userID *int, limit int, offset int) ([]content.LearningProgression, error) {
+	progressions := make([]content.LearningProgression, 0)
+
+	query := r.db.GetDB().
+		Table(TableName.LearningProgression).
+		Where(IDName.Activity+" = ?", activityID)
+
+	if userID != nil {
+		query = query.Where(IDName.User+" = ?", *userID)
+	}
+
+	err := query.
+		Order("created_timestamp desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&progressions).
+		Error
+
+	return progressions, err
+}
+
+func (r reviewRepository) GetExamResult(examResultID int) (*exam.ExamResult, error) {
+	result := exam.ExamResult{}
+
+	err := r.db.GetDB().
+		Table(ViewName.ExamResultSummary).
+		Where(IDName.ExamResult+" = ?", examResultID).
+		Find(&result).
+		Error
+
+	return &result, err
+}
+
+func (r reviewRepository) GetSubmittedProgressions(activityID int) ([]content.LearningProgression, error) {
+	progressions := make([]content.LearningProgression, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.LearningProgression).
+		Where(IDName.Activity+" = ? AND answer_version IS NOT NULL", activityID).
+		Order("learning_progression_id").
+		Find(&progressions).
+		Error
+
+	return progressions, err
+}
+
+func (r reviewRepository) GetSubmittedResultActivities(activityID int) ([]exam.ResultActivity, error) {
+	resultActivities := make([]exam.ResultActivity, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.ExamResultActivity).
+		Where(IDName.Activity+" = ? AND answer_version IS NOT NULL", activityID).
+		Find(&resultActivities).
+		Error
+
+	return resultActivities, err
+}
+
+// RegradeProgressions stores the new grades of the answers to the activity.
+// A user whose best answer now earns more is awarded the difference, points
+// already awarded are never taken back. created_timestamp updates itself on
+// every update, so it is set to itself to keep when the answer was made.
+func (r reviewRepository) RegradeProgressions(activityID int, progressions []content.LearningProgression) error {
+	tx := r.db.GetDB().Begin()
+
+	bests, err := r.getBestPoints(tx, activityID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, progression := range progressions {
+		err = tx.Table(TableName.LearningProgression).
+			Where("learning_progression_id = ?", progression.ID).
+			Updates(map[string]interface{}{
+				"is_correct":        progression.IsCorrect,
+				"point":             progression.Point,
+				"created_timestamp": gorm.Expr("created_timestamp"),
+			}).
+			Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	regradedBests, err := r.getBestPoints(tx, activityID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	awarded := make([]int, 0)
+	for userID, best := range regradedBests {
+		if best <= bests[userID] {
+			continue
+		}
+
+		_, err = addPoint(tx, &user.PointTransaction{
+			UserID:      userID,
+			Amount:      best - bests[userID],
+			Reason:      user.POINT_REASON_REGRADE,
+			ReferenceID: &activityID,
+		})
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		awarded = append(awarded, userID)
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return err
+	}
+
+	userRepo := NewUserRepository(r.db, r.cache)
+	for _, userID := range awarded {
+		if err = userRepo.ClearRankingCache(userID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r reviewRepository) getBestPoints(tx *gorm.DB, activityID int) (map[int]int, error) {
+	rows := make([]struct {
+		UserID int `gorm:"column:user_id"`
+		Point  int `gorm:"column:point"`
+	}, 0)
+
+	err := tx.Table(TableName.LearningProgression).
+		Select(IDName.User+" AS user_id", "MAX(point) AS point").
+		Where(IDName.Activity+" = ?", activityID).
+		Group(IDName.User).
+		Find(&rows).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	bests := map[int]int{}
+	for _, row := range rows {
+		bests[row.UserID] = row.Point
+	}
+
+	return bests, nil
+}
+
+// RegradeResultActivities stores the new scores of the answers to the
+// activity and whether the exam results they belong to are now passed, an
+// exam result is only updated when its outcome changes so it keeps its date
+func (r reviewRepository) RegradeResultActivities(activityID int, resultActivities []exam.ResultActivity) error {
+	tx := r.db.GetDB().Begin()
+
+	keys := make([]string, 0)
+
+	for _, resultActivity := range resultActivities {
+		err := tx.Table(TableName.ExamResultActivity).
+			Where(IDName.ExamResult+" = ? AND "+IDName.Activity+" = ?", resultActivity.ExamResultID, activityID).
+			Update("score", resultActivity.Score).
+			Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		totals := struct {
+			UserID     int  `gorm:"column:user_id"`
+			IsPassed   bool `gorm:"column:is_passed"`
+			Score      int  `gorm:"column:score"`
+			TotalScore int  `gorm:"column:total_score"`
+		}{}
+
+		err = tx.Table(TableName.ExamResultActivity).
+			Select(
+				TableName.ExamResult+".user_id AS user_id",
+				TableName.ExamResult+".is_passed AS is_passed",
+				fmt.Sprintf("SUM(%s.score) AS score", TableName.ExamResultActivity),
+				fmt.Sprintf("SUM(%s.point) AS total_score", TableName.Activity),
+			).
+			Joins(fmt.Sprintf("JOIN %s ON %s.%s = %s.%s",
+				TableName.ExamResult,
+				TableName.ExamResult,
+				IDName.ExamResult,
+				TableName.ExamResultActivity,
+				IDName.ExamResult,
+			)).
+			Joins(fmt.Sprintf("JOIN %s ON %s.%s = %s.%s",
+				TableName.Activity,
+				TableName.Activity,
+				IDName.Activity,
+				TableName.ExamResultActivity,
+				IDName.Activity,
+			)).
+			Where(TableName.ExamResultActivity+"."+IDName.ExamResult+" = ?", resultActivity.ExamResultID).
+			Group(TableName.ExamResult + "." + IDName.ExamResult).
+			Take(&totals).
+			Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+
+		if isPassed := exam.IsPassed(totals.Score, totals.TotalScore); isPassed != totals.IsPassed {
+			err = tx.Table(TableName.ExamResult).
+				Where(IDName.ExamResult+" = ?", resultActivity.ExamResultID).
+				Updates(map[string]interface{}{
+					"is_passed":         isPassed,
+					"created_timestamp": gorm.Expr("created_timestamp"),
+				}).
+				Error
+			if err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+
+		keys = append(keys,
+			"examRepository::GetExamResults::"+utils.ParseString(totals.UserID),
+			"examRepository::GetExamResult::"+utils.ParseString(totals.UserID)+"::"+utils.ParseString(resultActivity.ExamResultID),
+			"examRepository::GetActivitiesResult::"+utils.ParseString(resultActivity.ExamResultID),
+		)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	// Cleared after the commit, a read in between would cache the old rows
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return r.cache.Delete(keys...)
+}
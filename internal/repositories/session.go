@@ -0,0 +1,185 @@
+package repositories
+
+import (
+	"database-camp/internal/infrastructure/cache"
+	"database-camp/internal/infrastructure/database"
+	"database-camp/internal/models/entities/user"
+	"database-camp/internal/utils"
+	"encoding/json"
+	"time"
+)
+
+// SessionRepository keeps the device record of a session in MySQL and its
+// liveness in the cache, so verifying a token never has to reach the database.
+type SessionRepository interface {
+	GetSession(id int) (*user.Session, error)
+	GetUserSessions(userID int) ([]user.Session, error)
+	InsertSession(session user.Session) (*user.Session, error)
+	DeleteSession(userID int, id int) error
+	DeleteInactiveSessions(userID int) error
+	DeleteUserSessions(userID int) error
+	ActivateSession(userID int, id int, expiration time.Duration) error
+	TouchSession(userID int, id int, expiration time.Duration) (bool, error)
+	IsSessionActive(userID int, id int) bool
+	SetRefreshToken(refreshToken string, ref user.SessionRef, expiration time.Duration) error
+	PopRefreshToken(refreshToken string) (*user.SessionRef, error)
+}
+
+type sessionRepository struct {
+	db    database.MysqlDB
+	cache cache.Cache
+}
+
+func NewSessionRepository(db database.MysqlDB, cache cache.Cache) *sessionRepository {
+	return &sessionRepository{db: db, cache: cache}
+}
+
+func (r sessionRepository) sessionKey(userID int, id int) string {
+	return "sessionRepository::Session::" + utils.ParseString(userID) + "::" + utils.ParseString(id)
+}
+
+func (r sessionRepository) refreshTokenKey(refreshToken string) string {
+	return "sessionRepository::RefreshToken::" + refreshToken
+}
+
+func (r sessionRepository) GetSession(id int) (*user.Session, error) {
+	session := user.Session{}
+
+	err := r.db.GetDB().
+		Table(TableName.Session).
+		Where(IDName.Session+" = ?", id).
+		Find(&session).
+		Error
+
+	return &session, err
+}
+
+func (r sessionRepository) GetUserSessions(userID int) ([]user.Session, error) {
+	sessions := make([]user.Session, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.Session).
+		Where(IDName.User+" = ?", userID).
+		Order("created_timestamp DESC").
+		Find(&sessions).
+		Error
+
+	return sessions, err
+}
+
+func (r sessionRepository) InsertSession(session user.Session) (*user.Session, error) {
+	err := r.db.GetDB().
+		Table(TableName.Session).
+		Create(&session).
+		Error
+	return &session, err
+}
+
+func (r sessionRepository) DeleteSession(userID int, id int) error {
+	err := r.cache.Delete(r.sessionKey(userID, id))
+	if err != nil {
+		return err
+	}
+
+	err = r.db.GetDB().
+		Table(TableName.Session).
+		Where(IDName.Session+" = ?", id).
+		Where(IDName.User+" = ?", userID).
+		Delete(&user.Session{}).
+		Error
+
+	return err
+}
+
+func (r sessionRepository) DeleteInactiveSessions(userID int) error {
+	sessions, err := r.GetUserSessions(userID)
+	if err != nil {
+		return err
+	}
+
+	inactiveIDs := make([]int, 0)
+	for _, session := range sessions {
+		if !r.IsSessionActive(userID, session.ID) {
+			inactiveIDs = append(inactiveIDs, session.ID)
+		}
+	}
+
+	if len(inactiveIDs) == 0 {
+		return nil
+	}
+
+	err = r.db.GetDB().
+		Table(TableName.Session).
+		Where(IDName.Session+" IN ?", inactiveIDs).
+		Delete(&user.Session{}).
+		Error
+
+	return err
+}
+
+func (r sessionRepository) DeleteUserSessions(userID int) error {
+	sessions, err := r.GetUserSessions(userID)
+	if err != nil {
+		return err
+	}
+
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		keys = append(keys, r.sessionKey(userID, session.ID))
+	}
+
+	err = r.cache.Delete(keys...)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.GetDB().
+		Table(TableName.Session).
+		Where(IDName.User+" = ?", userID).
+		Delete(&user.Session{}).
+		Error
+
+	return err
+}
+
+func (r sessionRepository) ActivateSession(userID int, id int, expiration time.Duration) error {
+	return r.cache.Set(r.sessionKey(userID, id), time.Now().Local().Unix(), expiration)
+}
+
+func (r sessionRepository) TouchSession(userID int, id int, expiration time.Duration) (bool, error) {
+	return r.cache.Expire(r.sessionKey(userID, id), expiration)
+}
+
+func (r sessionRepository) IsSessionActive(userID int, id int) bool {
+	_, err := r.cache.Get(r.sessionKey(userID, id))
+	return err == nil
+}
+
+func (r sessionRepository) SetRefreshToken(refreshToken string, ref user.SessionRef, expiration time.Duration) error {
+	data, err := json.Marshal(ref)
+	if err != nil {
+		return err
+	}
+
+	return r.cache.Set(r.refreshTokenKey(refreshToken), string(data), expiration)
+}
+
+func (r sessionRepository) PopRefreshToken(refreshToken string) (*user.SessionRef, error) {
+	ref := user.SessionRef{}
+
+	cacheData, err := r.cache.GetDel(r.refreshTokenKey(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal([]byte(cacheData), &ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ref, nil
+}
@@ -26,13 +26,24 @@ type UserRepository interface {
 	GetRankingLeaderBoard() ([]user.Ranking, error)
 	GetUserHint(userID int, activityID int) ([]activity.UserHint, error)
 	GetPreExamID(userID int) (*int, error)
-	GetPreTestResults(userID int) (user.PreTestResults, error)
 	GetSpiderDataset(userID int) (dataset user.SpiderDataset, err error)
+	GetUserRoles(userID int) ([]string, error)
+	GetIdentity(provider string, subject string) (*user.Identity, error)
 	InsertUser(user user.User) (*user.User, error)
+	InsertIdentity(identity user.Identity) (*user.Identity, error)
 	InsertUserHint(userHint activity.UserHint) (*activity.UserHint, error)
 	InsertBadge(userBadge badge.UserBadge) (*badge.UserBadge, error)
-	InsertLearningProgression(userID int, activityID int, point int, isCorrect bool, hasProgression bool) error
+	InsertLearningProgression(progression content.LearningProgression, reason string) error
 	UpdatesByID(id int, updateData map[string]interface{}) error
+	SetUserRoles(userID int, roles []string) error
+	SetOIDCState(state string, oidcState user.OIDCState, expiration time.Duration) error
+	PopOIDCState(state string) (*user.OIDCState, error)
+	SetPasswordResetToken(resetToken string, userID int, expiration time.Duration) error
+	PopPasswordResetToken(resetToken string) (int, error)
+	VerifyEmail(userID int, email string) (bool, error)
+	AllowEmailVerificationResend(userID int, interval time.Duration) (bool, error)
+	SetDeletionTimestamp(userID int, deletionTimestamp *time.Time) error
+	ClearRankingCache(userID int) error
 }
 
 type userRepository struct {
@@ -111,7 +122,7 @@ func (r userRepository) GetAllBadge() ([]badge.Badge, error) {
 	if data, err := json.Marshal(badge); err != nil {
 		return nil, err
 	} else {
-		if err = r.cache.Set(key, string(data), time.Minute*10); err != nil {
+		if err = r.cache.Set(key, string(data), time.Second*10); err != nil {
 			return nil, err
 		}
 	}
@@ -173,7 +184,7 @@ func (r userRepository) GetPointRanking(id int) (*user.Ranking, error) {
 	if data, err := json.Marshal(ranking); err != nil {
 		return nil, err
 	} else {
-		if err = r.cache.Set(key, string(data), time.Minute*10); err != nil {
+		if err = r.cache.Set(key, string(data), time.Second*10); err != nil {
 			return nil, err
 		}
 	}
@@ -201,7 +212,7 @@ func (r userRepository) GetRankingLeaderBoard() ([]user.Ranking, error) {
 	if data, err := json.Marshal(ranking); err != nil {
 		return nil, err
 	} else {
-		if err = r.cache.Set(key, string(data), time.Minute*10); err != nil {
+		if err = r.cache.Set(key, string(data), time.Second*10); err != nil {
 			return nil, err
 		}
 	}
@@ -237,12 +248,27 @@ func (r userRepository) GetSpiderDataset(userID int) (dataset user.SpiderDataset
 	return
 }
 
-func (r userRepository) InsertUser(user user.User) (*user.User, error) {
-	err := r.db.GetDB().
-		Table(TableName.User).
-		Create(&user).
-		Error
-	return &user, err
+// InsertUser creates the user as a student, other roles are granted by an admin.
+func (r userRepository) InsertUser(_user user.User) (*user.User, error) {
+	tx := r.db.GetDB().Begin()
+
+	err := tx.Table(TableName.User).Create(&_user).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	userRole := user.UserRole{UserID: _user.ID, Role: string(user.STUDENT)}
+
+	err = tx.Table(TableName.UserRole).Create(&userRole).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit().Error
+
+	return &_user, err
 }
 
 func (r userRepository) InsertUserHint(userHint activity.UserHint) (*activity.UserHint, error) {
@@ -267,46 +293,63 @@ func (r userRepository) InsertBadge(userBadge badge.UserBadge) (*badge.UserBadge
 
 }
 
-func (r userRepository) InsertLearningProgression(userID int, activityID int, point int, isCorrect bool, hasProgression bool) error {
+// InsertLearningProgression records an answer with the point it earned and
+// awards the user what it earned over the best earlier answer to the
+// activity, the reward is recorded with the reason
+func (r userRepository) InsertLearningProgression(progression content.LearningProgression, reason string) error {
 	tx := r.db.GetDB().Begin()
 
-	progression := content.LearningProgression{
-		UserID:           userID,
-		ActivityID:       activityID,
-		IsCorrect:        isCorrect,
-		CreatedTimestamp: time.Now().Local(),
+	// Locking the user keeps two answers of the same user from both being
+	// awarded over the same best answer
+	var current int
+	err := tx.Table(TableName.User).
+		Clauses(lockForUpdate).
+		Select("point").
+		Where(IDName.User+" = ?", progression.UserID).
+		Take(&current).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return err
 	}
 
-	err := tx.Table(TableName.LearningProgression).Create(&progression).Error
+	var best int
+	err = tx.Table(TableName.LearningProgression).
+		Select("COALESCE(MAX(point), 0)").
+		Where(IDName.User+" = ? AND "+IDName.Activity+" = ?", progression.UserID, progression.ActivityID).
+		Take(&best).
+		Error
 	if err != nil {
 		tx.Rollback()
 		return err
 	}
 
-	logs.GetInstance().Info(hasProgression)
-
-	if !hasProgression && isCorrect {
-		logs.GetInstance().Info("update point")
+	progression.CreatedTimestamp = time.Now().Local()
 
-		statement := fmt.Sprintf("UPDATE %s SET point = point + %d WHERE %s = %d",
-			TableName.User,
-			point,
-			IDName.User,
-			userID,
-		)
-		temp := map[string]interface{}{}
-		err = tx.Debug().Raw(statement).Find(&temp).Error
+	err = tx.Table(TableName.LearningProgression).Create(&progression).Error
+	if err != nil {
+		tx.Rollback()
+		return err
 	}
 
-	logs.GetInstance().Info("not update point")
+	if progression.Point > best {
+		_, err = addPoint(tx, &user.PointTransaction{
+			UserID:      progression.UserID,
+			Amount:      progression.Point - best,
+			Reason:      reason,
+			ReferenceID: &progression.ActivityID,
+		})
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
 
-	if err != nil {
-		tx.Rollback()
+	if err = tx.Commit().Error; err != nil {
 		return err
 	}
 
-	tx.Commit()
-	return nil
+	return r.ClearRankingCache(progression.UserID)
 }
 
 func (r userRepository) UpdatesByID(id int, updateData map[string]interface{}) error {
@@ -335,16 +378,188 @@ func (r userRepository) GetPreExamID(userID int) (*int, error) {
 	return &data.ExamID, err
 }
 
-func (r userRepository) GetPreTestResults(userID int) (user.PreTestResults, error) {
-	var results user.PreTestResults
+// SetPasswordResetToken replaces any reset token the user requested before, so
+// only the latest mail can be used.
+func (r userRepository) SetPasswordResetToken(resetToken string, userID int, expiration time.Duration) error {
+	userKey := "userRepository::PasswordResetUser::" + utils.ParseString(userID)
+
+	if oldToken, err := r.cache.GetDel(userKey); err == nil {
+		if err = r.cache.Delete("userRepository::PasswordResetToken::" + oldToken); err != nil {
+			return err
+		}
+	}
+
+	err := r.cache.Set("userRepository::PasswordResetToken::"+resetToken, userID, expiration)
+	if err != nil {
+		return err
+	}
+
+	return r.cache.Set(userKey, resetToken, expiration)
+}
+
+// PopPasswordResetToken consumes the token, the token is used up once it is
+// read so a failure to clear the link to the user does not reject it
+func (r userRepository) PopPasswordResetToken(resetToken string) (int, error) {
+	cacheData, err := r.cache.GetDel("userRepository::PasswordResetToken::" + resetToken)
+	if err != nil {
+		return 0, err
+	}
+
+	userID := utils.ParseInt(cacheData)
+
+	err = r.cache.Delete("userRepository::PasswordResetUser::" + utils.ParseString(userID))
+	if err != nil {
+		logs.GetInstance().Error(err)
+	}
+
+	return userID, nil
+}
+
+// VerifyEmail only matches the email the link was sent to, so a link sent
+// before the email was changed cannot verify the new one.
+func (r userRepository) VerifyEmail(userID int, email string) (bool, error) {
+	result := r.db.GetDB().
+		Table(TableName.User).
+		Where(IDName.User+" = ?", userID).
+		Where("email = ?", email).
+		Where("verified_timestamp IS NULL").
+		Updates(map[string]interface{}{
+			"verified_timestamp": time.Now().Local(),
+			"updated_timestamp":  time.Now().Local(),
+		})
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	err := r.ClearRankingCache(userID)
+
+	return true, err
+}
 
+// SetDeletionTimestamp schedules the user for deletion, nil cancels it. The user
+// leaves the ranking while scheduled.
+func (r userRepository) SetDeletionTimestamp(userID int, deletionTimestamp *time.Time) error {
 	err := r.db.GetDB().
-		Table(ViewName.UserPreTestResult).
-		Where(IDName.User+"=?", userID).
-		Order("created_timestamp ASC").
-		Limit(1).
-		Find(&results).
+		Table(TableName.User).
+		Where(IDName.User+" = ?", userID).
+		Updates(map[string]interface{}{
+			"deletion_timestamp": deletionTimestamp,
+			"updated_timestamp":  time.Now().Local(),
+		}).
+		Error
+	if err != nil {
+		return err
+	}
+
+	return r.ClearRankingCache(userID)
+}
+
+// ClearRankingCache drops the cached ranking after a change to who appears in
+// it or how they appear.
+func (r userRepository) ClearRankingCache(userID int) error {
+	return r.cache.Delete(
+		"userRepository::GetRankingLeaderBoard",
+		"userRepository::GetPointRanking::"+utils.ParseString(userID),
+	)
+}
+
+// AllowEmailVerificationResend reports whether a verification mail may be sent
+// now and, if so, blocks further mails for the interval.
+func (r userRepository) AllowEmailVerificationResend(userID int, interval time.Duration) (bool, error) {
+	key := "userRepository::EmailVerificationResend::" + utils.ParseString(userID)
+	return r.cache.SetNX(key, time.Now().Local().Unix(), interval)
+}
+
+func (r userRepository) GetUserRoles(userID int) ([]string, error) {
+	roles := make([]string, 0)
+
+	err := r.db.GetDB().
+		Table(TableName.UserRole).
+		Select("role").
+		Where(IDName.User+" = ?", userID).
+		Order("role").
+		Find(&roles).
+		Error
+
+	return roles, err
+}
+
+func (r userRepository) SetUserRoles(userID int, roles []string) error {
+	tx := r.db.GetDB().Begin()
+
+	err := tx.Table(TableName.UserRole).
+		Where(IDName.User+" = ?", userID).
+		Delete(&user.UserRole{}).
+		Error
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if len(roles) > 0 {
+		userRoles := make([]user.UserRole, 0, len(roles))
+		for _, role := range roles {
+			userRoles = append(userRoles, user.UserRole{UserID: userID, Role: role})
+		}
+
+		err = tx.Table(TableName.UserRole).Create(&userRoles).Error
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+func (r userRepository) GetIdentity(provider string, subject string) (*user.Identity, error) {
+	identity := user.Identity{}
+
+	err := r.db.GetDB().
+		Table(TableName.UserIdentity).
+		Where("provider = ?", provider).
+		Where("subject = ?", subject).
+		Find(&identity).
 		Error
 
-	return results, err
+	return &identity, err
+}
+
+func (r userRepository) InsertIdentity(identity user.Identity) (*user.Identity, error) {
+	err := r.db.GetDB().
+		Table(TableName.UserIdentity).
+		Create(&identity).
+		Error
+	return &identity, err
+}
+
+func (r userRepository) SetOIDCState(state string, oidcState user.OIDCState, expiration time.Duration) error {
+	data, err := json.Marshal(oidcState)
+	if err != nil {
+		return err
+	}
+
+	return r.cache.Set("userRepository::OIDCState::"+state, string(data), expiration)
+}
+
+// PopOIDCState removes the state as it is read, so a callback cannot be replayed.
+func (r userRepository) PopOIDCState(state string) (*user.OIDCState, error) {
+	oidcState := user.OIDCState{}
+
+	cacheData, err := r.cache.GetDel("userRepository::OIDCState::" + state)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal([]byte(cacheData), &oidcState)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oidcState, nil
 }
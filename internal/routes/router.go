@@ -2,6 +2,8 @@ package routes
 
 import (
 	"database-camp/internal/infrastructure/application"
+	"database-camp/internal/models/entities/user"
+	"database-camp/internal/models/request"
 	"database-camp/internal/registry"
 	"net/http"
 )
@@ -36,9 +38,13 @@ func (r *router) setup() {
 	r.setupUser()
 	r.setupLearning()
 	r.setupExam()
+	r.setupReview()
+	r.setupAdmin()
 }
 
 func (r *router) setupProbe() {
+	r.app.Get("/.well-known/jwks.json", r.regis.GetMiddlewares().Jwt.JWKS)
+
 	r.route.Get("/x", func(c application.Context) {
 		c.JSON(http.StatusOK, map[string]string{
 			"build_commit": BuildCommit,
@@ -56,23 +62,43 @@ func (r *router) setupProbe() {
 func (r *router) setupUser() {
 	jwt := r.regis.GetMiddlewares().Jwt
 	handler := r.regis.GetHandlers().UserHandler
+	privacyHandler := r.regis.GetHandlers().PrivacyHandler
+	pointHandler := r.regis.GetHandlers().PointHandler
 	userRoute := r.route.Group("user")
 	{
 		userRoute.Post("/register", handler.Register)
 		userRoute.Post("/login", handler.Login)
+		userRoute.Post("/login/unlock", handler.UnlockLogin)
+		userRoute.Post("/token/refresh", handler.RefreshToken)
+		userRoute.Post("/password/reset/request", handler.RequestPasswordReset)
+		userRoute.Post("/password/reset/confirm", handler.ConfirmPasswordReset)
+		userRoute.Post("/email/verify", handler.VerifyEmail)
+		userRoute.Get("/oidc/:provider/login", handler.OIDCLogin)
+		userRoute.Post("/oidc/:provider/callback", handler.OIDCCallback)
+		userRoute.Get("/profile/:id", jwt.VerifyOptional, handler.GetProfile)
 	}
 
 	{
 		userRoute.Get("/info", jwt.Verify, handler.GetOwnProfile)
-		userRoute.Get("/profile/:id", jwt.Verify, handler.GetProfile)
 		userRoute.Get("/ranking", jwt.Verify, handler.GetUserRanking)
 		userRoute.Put("/profile", jwt.Verify, handler.Edit)
+		userRoute.Put("/profile/avatar", jwt.Verify, handler.UploadAvatar)
+		userRoute.Delete("/profile/avatar", jwt.Verify, handler.DeleteAvatar)
+		userRoute.Put("/password", jwt.Verify, handler.ChangePassword)
+		userRoute.Post("/logout", jwt.Verify, handler.Logout)
+		userRoute.Get("/sessions", jwt.Verify, handler.GetSessions)
+		userRoute.Delete("/sessions/:id", jwt.Verify, handler.RevokeSession)
+		userRoute.Post("/email/verify/resend", jwt.Verify, handler.ResendEmailVerification)
+		userRoute.Post("/account/delete", jwt.Verify, handler.DeleteAccount)
+		userRoute.Get("/data/export", jwt.Verify, privacyHandler.ExportData)
+		userRoute.Get("/points/history", jwt.Verify, pointHandler.GetOwnHistory)
 	}
 }
 
 func (r *router) setupLearning() {
 	jwt := r.regis.GetMiddlewares().Jwt
 	handler := r.regis.GetHandlers().LearningHandler
+	reviewHandler := r.regis.GetHandlers().ReviewHandler
 	learningRoute := r.route.Group("learning", jwt.Verify)
 	activityRoute := learningRoute.Group("activity")
 	{
@@ -80,11 +106,15 @@ func (r *router) setupLearning() {
 		learningRoute.Get("/overview", handler.GetOverview)
 		learningRoute.Get("/content/roadmap/:id", handler.GetContentRoadmap)
 		learningRoute.Get("/recommend", handler.GetRecommend)
+		learningRoute.Get("/review", handler.GetReviews)
+		learningRoute.Get("/content/:id/attachments", handler.GetAttachments)
+		learningRoute.Get("/attachment/:id/download", handler.DownloadAttachment)
 
 	}
 
 	{
 		activityRoute.Get("/:id", handler.GetActivity)
+		activityRoute.Get("/:id/attempts", reviewHandler.GetOwnAttempts)
 		activityRoute.Post("/hint/:id", handler.UseHint)
 		activityRoute.Post("/check-answer", handler.CheckAnswer)
 		activityRoute.Post("/peer", handler.PeerReview)
@@ -102,3 +132,58 @@ func (r *router) setupExam() {
 		examRoute.Post("/check", handler.CheckExam)
 	}
 }
+
+func (r *router) setupReview() {
+	jwt := r.regis.GetMiddlewares().Jwt
+	handler := r.regis.GetHandlers().ReviewHandler
+	reviewRoute := r.route.Group("review", jwt.Verify, jwt.RequireRole(user.INSTRUCTOR, user.ADMIN))
+	{
+		reviewRoute.Get("/activities/:id/attempts", handler.GetAttempts)
+		reviewRoute.Get("/exam/result/:id", handler.GetExamResult)
+	}
+}
+
+func (r *router) setupAdmin() {
+	jwt := r.regis.GetMiddlewares().Jwt
+	userHandler := r.regis.GetHandlers().UserHandler
+	contentHandler := r.regis.GetHandlers().ContentAdminHandler
+	reviewHandler := r.regis.GetHandlers().ReviewHandler
+	pointHandler := r.regis.GetHandlers().PointHandler
+	adminRoute := r.route.Group("admin", jwt.Verify, jwt.RequireRole(user.ADMIN))
+	{
+		adminRoute.Get("/user/:id/roles", userHandler.GetUserRoles)
+		adminRoute.Put("/user/:id/roles", userHandler.SetUserRoles)
+		adminRoute.Get("/user/:id/points", pointHandler.GetHistory)
+		adminRoute.Post("/user/:id/points", pointHandler.AdjustPoint)
+	}
+
+	{
+		adminRoute.Get("/content-groups", contentHandler.GetContentGroups)
+		adminRoute.Post("/content-groups", contentHandler.CreateContentGroup)
+		adminRoute.Put("/content-groups/:id", contentHandler.UpdateContentGroup)
+		adminRoute.Delete("/content-groups/:id", contentHandler.DeleteContentGroup)
+		adminRoute.Get("/content-groups/:id/contents", contentHandler.GetContents)
+		adminRoute.Post("/contents", contentHandler.CreateContent)
+		adminRoute.Put("/contents/:id", contentHandler.UpdateContent)
+		adminRoute.Delete("/contents/:id", contentHandler.DeleteContent)
+		adminRoute.Get("/contents/:id/activities", contentHandler.GetActivities)
+		adminRoute.Get("/activities/:id", contentHandler.GetActivity)
+		adminRoute.Post("/activities", contentHandler.CreateActivity)
+		adminRoute.Put("/activities/:id", contentHandler.UpdateActivity)
+		adminRoute.Delete("/activities/:id", contentHandler.DeleteActivity)
+		adminRoute.Post("/hints", contentHandler.CreateHint)
+		adminRoute.Put("/hints/:id", contentHandler.UpdateHint)
+		adminRoute.Delete("/hints/:id", contentHandler.DeleteHint)
+		adminRoute.Get("/prerequisites", contentHandler.GetPrerequisites)
+		adminRoute.Post("/prerequisites", contentHandler.CreatePrerequisite)
+		adminRoute.Delete("/prerequisites/:id", contentHandler.DeletePrerequisite)
+		adminRoute.Get("/contents/:id/attachments", contentHandler.GetAttachmentStatistics)
+		adminRoute.Post("/contents/:id/attachments", application.BodyLimit(request.MAX_ATTACHMENT_FORM_BYTES), contentHandler.CreateAttachment)
+		adminRoute.Delete("/attachments/:id", contentHandler.DeleteAttachment)
+	}
+
+	{
+		adminRoute.Post("/activities/:id/regrade", reviewHandler.StartRegrade)
+		adminRoute.Get("/regrade-jobs/:id", reviewHandler.GetRegradeJob)
+	}
+}
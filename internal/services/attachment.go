@@ -0,0 +1,79 @@
+package services
+
+import (
+	"database-camp/internal/errs"
+	"database-camp/internal/logs"
+	"database-camp/internal/models/entities/content"
+	"database-camp/internal/models/response"
+)
+
+// GetAttachments lists the files of a content, a locked content has none to
+// show until it is unlocked.
+func (s learningService) GetAttachments(userID int, contentID int) (*response.AttachmentListResponse, error) {
+	contentDB, err := s.learningRepo.GetContent(contentID)
+	if err != nil || contentDB.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrContentNotFound
+	}
+
+	err = s.checkContentUnlocked(userID, contentID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.learningRepo.GetContentAttachments(contentID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	response := response.AttachmentListResponse{
+		ContentID:   contentID,
+		Attachments: attachments,
+	}
+
+	return &response, nil
+}
+
+/**
+ * Sign a download link of an attachment and record the download, a failed
+ * record does not keep the user from the file
+ *
+ * @param userID 		user who downloads
+ * @param attachmentID 	attachment to download
+ *
+ * @return the attachment with its link
+ */
+func (s learningService) GetAttachmentLink(userID int, attachmentID int) (*response.AttachmentLinkResponse, error) {
+	attachment, err := s.learningRepo.GetAttachment(attachmentID)
+	if err != nil || attachment.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrAttachmentNotFound
+	}
+
+	err = s.checkContentUnlocked(userID, attachment.ContentID)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.attachmentRepo.GetAttachmentLink(attachment.ObjectName, attachment.FileName)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrServiceUnavailableError
+	}
+
+	err = s.learningRepo.InsertAttachmentDownload(&content.AttachmentDownload{
+		AttachmentID: attachment.ID,
+		UserID:       userID,
+	})
+	if err != nil {
+		logs.GetInstance().Error(err)
+	}
+
+	response := response.AttachmentLinkResponse{
+		Attachment:   *attachment,
+		DownloadLink: link,
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"database-camp/internal/errs"
+	"database-camp/internal/logs"
+	"database-camp/internal/models/entities/user"
+	"database-camp/internal/models/request"
+	"database-camp/internal/models/response"
+	"database-camp/internal/utils"
+	"fmt"
+	"time"
+)
+
+// Uploads are decoded only when neither side is above this many pixels
+const defaultAvatarMaxPixel = 4096
+
+// UploadAvatar stores the picture in every avatar size under a new name, so
+// links to the previous avatar cached by clients stop resolving once it is
+// deleted.
+func (s userService) UploadAvatar(userID int, request request.AvatarRequest) (*response.AvatarResponse, error) {
+	_user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || _user.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUserNotFound
+	}
+
+	maxPixel := envInt("AVATAR_MAX_PIXEL", defaultAvatarMaxPixel)
+
+	img, err := utils.DecodeImage(request.Data, maxPixel)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrAvatarInvalid
+	}
+
+	token, err := utils.RandomToken()
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrInternalServerError
+	}
+
+	avatar := fmt.Sprintf("avatar/%d/%s", userID, token[:16])
+
+	for _, size := range user.AvatarSizes {
+		data, err := utils.EncodeJPEG(utils.ResizeSquare(img, size.Pixel))
+		if err != nil {
+			logs.GetInstance().Error(err)
+			return nil, errs.ErrInternalServerError
+		}
+
+		err = s.avatarRepo.UploadAvatar(user.AvatarObjectName(avatar, size), data)
+		if err != nil {
+			logs.GetInstance().Error(err)
+			return nil, errs.ErrServiceUnavailableError
+		}
+	}
+
+	err = s.userRepo.UpdatesByID(userID, map[string]interface{}{
+		"avatar":            avatar,
+		"updated_timestamp": time.Now().Local(),
+	})
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUpdateError
+	}
+
+	s.deleteAvatarObjects(_user.Avatar)
+	s.clearRankingCache(userID)
+
+	response := s.avatarLinks(avatar)
+
+	return &response, nil
+}
+
+func (s userService) DeleteAvatar(userID int) (*response.MessageResponse, error) {
+	_user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || _user.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUserNotFound
+	}
+
+	if _user.Avatar != "" {
+		err = s.userRepo.UpdatesByID(userID, map[string]interface{}{
+			"avatar":            "",
+			"updated_timestamp": time.Now().Local(),
+		})
+		if err != nil {
+			logs.GetInstance().Error(err)
+			return nil, errs.ErrUpdateError
+		}
+
+		s.deleteAvatarObjects(_user.Avatar)
+		s.clearRankingCache(userID)
+	}
+
+	response := response.MessageResponse{
+		ThMessage: response.AVATAR_DELETED_TH,
+		EnMessage: response.AVATAR_DELETED_EN,
+	}
+
+	return &response, nil
+}
+
+func (s userService) deleteAvatarObjects(avatar string) {
+	if avatar == "" {
+		return
+	}
+
+	err := s.avatarRepo.DeleteAvatar(user.AvatarObjectNames(avatar)...)
+	if err != nil {
+		logs.GetInstance().Error(err)
+	}
+}
+
+// clearRankingCache makes the leaderboard show a profile change right away.
+func (s userService) clearRankingCache(userID int) {
+	err := s.userRepo.ClearRankingCache(userID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+	}
+}
+
+// avatarLinks returns nil when the user has no avatar or the links cannot be
+// signed, a missing picture should not fail the whole page.
+func (s userService) avatarLinks(avatar string) response.AvatarResponse {
+	if avatar == "" {
+		return nil
+	}
+
+	links := response.AvatarResponse{}
+
+	for _, size := range user.AvatarSizes {
+		link, err := s.avatarRepo.GetAvatarLink(user.AvatarObjectName(avatar, size))
+		if err != nil {
+			logs.GetInstance().Error(err)
+			return nil
+		}
+		links[size.Name] = link
+	}
+
+	return links
+}
+
+func (s userService) rankingUser(ranking user.Ranking) response.RankingUserResponse {
+	return response.RankingUserResponse{
+		ID:      ranking.ID,
+		Name:    ranking.Name,
+		Point:   ranking.Point,
+		Avatar:  s.avatarLinks(ranking.Avatar),
+		Ranking: ranking.Ranking,
+	}
+}
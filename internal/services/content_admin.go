@@ -0,0 +1,740 @@
+package services
+
+import (
+	"database-camp/internal/errs"
+	"database-camp/internal/logs"
+	"database-camp/internal/models/entities/activity"
+	"database-camp/internal/models/entities/content"
+	"database-camp/internal/models/request"
+	"database-camp/internal/models/response"
+	"database-camp/internal/repositories"
+	"database-camp/internal/utils"
+	"fmt"
+	"path"
+	"strings"
+)
+
+type ContentAdminService interface {
+	GetContentGroups() (*response.ContentGroupListResponse, error)
+	CreateContentGroup(request request.ContentGroupRequest) (*content.ContentGroup, error)
+	UpdateContentGroup(id int, request request.ContentGroupRequest) (*content.ContentGroup, error)
+	DeleteContentGroup(id int) (*response.MessageResponse, error)
+	GetContents(groupID int) (*response.ContentListResponse, error)
+	CreateContent(request request.ContentRequest) (*content.Content, error)
+	UpdateContent(id int, request request.ContentRequest) (*content.Content, error)
+	DeleteContent(id int) (*response.MessageResponse, error)
+	GetActivities(contentID int) (*response.ActivityListResponse, error)
+	GetActivity(id int) (*response.AuthoredActivityResponse, error)
+	CreateActivity(request request.ActivityRequest) (*response.AuthoredActivityResponse, error)
+	UpdateActivity(id int, request request.ActivityRequest) (*response.AuthoredActivityResponse, error)
+	DeleteActivity(id int) (*response.MessageResponse, error)
+	CreateHint(request request.HintRequest) (*activity.Hint, error)
+	UpdateHint(id int, request request.HintRequest) (*activity.Hint, error)
+	DeleteHint(id int) (*response.MessageResponse, error)
+	GetPrerequisites() (*response.PrerequisiteListResponse, error)
+	CreatePrerequisite(request request.PrerequisiteRequest) (*content.Prerequisite, error)
+	DeletePrerequisite(id int) (*response.MessageResponse, error)
+	GetAttachmentStatistics(contentID int) (*response.AttachmentStatisticResponse, error)
+	CreateAttachment(contentID int, request request.AttachmentRequest) (*content.Attachment, error)
+	DeleteAttachment(id int) (*response.MessageResponse, error)
+}
+
+type contentAdminService struct {
+	contentAdminRepo repositories.ContentAdminRepository
+	learningRepo     repositories.LearningRepository
+	attachmentRepo   repositories.AttachmentRepository
+}
+
+func NewContentAdminService(
+	contentAdminRepo repositories.ContentAdminRepository,
+	learningRepo repositories.LearningRepository,
+	attachmentRepo repositories.AttachmentRepository,
+) *contentAdminService {
+	return &contentAdminService{
+		contentAdminRepo: contentAdminRepo,
+		learningRepo:     learningRepo,
+		attachmentRepo:   attachmentRepo,
+	}
+}
+
+func (s contentAdminService) GetContentGroups() (*response.ContentGroupListResponse, error) {
+	groups, err := s.contentAdminRepo.GetContentGroups()
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	response := response.ContentGroupListResponse{ContentGroups: groups}
+
+	return &response, nil
+}
+
+func (s contentAdminService) CreateContentGroup(request request.ContentGroupRequest) (*content.ContentGroup, error) {
+	err := s.checkContentGroupLinks(*request.BadgeID, *request.MiniExamID)
+	if err != nil {
+		return nil, err
+	}
+
+	group := content.ContentGroup{
+		Name:       strings.TrimSpace(*request.Name),
+		BadgeID:    *request.BadgeID,
+		MiniExamID: *request.MiniExamID,
+	}
+
+	err = s.contentAdminRepo.InsertContentGroup(&group)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrInsertError
+	}
+
+	return &group, nil
+}
+
+func (s contentAdminService) UpdateContentGroup(id int, request request.ContentGroupRequest) (*content.ContentGroup, error) {
+	group, err := s.getContentGroup(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.checkContentGroupLinks(*request.BadgeID, *request.MiniExamID)
+	if err != nil {
+		return nil, err
+	}
+
+	group.Name = strings.TrimSpace(*request.Name)
+	group.BadgeID = *request.BadgeID
+	group.MiniExamID = *request.MiniExamID
+
+	err = s.contentAdminRepo.UpdateContentGroup(*group)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUpdateError
+	}
+
+	return group, nil
+}
+
+func (s contentAdminService) DeleteContentGroup(id int) (*response.MessageResponse, error) {
+	_, err := s.getContentGroup(id)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.contentAdminRepo.CountContents(id)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	if count > 0 {
+		return nil, errs.ErrContentGroupNotEmpty
+	}
+
+	err = s.contentAdminRepo.DeleteContentGroup(id)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUpdateError
+	}
+
+	return s.deletedMessage(), nil
+}
+
+func (s contentAdminService) GetContents(groupID int) (*response.ContentListResponse, error) {
+	_, err := s.getContentGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := s.contentAdminRepo.GetContents(groupID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	response := response.ContentListResponse{Contents: contents}
+
+	return &response, nil
+}
+
+func (s contentAdminService) CreateContent(request request.ContentRequest) (*content.Content, error) {
+	_, err := s.getContentGroup(*request.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	_content := content.Content{
+		GroupID:   *request.GroupID,
+		Name:      strings.TrimSpace(*request.Name),
+		VideoPath: request.VideoPath,
+		SlidePath: request.SlidePath,
+	}
+
+	err = s.contentAdminRepo.InsertContent(&_content)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrInsertError
+	}
+
+	return &_content, nil
+}
+
+func (s contentAdminService) UpdateContent(id int, request request.ContentRequest) (*content.Content, error) {
+	_content, err := s.getContent(id)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.getContentGroup(*request.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	_content.GroupID = *request.GroupID
+	_content.Name = strings.TrimSpace(*request.Name)
+	_content.VideoPath = request.VideoPath
+	_content.SlidePath = request.SlidePath
+
+	err = s.contentAdminRepo.UpdateContent(*_content)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUpdateError
+	}
+
+	return _content, nil
+}
+
+func (s contentAdminService) DeleteContent(id int) (*response.MessageResponse, error) {
+	_, err := s.getContent(id)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.contentAdminRepo.CountActivities(id)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	if count > 0 {
+		return nil, errs.ErrContentNotEmpty
+	}
+
+	attachments, err := s.learningRepo.GetContentAttachments(id)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	err = s.contentAdminRepo.DeleteContent(id)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUpdateError
+	}
+
+	s.deleteAttachmentObjects(attachments...)
+
+	return s.deletedMessage(), nil
+}
+
+func (s contentAdminService) GetActivities(contentID int) (*response.ActivityListResponse, error) {
+	_, err := s.getContent(contentID)
+	if err != nil {
+		return nil, err
+	}
+
+	activities, err := s.contentAdminRepo.GetActivities(contentID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	response := response.ActivityListResponse{Activities: activities}
+
+	return &response, nil
+}
+
+func (s contentAdminService) GetActivity(id int) (*response.AuthoredActivityResponse, error) {
+	_activity, err := s.getActivity(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var choices interface{}
+
+	if activity.HasChoices(_activity.TypeID) {
+		activityChoices, err := s.learningRepo.GetActivityChoices(_activity.ID, _activity.TypeID)
+		if err != nil {
+			logs.GetInstance().Error(err)
+			return nil, errs.ErrLoadError
+		}
+
+		choices = activity.AuthoredChoices(activityChoices, _activity.TypeID)
+	}
+
+	hints, err := s.learningRepo.GetActivityHints(_activity.ID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	response := response.AuthoredActivityResponse{
+		Activity: *_activity,
+		Choices:  choices,
+		Hints:    hints,
+	}
+
+	return &response, nil
+}
+
+func (s contentAdminService) CreateActivity(request request.ActivityRequest) (*response.AuthoredActivityResponse, error) {
+	choices, err := request.ParseChoices()
+	if err != nil {
+		return nil, err
+	}
+
+	if request.ContentID != nil {
+		_, err = s.getContent(*request.ContentID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_activity := activity.Activity{
+		TypeID:    *request.TypeID,
+		ContentID: request.ContentID,
+		Order:     request.Order,
+		Story:     request.Story,
+		Point:     *request.Point,
+		Question:  *request.Question,
+	}
+
+	err = s.contentAdminRepo.InsertActivity(&_activity, choices)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrInsertError
+	}
+
+	return s.GetActivity(_activity.ID)
+}
+
+func (s contentAdminService) UpdateActivity(id int, request request.ActivityRequest) (*response.AuthoredActivityResponse, error) {
+	_activity, err := s.getActivity(id)
+	if err != nil {
+		return nil, err
+	}
+
+	choices, err := request.ParseChoices()
+	if err != nil {
+		return nil, err
+	}
+
+	if request.ContentID != nil {
+		_, err = s.getContent(*request.ContentID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	oldContentID := _activity.ContentID
+
+	_activity.TypeID = *request.TypeID
+	_activity.ContentID = request.ContentID
+	_activity.Order = request.Order
+	_activity.Story = request.Story
+	_activity.Point = *request.Point
+	_activity.Question = *request.Question
+
+	err = s.contentAdminRepo.UpdateActivity(*_activity, oldContentID, choices)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUpdateError
+	}
+
+	return s.GetActivity(id)
+}
+
+func (s contentAdminService) DeleteActivity(id int) (*response.MessageResponse, error) {
+	_activity, err := s.getActivity(id)
+	if err != nil {
+		return nil, err
+	}
+
+	inUse, err := s.contentAdminRepo.IsActivityInUse(id)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	if inUse {
+		return nil, errs.ErrActivityInUse
+	}
+
+	err = s.contentAdminRepo.DeleteActivity(*_activity)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUpdateError
+	}
+
+	return s.deletedMessage(), nil
+}
+
+func (s contentAdminService) CreateHint(request request.HintRequest) (*activity.Hint, error) {
+	err := s.checkHintLevel(0, *request.ActivityID, *request.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	hint := activity.Hint{
+		ActivityID:  *request.ActivityID,
+		Content:     *request.Content,
+		PointReduce: *request.PointReduce,
+		Level:       *request.Level,
+	}
+
+	err = s.contentAdminRepo.InsertHint(&hint)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrInsertError
+	}
+
+	return &hint, nil
+}
+
+func (s contentAdminService) UpdateHint(id int, request request.HintRequest) (*activity.Hint, error) {
+	hint, err := s.getHint(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.checkHintLevel(id, *request.ActivityID, *request.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	oldActivityID := hint.ActivityID
+
+	hint.ActivityID = *request.ActivityID
+	hint.Content = *request.Content
+	hint.PointReduce = *request.PointReduce
+	hint.Level = *request.Level
+
+	err = s.contentAdminRepo.UpdateHint(*hint, oldActivityID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUpdateError
+	}
+
+	return hint, nil
+}
+
+func (s contentAdminService) DeleteHint(id int) (*response.MessageResponse, error) {
+	hint, err := s.getHint(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.contentAdminRepo.DeleteHint(*hint)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUpdateError
+	}
+
+	return s.deletedMessage(), nil
+}
+
+func (s contentAdminService) GetPrerequisites() (*response.PrerequisiteListResponse, error) {
+	prerequisites, err := s.learningRepo.GetPrerequisites()
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	return &response.PrerequisiteListResponse{Prerequisites: prerequisites}, nil
+}
+
+/**
+ * Lock a content or content group behind a prerequisite, the prerequisites
+ * must leave every target reachable
+ *
+ * @param request 	target and how it is unlocked
+ *
+ * @return the prerequisite
+ */
+func (s contentAdminService) CreatePrerequisite(request request.PrerequisiteRequest) (*content.Prerequisite, error) {
+	prerequisite := content.Prerequisite{
+		TargetType: *request.TargetType,
+		TargetID:   *request.TargetID,
+		Rule:       *request.Rule,
+		RequiredID: *request.RequiredID,
+		Count:      request.Count,
+	}
+
+	err := s.checkPrerequisiteLinks(prerequisite)
+	if err != nil {
+		return nil, err
+	}
+
+	prerequisites, err := s.learningRepo.GetPrerequisites()
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	overview, err := s.learningRepo.GetOverview()
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	if prerequisites.HasCycle(prerequisite, overview) {
+		return nil, errs.ErrPrerequisiteCycle
+	}
+
+	err = s.contentAdminRepo.InsertPrerequisite(&prerequisite)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrInsertError
+	}
+
+	return &prerequisite, nil
+}
+
+func (s contentAdminService) DeletePrerequisite(id int) (*response.MessageResponse, error) {
+	prerequisite, err := s.contentAdminRepo.GetPrerequisite(id)
+	if err != nil || prerequisite.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrPrerequisiteNotFound
+	}
+
+	err = s.contentAdminRepo.DeletePrerequisite(id)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUpdateError
+	}
+
+	return s.deletedMessage(), nil
+}
+
+// checkPrerequisiteLinks makes sure the target of the prerequisite and what
+// it requires exist.
+func (s contentAdminService) checkPrerequisiteLinks(prerequisite content.Prerequisite) error {
+	exists, err := s.contentAdminRepo.HasTarget(prerequisite.TargetType, prerequisite.TargetID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return errs.ErrLoadError
+	} else if !exists && prerequisite.TargetType == content.PREREQUISITE_TARGET_CONTENT_GROUP {
+		return errs.ErrContentGroupNotFound
+	} else if !exists {
+		return errs.ErrContentNotFound
+	}
+
+	switch prerequisite.Rule {
+	case content.UNLOCK_RULE_COMPLETE_ACTIVITIES:
+		exists, err = s.contentAdminRepo.HasTarget(content.PREREQUISITE_TARGET_CONTENT, prerequisite.RequiredID)
+		if err == nil && !exists {
+			return errs.ErrContentNotFound
+		}
+	case content.UNLOCK_RULE_PASS_MINI_EXAM:
+		exists, err = s.contentAdminRepo.HasTarget(content.PREREQUISITE_TARGET_CONTENT_GROUP, prerequisite.RequiredID)
+		if err == nil && !exists {
+			return errs.ErrContentGroupNotFound
+		}
+	case content.UNLOCK_RULE_EARN_BADGE:
+		exists, err = s.contentAdminRepo.HasBadge(prerequisite.RequiredID)
+		if err == nil && !exists {
+			return errs.ErrBadgeNotFound
+		}
+	}
+
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return errs.ErrLoadError
+	}
+
+	return nil
+}
+
+func (s contentAdminService) getContentGroup(id int) (*content.ContentGroup, error) {
+	group, err := s.contentAdminRepo.GetContentGroup(id)
+	if err != nil || group.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrContentGroupNotFound
+	}
+	return group, nil
+}
+
+// checkContentGroupLinks makes sure the badge given for passing the group and
+// its mini exam exist.
+func (s contentAdminService) checkContentGroupLinks(badgeID int, miniExamID int) error {
+	exists, err := s.contentAdminRepo.HasBadge(badgeID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return errs.ErrLoadError
+	} else if !exists {
+		return errs.ErrBadgeNotFound
+	}
+
+	exists, err = s.contentAdminRepo.HasExam(miniExamID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return errs.ErrLoadError
+	} else if !exists {
+		return errs.ErrExamNotFound
+	}
+
+	return nil
+}
+
+func (s contentAdminService) getContent(id int) (*content.Content, error) {
+	_content, err := s.contentAdminRepo.GetContent(id)
+	if err != nil || _content.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrContentNotFound
+	}
+	return _content, nil
+}
+
+func (s contentAdminService) getActivity(id int) (*activity.Activity, error) {
+	_activity, err := s.contentAdminRepo.GetActivity(id)
+	if err != nil || _activity.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrActivitiesNotFound
+	}
+	return _activity, nil
+}
+
+func (s contentAdminService) getHint(id int) (*activity.Hint, error) {
+	hint, err := s.contentAdminRepo.GetHint(id)
+	if err != nil || hint.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrHintNotFound
+	}
+	return hint, nil
+}
+
+// checkHintLevel makes sure the activity exists and no other of its hints has
+// the level, hints are handed out in level order.
+func (s contentAdminService) checkHintLevel(hintID int, activityID int, level int) error {
+	_, err := s.getActivity(activityID)
+	if err != nil {
+		return err
+	}
+
+	hints, err := s.learningRepo.GetActivityHints(activityID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return errs.ErrLoadError
+	}
+
+	for _, hint := range hints {
+		if hint.ID != hintID && hint.Level == level {
+			return errs.ErrHintLevelInvalid
+		}
+	}
+
+	return nil
+}
+
+func (s contentAdminService) deletedMessage() *response.MessageResponse {
+	return &response.MessageResponse{
+		ThMessage: response.CONTENT_DELETED_TH,
+		EnMessage: response.CONTENT_DELETED_EN,
+	}
+}
+
+func (s contentAdminService) GetAttachmentStatistics(contentID int) (*response.AttachmentStatisticResponse, error) {
+	_, err := s.getContent(contentID)
+	if err != nil {
+		return nil, err
+	}
+
+	statistics, err := s.contentAdminRepo.GetAttachmentStatistics(contentID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	return &response.AttachmentStatisticResponse{Attachments: statistics}, nil
+}
+
+/**
+ * Upload a file to the bucket under a new name and attach it to a content
+ *
+ * @param contentID 	content the file belongs to
+ * @param request 		file and what it is for
+ *
+ * @return the attachment
+ */
+func (s contentAdminService) CreateAttachment(contentID int, request request.AttachmentRequest) (*content.Attachment, error) {
+	_, err := s.getContent(contentID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := utils.RandomToken()
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrInternalServerError
+	}
+
+	attachment := content.Attachment{
+		ContentID:   contentID,
+		Type:        request.Type,
+		Name:        strings.TrimSpace(request.Name),
+		FileName:    request.FileName,
+		ObjectName:  fmt.Sprintf("attachment/%d/%s%s", contentID, token[:16], path.Ext(request.FileName)),
+		ContentType: request.ContentType,
+		Size:        int64(len(request.Data)),
+	}
+
+	err = s.attachmentRepo.UploadAttachment(attachment.ObjectName, attachment.ContentType, request.Data)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrServiceUnavailableError
+	}
+
+	err = s.contentAdminRepo.InsertAttachment(&attachment)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		s.deleteAttachmentObjects(attachment)
+		return nil, errs.ErrInsertError
+	}
+
+	return &attachment, nil
+}
+
+func (s contentAdminService) DeleteAttachment(id int) (*response.MessageResponse, error) {
+	attachment, err := s.learningRepo.GetAttachment(id)
+	if err != nil || attachment.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrAttachmentNotFound
+	}
+
+	err = s.contentAdminRepo.DeleteAttachment(id)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUpdateError
+	}
+
+	s.deleteAttachmentObjects(*attachment)
+
+	return s.deletedMessage(), nil
+}
+
+// deleteAttachmentObjects removes the files of deleted attachments, a file
+// left in the bucket is only logged since nothing links to it any more
+func (s contentAdminService) deleteAttachmentObjects(attachments ...content.Attachment) {
+	if len(attachments) == 0 {
+		return
+	}
+
+	objectNames := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		objectNames = append(objectNames, attachment.ObjectName)
+	}
+
+	err := s.attachmentRepo.DeleteAttachment(objectNames...)
+	if err != nil {
+		logs.GetInstance().Error(err)
+	}
+}
@@ -0,0 +1,441 @@
+package services
+
+import (
+	"bytes"
+	"database-camp/internal/errs"
+	"database-camp/internal/logs"
+	"database-camp/internal/models/entities/content"
+	"database-camp/internal/models/entities/curriculum"
+	"database-camp/internal/models/entities/exam"
+	"database-camp/internal/models/request"
+	"database-camp/internal/repositories"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type CurriculumService interface {
+	Export(format string) ([]byte, error)
+	Import(data []byte, format string, dryRun bool) (curriculum.Diff, error)
+}
+
+type curriculumService struct {
+	curriculumRepo repositories.CurriculumRepository
+}
+
+func NewCurriculumService(curriculumRepo repositories.CurriculumRepository) *curriculumService {
+	return &curriculumService{curriculumRepo: curriculumRepo}
+}
+
+func (s curriculumService) Export(format string) ([]byte, error) {
+	bundle, err := s.curriculumRepo.GetBundle()
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrInternalServerError
+	}
+
+	switch format {
+	case curriculum.FORMAT_JSON:
+		return append(data, '\n'), nil
+	case curriculum.FORMAT_YAML:
+		return jsonToYAML(data)
+	default:
+		return nil, errs.ErrBundleFormatUnknown
+	}
+}
+
+// Import validates the bundle against itself and the database, and writes
+// what differs unless it is a dry run. The diff is returned either way.
+func (s curriculumService) Import(data []byte, format string, dryRun bool) (curriculum.Diff, error) {
+	bundle, err := decodeBundle(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.curriculumRepo.GetBundle()
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	err = validateBundle(bundle, *current)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle.Sort()
+	diff := curriculum.Compare(*bundle, *current)
+
+	if dryRun {
+		return diff, nil
+	}
+
+	err = s.curriculumRepo.ImportBundle(*bundle, diff)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUpdateError
+	}
+
+	return diff, nil
+}
+
+func decodeBundle(data []byte, format string) (*curriculum.Bundle, error) {
+	switch format {
+	case curriculum.FORMAT_JSON:
+	case curriculum.FORMAT_YAML:
+		var document interface{}
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			logs.GetInstance().Error(err)
+			return nil, errs.ErrBundleInvalid
+		}
+
+		var err error
+		if data, err = json.Marshal(document); err != nil {
+			logs.GetInstance().Error(err)
+			return nil, errs.ErrBundleInvalid
+		}
+	default:
+		return nil, errs.ErrBundleFormatUnknown
+	}
+
+	bundle := curriculum.Bundle{}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&bundle); err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrBundleInvalid
+	}
+
+	return &bundle, nil
+}
+
+// validateBundle checks every row of the bundle and parses the choices into
+// the structure of their activity type. References may point to rows of the
+// bundle or of the database, rows missing from the bundle are not deleted.
+func validateBundle(bundle *curriculum.Bundle, current curriculum.Bundle) error {
+	problems := curriculum.ValidationErrors{}
+
+	if bundle.Version != curriculum.BUNDLE_VERSION {
+		problems.Add("version %d is not supported, expected %d", bundle.Version, curriculum.BUNDLE_VERSION)
+		return problems
+	}
+
+	badgeIDs := map[int]bool{}
+	for _, badge := range current.Badges {
+		badgeIDs[badge.ID] = true
+	}
+
+	seen := map[int]bool{}
+	for _, badge := range bundle.Badges {
+		if badge.ID <= 0 || seen[badge.ID] {
+			problems.Add("badge %d: id must be positive and unique", badge.ID)
+		}
+		if strings.TrimSpace(badge.Name) == "" {
+			problems.Add("badge %d: name is empty", badge.ID)
+		}
+		seen[badge.ID] = true
+		badgeIDs[badge.ID] = true
+	}
+
+	examTypes := map[int]exam.ExamType{}
+	for _, _exam := range current.Exams {
+		examTypes[_exam.ID] = _exam.Type
+	}
+
+	seen = map[int]bool{}
+	for _, _exam := range bundle.Exams {
+		if _exam.ID <= 0 || seen[_exam.ID] {
+			problems.Add("exam %d: id must be positive and unique", _exam.ID)
+		}
+		switch _exam.Type {
+		case exam.PRE, exam.MINI, exam.POST:
+		default:
+			problems.Add("exam %d: type %q is not PRE, MINI or POST", _exam.ID, _exam.Type)
+		}
+		seen[_exam.ID] = true
+		examTypes[_exam.ID] = _exam.Type
+	}
+
+	groupIDs := map[int]bool{}
+	for _, group := range current.ContentGroups {
+		groupIDs[group.ID] = true
+	}
+
+	seen = map[int]bool{}
+	for _, group := range bundle.ContentGroups {
+		if group.ID <= 0 || seen[group.ID] {
+			problems.Add("content group %d: id must be positive and unique", group.ID)
+		}
+		if strings.TrimSpace(group.Name) == "" {
+			problems.Add("content group %d: name is empty", group.ID)
+		}
+		if !badgeIDs[group.BadgeID] {
+			problems.Add("content group %d: badge %d not found", group.ID, group.BadgeID)
+		}
+		if examTypes[group.MiniExamID] != exam.MINI {
+			problems.Add("content group %d: mini exam %d not found", group.ID, group.MiniExamID)
+		}
+		seen[group.ID] = true
+		groupIDs[group.ID] = true
+	}
+
+	seen = map[int]bool{}
+	for _, group := range bundle.ContentGroups {
+		for _, _content := range group.Contents {
+			if _content.ID <= 0 || seen[_content.ID] {
+				problems.Add("content %d: id must be positive and unique", _content.ID)
+			}
+			if _content.GroupID != group.ID {
+				problems.Add("content %d: content_group_id %d does not match its group %d", _content.ID, _content.GroupID, group.ID)
+			}
+			if strings.TrimSpace(_content.Name) == "" {
+				problems.Add("content %d: name is empty", _content.ID)
+			}
+			seen[_content.ID] = true
+		}
+	}
+
+	activityIDs := map[int]bool{}
+	for _, _activity := range current.AllActivities() {
+		activityIDs[_activity.ID] = true
+	}
+
+	seenActivities := map[int]bool{}
+	seenHints := map[int]bool{}
+
+	validateActivity := func(_activity *curriculum.Activity, contentID *int) {
+		if _activity.ID <= 0 || seenActivities[_activity.ID] {
+			problems.Add("activity %d: id must be positive and unique", _activity.ID)
+		}
+		seenActivities[_activity.ID] = true
+		activityIDs[_activity.ID] = true
+
+		if contentID == nil && _activity.ContentID != nil {
+			problems.Add("activity %d: activities of a content are listed under the content", _activity.ID)
+		} else if contentID != nil && (_activity.ContentID == nil || *_activity.ContentID != *contentID) {
+			problems.Add("activity %d: content_id does not match its content %d", _activity.ID, *contentID)
+		}
+
+		activityRequest := request.ActivityRequest{
+			TypeID:    &_activity.TypeID,
+			ContentID: _activity.ContentID,
+			Order:     _activity.Order,
+			Story:     _activity.Story,
+			Point:     &_activity.Point,
+			Question:  &_activity.Question,
+			Choices:   _activity.Choices,
+		}
+
+		if err := activityRequest.Validate(); err != nil {
+			problems.Add("activity %d: %s", _activity.ID, err)
+		} else {
+			_activity.Choices, _ = activityRequest.ParseChoices()
+		}
+
+		levels := map[int]bool{}
+		for _, hint := range _activity.Hints {
+			if hint.ID <= 0 || seenHints[hint.ID] {
+				problems.Add("hint %d: id must be positive and unique", hint.ID)
+			}
+			seenHints[hint.ID] = true
+
+			if hint.ActivityID != _activity.ID {
+				problems.Add("hint %d: activity_id %d does not match its activity %d", hint.ID, hint.ActivityID, _activity.ID)
+			}
+
+			hintRequest := request.HintRequest{
+				ActivityID:  &hint.ActivityID,
+				Content:     &hint.Content,
+				PointReduce: &hint.PointReduce,
+				Level:       &hint.Level,
+			}
+
+			if err := hintRequest.Validate(); err != nil {
+				problems.Add("hint %d: %s", hint.ID, err)
+			} else if levels[hint.Level] {
+				problems.Add("hint %d: %s", hint.ID, errs.ErrHintLevelInvalid)
+			}
+			levels[hint.Level] = true
+		}
+	}
+
+	for i := range bundle.ContentGroups {
+		contents := bundle.ContentGroups[i].Contents
+		for j := range contents {
+			for k := range contents[j].Activities {
+				validateActivity(&contents[j].Activities[k], &contents[j].ID)
+			}
+		}
+	}
+
+	for i := range bundle.Activities {
+		validateActivity(&bundle.Activities[i], nil)
+	}
+
+	for _, _exam := range bundle.Exams {
+		seen := map[curriculum.ExamActivity]bool{}
+		for _, examActivity := range _exam.Activities {
+			if !activityIDs[examActivity.ActivityID] {
+				problems.Add("exam %d: activity %d not found", _exam.ID, examActivity.ActivityID)
+			}
+			if !groupIDs[examActivity.ContentGroupID] {
+				problems.Add("exam %d: content group %d not found", _exam.ID, examActivity.ContentGroupID)
+			}
+			if seen[examActivity] {
+				problems.Add("exam %d: activity %d is listed twice", _exam.ID, examActivity.ActivityID)
+			}
+			seen[examActivity] = true
+		}
+	}
+
+	contentGroupIDs := map[int]int{}
+	for _, _content := range append(current.AllContents(), bundle.AllContents()...) {
+		contentGroupIDs[_content.ID] = _content.GroupID
+	}
+
+	overview := content.OverviewList{}
+	for contentID, groupID := range contentGroupIDs {
+		overview = append(overview, content.Overview{GroupID: groupID, ContentID: contentID})
+	}
+
+	targetIDs := map[string]func(int) bool{
+		content.PREREQUISITE_TARGET_CONTENT:       func(id int) bool { _, ok := contentGroupIDs[id]; return ok },
+		content.PREREQUISITE_TARGET_CONTENT_GROUP: func(id int) bool { return groupIDs[id] },
+	}
+	requiredIDs := map[string]func(int) bool{
+		content.UNLOCK_RULE_COMPLETE_ACTIVITIES: targetIDs[content.PREREQUISITE_TARGET_CONTENT],
+		content.UNLOCK_RULE_PASS_MINI_EXAM:      targetIDs[content.PREREQUISITE_TARGET_CONTENT_GROUP],
+		content.UNLOCK_RULE_EARN_BADGE:          func(id int) bool { return badgeIDs[id] },
+	}
+
+	seen = map[int]bool{}
+	for _, prerequisite := range bundle.Prerequisites {
+		if prerequisite.ID <= 0 || seen[prerequisite.ID] {
+			problems.Add("prerequisite %d: id must be positive and unique", prerequisite.ID)
+		}
+		seen[prerequisite.ID] = true
+
+		prerequisiteRequest := request.PrerequisiteRequest{
+			TargetType: &prerequisite.TargetType,
+			TargetID:   &prerequisite.TargetID,
+			Rule:       &prerequisite.Rule,
+			RequiredID: &prerequisite.RequiredID,
+			Count:      prerequisite.Count,
+		}
+
+		if err := prerequisiteRequest.Validate(); err != nil {
+			problems.Add("prerequisite %d: %s", prerequisite.ID, err)
+			continue
+		}
+
+		if !targetIDs[prerequisite.TargetType](prerequisite.TargetID) {
+			problems.Add("prerequisite %d: %s %d not found", prerequisite.ID, prerequisite.TargetType, prerequisite.TargetID)
+		}
+		if !requiredIDs[prerequisite.Rule](prerequisite.RequiredID) {
+			problems.Add("prerequisite %d: required %d of rule %s not found", prerequisite.ID, prerequisite.RequiredID, prerequisite.Rule)
+		}
+	}
+
+	// Prerequisites only in the database stay, so they take part in cycles
+	prerequisites := append(content.Prerequisites{}, bundle.Prerequisites...)
+	for _, prerequisite := range current.Prerequisites {
+		if !seen[prerequisite.ID] {
+			prerequisites = append(prerequisites, prerequisite)
+		}
+	}
+
+	for _, prerequisite := range bundle.Prerequisites {
+		if prerequisites.HasCycle(prerequisite, overview) {
+			problems.Add("prerequisite %d: %s", prerequisite.ID, errs.ErrPrerequisiteCycle)
+		}
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+
+	return nil
+}
+
+// jsonToYAML converts through yaml nodes rather than maps, so the keys keep
+// the order of the structs instead of being sorted.
+func jsonToYAML(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	node, err := yamlNode(decoder)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrInternalServerError
+	}
+
+	buffer := bytes.Buffer{}
+
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+
+	if err = encoder.Encode(node); err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrInternalServerError
+	}
+
+	return buffer.Bytes(), encoder.Close()
+}
+
+func yamlNode(decoder *json.Decoder) (*yaml.Node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		if token == '{' {
+			node.Kind = yaml.MappingNode
+		}
+
+		for decoder.More() {
+			if node.Kind == yaml.MappingNode {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: fmt.Sprint(key)})
+			}
+
+			child, err := yamlNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+
+		// The closing delimiter
+		_, err = decoder.Token()
+		return node, err
+
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: token}, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(token.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: token.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(token)}, nil
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+}
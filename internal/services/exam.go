@@ -5,6 +5,7 @@ import (
 	"database-camp/internal/infrastructure/cache"
 	"database-camp/internal/logs"
 	"database-camp/internal/models/entities/badge"
+	"database-camp/internal/models/entities/content"
 	"database-camp/internal/models/entities/exam"
 	"database-camp/internal/models/request"
 	"database-camp/internal/models/response"
@@ -45,9 +46,14 @@ func NewExamService(
 }
 
 func (s examService) GetExam(examID int, userID int) (*response.ExamResponse, error) {
+	err := s.checkVerified(userID)
+	if err != nil {
+		return nil, err
+	}
+
 	examLoader := loaders.NewExamLoader(s.examRepo, s.userRepo)
 
-	err := examLoader.Load(userID, examID)
+	err = examLoader.Load(userID, examID)
 	if err != nil {
 		logs.GetInstance().Error(err)
 		return nil, errs.ErrExamNotFound
@@ -67,6 +73,11 @@ func (s examService) GetExam(examID int, userID int) (*response.ExamResponse, er
 		return nil, errs.ErrFinalExamBadgesNotEnough
 	}
 
+	err = s.checkExamUnlocked(userID, *_exam)
+	if err != nil {
+		return nil, err
+	}
+
 	activitiesExamLoader := loaders.NewActivityExamLoader(s.learningRepo)
 
 	err = activitiesExamLoader.Load(examActivities)
@@ -120,9 +131,14 @@ func (s examService) GetOverview(userID int) (*response.ExamOverviewResponse, er
 }
 
 func (s examService) CheckExam(userID int, request request.ExamAnswerRequest) (*response.ExamResultOverviewResponse, error) {
+	err := s.checkVerified(userID)
+	if err != nil {
+		return nil, err
+	}
+
 	checkExamLoader := loaders.NewCheckExamLoader(s.examRepo)
 
-	err := checkExamLoader.Load(*request.ExamID)
+	err = checkExamLoader.Load(*request.ExamID)
 	if err != nil {
 		logs.GetInstance().Error(err)
 		return nil, errs.ErrExamNotFound
@@ -135,6 +151,11 @@ func (s examService) CheckExam(userID int, request request.ExamAnswerRequest) (*
 		return nil, errs.ErrExamNotFound
 	}
 
+	err = s.checkExamUnlocked(userID, *exam)
+	if err != nil {
+		return nil, err
+	}
+
 	activitiesExamLoader := loaders.NewActivityExamLoader(s.learningRepo)
 
 	err = activitiesExamLoader.Load(examActivities)
@@ -149,7 +170,14 @@ func (s examService) CheckExam(userID int, request request.ExamAnswerRequest) (*
 		return nil, errs.ErrActivitiesNumberIncorrect
 	}
 
-	result, err := activities.CheckAnswers(*request.ExamID, userID, request.Activities)
+	// Without the overview an exam still counts, only its activities of no
+	// content go unobserved by the mastery
+	overview, err := s.learningRepo.GetOverview()
+	if err != nil {
+		logs.GetInstance().Error(err)
+	}
+
+	result, err := activities.CheckAnswers(*request.ExamID, userID, request.Activities, content.OverviewList(overview).GroupContentIDs())
 	if err != nil {
 		logs.GetInstance().Info(err)
 
@@ -161,6 +189,12 @@ func (s examService) CheckExam(userID int, request request.ExamAnswerRequest) (*
 		return nil, err
 	}
 
+	// The result is saved, a mastery that fails to update stays as it was
+	err = s.learningRepo.UpdateMasteries(userID, result.Observations, time.Now().Local())
+	if err != nil {
+		logs.GetInstance().Error(err)
+	}
+
 	if exam.BadgeID != 0 {
 		_, err = s.userRepo.InsertBadge(badge.UserBadge{
 			UserID:  userID,
@@ -188,7 +222,7 @@ func (s examService) CheckExam(userID int, request request.ExamAnswerRequest) (*
 	if data, err := json.Marshal(response); err != nil {
 		return nil, err
 	} else {
-		if err = s.cache.Set(key, string(data), time.Minute*10); err != nil {
+		if err = s.cache.Set(key, string(data), time.Second*10); err != nil {
 			return nil, err
 		}
 	}
@@ -218,6 +252,12 @@ func (s examService) GetExamResult(userID int, examResultID int) (*response.Exam
 	examResult := loader.GetExamResult()
 	resultActivities := loader.GetResultActivities()
 
+	// The activities are loaded by the result alone, they hold the answers
+	// of whoever took the exam
+	if examResult == nil || examResult.ID == 0 {
+		return nil, errs.ErrExamNotFound
+	}
+
 	res = response.ExamResultOverviewResponse{
 		ExamResultID:     examResult.ID,
 		ExamID:           examResult.ExamID,
@@ -232,10 +272,44 @@ func (s examService) GetExamResult(userID int, examResultID int) (*response.Exam
 	if data, err := json.Marshal(res); err != nil {
 		return nil, err
 	} else {
-		if err = s.cache.Set(key, string(data), time.Minute*10); err != nil {
+		if err = s.cache.Set(key, string(data), time.Second*10); err != nil {
 			return nil, err
 		}
 	}
 
 	return &res, nil
 }
+
+// checkVerified keeps users who have not verified their email out of exams.
+func (s examService) checkVerified(userID int) error {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || user.ID == 0 {
+		logs.GetInstance().Error(err)
+		return errs.ErrUserNotFound
+	}
+
+	if !user.IsVerified() {
+		return errs.ErrEmailNotVerified
+	}
+
+	return nil
+}
+
+// checkExamUnlocked makes sure the content group of a mini exam is unlocked,
+// the pre exam and the final exam belong to no group
+func (s examService) checkExamUnlocked(userID int, _exam exam.Exam) error {
+	if _exam.Type != string(exam.MINI) {
+		return nil
+	}
+
+	locks, err := loadLocks(s.learningRepo, s.userRepo, s.examRepo, userID)
+	if err != nil {
+		return err
+	}
+
+	if locks.IsGroupLocked(_exam.ContentGroupID) {
+		return errs.ErrContentGroupLocked
+	}
+
+	return nil
+}
@@ -5,40 +5,117 @@ import (
 	"database-camp/internal/logs"
 	"database-camp/internal/models/entities/activity"
 	"database-camp/internal/models/entities/content"
+	"database-camp/internal/models/entities/user"
 	"database-camp/internal/models/request"
 	"database-camp/internal/models/response"
 	"database-camp/internal/repositories"
 	"database-camp/internal/services/loaders"
 	"database-camp/internal/utils"
+	"encoding/json"
+	"time"
 )
 
 type LearningService interface {
-	GetVideoLecture(id int) (*response.VideoLectureResponse, error)
+	GetVideoLecture(userID int, id int) (*response.VideoLectureResponse, error)
 	GetOverview(userID int) (*response.ContentOverviewResponse, error)
 	GetActivity(userID int, activityID int) (*response.ActivityResponse, error)
-	GetRecommend(userID int) (*response.RecommendResponse, error)
+	GetRecommend(userID int, request request.RecommendRequest) (*response.RecommendResponse, error)
+	GetReviews(userID int, request request.ReviewQueueRequest) (*response.ReviewQueueResponse, error)
 	UseHint(userID int, activityID int) (*response.UsedHintResponse, error)
 	GetContentRoadmap(userID int, contentID int) (*response.ContentRoadmapResponse, error)
 	CheckAnswer(userID int, request request.CheckAnswerRequest) (*response.AnswerResponse, error)
 	CheckPeerReview(userID int, request request.PeerReviewRequest) (*response.AnswerResponse, error)
+	GetAttachments(userID int, contentID int) (*response.AttachmentListResponse, error)
+	GetAttachmentLink(userID int, attachmentID int) (*response.AttachmentLinkResponse, error)
 }
 
 type learningService struct {
-	learningRepo repositories.LearningRepository
-	userRepo     repositories.UserRepository
+	learningRepo   repositories.LearningRepository
+	userRepo       repositories.UserRepository
+	examRepo       repositories.ExamRepository
+	attachmentRepo repositories.AttachmentRepository
 }
 
-func NewLearningService(learningRepo repositories.LearningRepository, userRepo repositories.UserRepository) *learningService {
-	return &learningService{learningRepo: learningRepo, userRepo: userRepo}
+func NewLearningService(
+	learningRepo repositories.LearningRepository,
+	userRepo repositories.UserRepository,
+	examRepo repositories.ExamRepository,
+	attachmentRepo repositories.AttachmentRepository,
+) *learningService {
+	return &learningService{
+		learningRepo:   learningRepo,
+		userRepo:       userRepo,
+		examRepo:       examRepo,
+		attachmentRepo: attachmentRepo,
+	}
+}
+
+type locksLoader interface {
+	Load(userID int) error
+	GetLocks() content.Locks
+}
+
+// loadLocks finds the contents and content groups the user has not unlocked
+func loadLocks(
+	learningRepo repositories.LearningRepository,
+	userRepo repositories.UserRepository,
+	examRepo repositories.ExamRepository,
+	userID int,
+) (*content.Locks, error) {
+	return loadLocksWith(loaders.NewUnlockLoader(learningRepo, userRepo, examRepo), userID)
+}
+
+// loadLocksWith finds the locks with a loader given what the caller already
+// loaded
+func loadLocksWith(loader locksLoader, userID int) (*content.Locks, error) {
+	err := loader.Load(userID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	locks := loader.GetLocks()
+
+	return &locks, nil
+}
+
+// checkActivityUnlocked makes sure the content of the activity is unlocked,
+// activities of no content are only in exams and are never locked
+func (s learningService) checkActivityUnlocked(userID int, _activity activity.Activity) error {
+	if _activity.ContentID == nil {
+		return nil
+	}
+
+	return s.checkContentUnlocked(userID, *_activity.ContentID)
+}
+
+func (s learningService) checkContentUnlocked(userID int, contentID int) error {
+	locks, err := loadLocks(s.learningRepo, s.userRepo, s.examRepo, userID)
+	if err != nil {
+		return err
+	}
+
+	if locks.IsContentLocked(contentID) {
+		return errs.ErrContentLocked
+	}
+
+	return nil
 }
 
-func (s learningService) GetVideoLecture(id int) (*response.VideoLectureResponse, error) {
+// GetVideoLecture links the video of a content, the video of a locked content
+// is not linked until it is unlocked.
+func (s learningService) GetVideoLecture(userID int, id int) (*response.VideoLectureResponse, error) {
 	contentDB, err := s.learningRepo.GetContent(id)
 	if err != nil || contentDB == nil {
 		logs.GetInstance().Error(err)
 		return nil, errs.ErrContentNotFound
 	}
 
+	err = s.checkContentUnlocked(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
 	videoLink, err := s.learningRepo.GetVideoFileLink(contentDB.VideoPath)
 	if err != nil {
 		logs.GetInstance().Error(err)
@@ -68,6 +145,17 @@ func (s learningService) GetOverview(userID int) (*response.ContentOverviewRespo
 	learningProgression := loader.GetLearningProgression()
 	lastedGroup, contentGroup := overview.GetLearningOverview(learningProgression)
 
+	unlockLoader := loaders.NewUnlockLoader(s.learningRepo, s.userRepo, s.examRepo)
+	unlockLoader.SetOverview(overview)
+	unlockLoader.SetProgressions(learningProgression)
+
+	locks, err := loadLocksWith(unlockLoader, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	locks.SetLocked(contentGroup)
+
 	response := response.ContentOverviewResponse{
 		PreExam:              preExamID,
 		LastedGroup:          lastedGroup,
@@ -94,6 +182,11 @@ func (s learningService) GetActivity(userID int, activityID int) (*response.Acti
 		return nil, errs.ErrActivitiesNotFound
 	}
 
+	err = s.checkActivityUnlocked(userID, *_activity)
+	if err != nil {
+		return nil, err
+	}
+
 	choices, err := s.learningRepo.GetActivityChoices(_activity.ID, _activity.TypeID)
 	if err != nil {
 		logs.GetInstance().Error(err)
@@ -124,25 +217,34 @@ func (s learningService) UseHint(userID int, activityID int) (*response.UsedHint
 
 	activityHints := loader.GetActivityHintsDB()
 	userHints := loader.GetUserHintsDB()
-	user := loader.GetUser()
 
 	if len(activityHints) == 0 {
 		return nil, errs.ErrLoadError
 	}
 
+	// A hint gives the answer away, so it is as locked as its activity
+	_activity, err := s.learningRepo.GetActivity(activityID)
+	if err != nil || _activity.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrActivitiesNotFound
+	}
+
+	err = s.checkActivityUnlocked(userID, *_activity)
+	if err != nil {
+		return nil, err
+	}
+
 	nextLevelHint := activityHints.GetNextLevelHint(userHints)
 	if nextLevelHint == nil {
 		return nil, errs.ErrHintAlreadyUsed
 	}
 
-	if user.Point < nextLevelHint.PointReduce {
-		return nil, errs.ErrHintPointsNotEnough
-	}
-
-	err = s.learningRepo.UseHint(userID, nextLevelHint.PointReduce, nextLevelHint.ID)
+	ok, err := s.learningRepo.UseHint(userID, nextLevelHint.PointReduce, nextLevelHint.ID)
 	if err != nil {
 		logs.GetInstance().Error(err)
 		return nil, errs.ErrInsertError
+	} else if !ok {
+		return nil, errs.ErrHintPointsNotEnough
 	}
 
 	response := response.UsedHintResponse{
@@ -171,40 +273,41 @@ func (s learningService) GetContentRoadmap(userID int, contentID int) (*response
 
 	roadmapItems := contentActivity.GetContentRoadmap(learningProgression)
 
+	unlockLoader := loaders.NewUnlockLoader(s.learningRepo, s.userRepo, s.examRepo)
+	unlockLoader.SetProgressions(learningProgression)
+
+	locks, err := loadLocksWith(unlockLoader, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	unmetPrerequisites := locks.GetUnmetContent(content.ID)
+
 	response := response.ContentRoadmapResponse{
-		ContentID:   content.ID,
-		ContentName: content.Name,
-		Items:       roadmapItems,
+		ContentID:          content.ID,
+		ContentName:        content.Name,
+		IsLocked:           len(unmetPrerequisites) > 0,
+		UnmetPrerequisites: unmetPrerequisites,
+		Items:              roadmapItems,
 	}
 
 	return &response, nil
 }
 
-func (s learningService) finishActivityAnswer(
-	progression *content.LearningProgression,
-	activityID int,
-	activityTypeID int,
-	activityPoint int,
-	isCorrect bool,
-	userID int,
-) (int, error) {
-	hasProgression := *progression != (content.LearningProgression{})
-
-	err := s.userRepo.InsertLearningProgression(userID, activityID, activityPoint, isCorrect, hasProgression)
+func (s learningService) finishActivityAnswer(progression content.LearningProgression, reason string) (int, error) {
+	err := s.userRepo.InsertLearningProgression(progression, reason)
 	if err != nil {
 		logs.GetInstance().Error(err)
 		return 0, errs.ErrInsertError
 	}
 
-	user, err := s.userRepo.GetUserByID(userID)
-	if err != nil || user == nil {
+	_user, err := s.userRepo.GetUserByID(progression.UserID)
+	if err != nil || _user == nil {
 		logs.GetInstance().Error(err)
 		return 0, errs.ErrUserNotFound
 	}
 
-	logs.GetInstance().Info(user.Point)
-
-	return user.Point, nil
+	return _user.Point, nil
 }
 
 func (s learningService) CheckAnswer(userID int, request request.CheckAnswerRequest) (*response.AnswerResponse, error) {
@@ -219,7 +322,6 @@ func (s learningService) CheckAnswer(userID int, request request.CheckAnswerRequ
 
 	_activity := loader.GetActivity()
 	choices := loader.GetChoices()
-	progression := loader.GetProgression()
 
 	if _activity == nil {
 		return nil, errs.ErrLoadError
@@ -229,56 +331,49 @@ func (s learningService) CheckAnswer(userID int, request request.CheckAnswerRequ
 		return nil, errs.ErrActivityTypeInvalid
 	}
 
-	var isCorrect bool
-	var errMessage *string
-	var erChoiceAnswer activity.ERChoiceAnswer
-	var choice activity.ERChoice
-
-	if *request.ActivityTypeID == 6 {
-		var ok bool
-		choice, ok = choices.(activity.ERChoice)
-		if !ok {
-			return nil, errs.ErrAnswerInvalid
-		}
-
-		err := utils.StructToStruct(request.Answer, &erChoiceAnswer)
-
-		if err != nil {
-			logs.GetInstance().Error(err)
-			return nil, errs.ErrInternalServerError
-		}
-
-		var message string
-		isCorrect, message = erChoiceAnswer.IsCorrect(choice)
+	err = s.checkActivityUnlocked(userID, *_activity)
+	if err != nil {
+		return nil, err
+	}
 
-		errMessage = &message
-	} else {
-		formatedAnswer, err := activity.FormatAnswer(request.Answer, *request.ActivityTypeID)
-		if err != nil {
-			logs.GetInstance().Error(err)
-			return nil, err
-		}
+	grade, err := activity.GradeAnswer(request.Answer, *request.ActivityTypeID, choices)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, err
+	}
 
-		isCorrect, err = formatedAnswer.IsCorrect(choices)
-		if err != nil {
-			return nil, err
-		}
+	submitted, err := activity.EncodeAnswer(request.Answer, *request.ActivityTypeID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, err
 	}
 
-	updatedPoint, err := s.finishActivityAnswer(
-		progression,
-		*request.ActivityID,
-		*request.ActivityTypeID,
-		_activity.Point,
-		isCorrect,
-		userID,
-	)
+	version := activity.ANSWER_VERSION
+
+	// Answers from the review queue come here too, only what an answer earns
+	// over the best earlier answer is awarded so a review never earns the
+	// point of the activity again
+	updatedPoint, err := s.finishActivityAnswer(content.LearningProgression{
+		UserID:        userID,
+		ActivityID:    *request.ActivityID,
+		IsCorrect:     grade.IsCorrect,
+		Point:         grade.Point(_activity.Point),
+		Answer:        submitted,
+		AnswerVersion: &version,
+	}, user.POINT_REASON_ACTIVITY)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if *request.ActivityTypeID == activity.PEER_ACTIVITY_TYPE_ID && *request.ActivityID == activity.PEER_ACTIVITY_ID && choice.Type == activity.ER_CHOICE_DRAW {
+	if *request.ActivityTypeID == activity.PEER_ACTIVITY_TYPE_ID && *request.ActivityID == activity.PEER_ACTIVITY_ID && choices.(activity.ERChoice).Type == activity.ER_CHOICE_DRAW {
+		var erChoiceAnswer activity.ERChoiceAnswer
+
+		err = utils.StructToStruct(request.Answer, &erChoiceAnswer)
+		if err != nil {
+			logs.GetInstance().Error(err)
+			return nil, errs.ErrInternalServerError
+		}
 
 		erAnswer := activity.ERAnswer{
 			UserID:        userID,
@@ -295,16 +390,135 @@ func (s learningService) CheckAnswer(userID int, request request.CheckAnswerRequ
 
 	response := response.AnswerResponse{
 		ActivityID:   _activity.ID,
-		IsCorrect:    isCorrect,
+		IsCorrect:    grade.IsCorrect,
+		Score:        grade.Score,
+		Items:        grade.Items,
 		UpdatedPoint: updatedPoint,
-		ErrMessage:   errMessage,
+		ErrMessage:   grade.Message,
+		Feedback:     grade.Feedback,
+	}
+
+	if _activity.ID != activity.PEER_ACTIVITY_ID {
+		response.NextReview = s.scheduleReview(userID, _activity.ID, grade)
+		s.observeMastery(userID, *_activity, grade)
 	}
 
 	return &response, nil
 }
 
-func (s learningService) GetRecommend(userID int) (*response.RecommendResponse, error) {
-	loader := loaders.NewRecommendLoader(s.learningRepo, s.userRepo)
+// scheduleReview feeds the answer to the review schedule of the activity, the
+// answer is already recorded so a failure here only leaves the schedule as
+// it was
+func (s learningService) scheduleReview(userID int, activityID int, grade *activity.Grade) *time.Time {
+	quality := content.ReviewQuality(grade.IsCorrect, grade.Score)
+
+	schedule, err := s.learningRepo.ReviewActivity(userID, activityID, quality, time.Now().Local())
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil
+	} else if schedule == nil {
+		return nil
+	}
+
+	return &schedule.DueTimestamp
+}
+
+// observeMastery feeds the answer to the mastery of the content of the
+// activity, like scheduleReview a failure only leaves the mastery as it was
+func (s learningService) observeMastery(userID int, _activity activity.Activity, grade *activity.Grade) {
+	if _activity.ContentID == nil {
+		return
+	}
+
+	err := s.learningRepo.UpdateMasteries(userID, []content.MasteryObservation{{
+		ContentID: *_activity.ContentID,
+		Score:     grade.Score,
+	}}, time.Now().Local())
+	if err != nil {
+		logs.GetInstance().Error(err)
+	}
+}
+
+/**
+ * Get the activities the user should answer again, the most overdue first.
+ * The peer activity is never scheduled and activities of locked contents
+ * wait until they are unlocked.
+ *
+ * @param userID 	user to review
+ * @param request 	how many activities to get
+ *
+ * @return the due activities and when the next one is due
+ */
+func (s learningService) GetReviews(userID int, request request.ReviewQueueRequest) (*response.ReviewQueueResponse, error) {
+	now := time.Now().Local()
+
+	overview, err := s.learningRepo.GetOverview()
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	unlockLoader := loaders.NewUnlockLoader(s.learningRepo, s.userRepo, s.examRepo)
+	unlockLoader.SetOverview(overview)
+
+	locks, err := loadLocksWith(unlockLoader, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	lockedActivityIDs := locks.GetLockedActivityIDs(overview)
+
+	schedules, err := s.learningRepo.GetDueReviews(userID, now, request.Limit, lockedActivityIDs)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	totalDue, err := s.learningRepo.CountDueReviews(userID, now, lockedActivityIDs)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	items := make([]response.ReviewItem, 0, len(schedules))
+	for _, schedule := range schedules {
+		_activity, err := s.learningRepo.GetActivity(schedule.ActivityID)
+		if err != nil || _activity.ID == 0 {
+			logs.GetInstance().Error(err)
+			return nil, errs.ErrActivitiesNotFound
+		}
+
+		choices, err := s.learningRepo.GetActivityChoices(_activity.ID, _activity.TypeID)
+		if err != nil {
+			logs.GetInstance().Error(err)
+			return nil, errs.ErrActivitiesNotFound
+		}
+
+		items = append(items, response.ReviewItem{
+			Activity: *_activity,
+			Choices:  choices.CreatePropositionChoices(),
+			Schedule: schedule,
+		})
+	}
+
+	res := response.ReviewQueueResponse{
+		TotalDue: totalDue,
+		Items:    items,
+	}
+
+	next, err := s.learningRepo.GetNextReview(userID, now, lockedActivityIDs)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	} else if next != nil {
+		res.NextReview = &next.DueTimestamp
+	}
+
+	return &res, nil
+}
+
+func (s learningService) GetRecommend(userID int, request request.RecommendRequest) (*response.RecommendResponse, error) {
+	loader := loaders.NewRecommendLoader(s.learningRepo)
 
 	err := loader.Load(userID)
 	if err != nil {
@@ -312,16 +526,22 @@ func (s learningService) GetRecommend(userID int) (*response.RecommendResponse,
 		return nil, errs.ErrLoadError
 	}
 
-	contentGroups := loader.GetContentGroups()
-	preTestResults := loader.GetPreTestResults()
+	overview := loader.GetOverview()
+	masteries := loader.GetMasteries()
 
-	recommend := preTestResults.GetRecommend(contentGroups)
+	unlockLoader := loaders.NewUnlockLoader(s.learningRepo, s.userRepo, s.examRepo)
+	unlockLoader.SetOverview(overview)
 
-	response := response.RecommendResponse{
-		Recommend: recommend,
+	locks, err := loadLocksWith(unlockLoader, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	return &response, err
+	recommendations := locks.GetUnlockedOverview(overview).GetRecommend(masteries, request.Limit)
+
+	response := response.NewRecommendResponse(recommendations)
+
+	return &response, nil
 }
 
 func (s learningService) CheckPeerReview(userID int, request request.PeerReviewRequest) (*response.AnswerResponse, error) {
@@ -340,7 +560,6 @@ func (s learningService) CheckPeerReview(userID int, request request.PeerReviewR
 
 	_erAnswer := loader.GetERAnswer()
 	_erChoice := loader.GetERChoice()
-	_progression := loader.GetProgression()
 
 	suggestionsList := _erChoice.GetSuggestionsList(activity.ERChoiceAnswer{
 		Tables:        _erAnswer.Tables,
@@ -351,14 +570,29 @@ func (s learningService) CheckPeerReview(userID int, request request.PeerReviewR
 
 	correct := suggestionsList.Compare(request.Reviews)
 
-	updatedPoint, err := s.finishActivityAnswer(
-		_progression,
-		activity.PEER_ACTIVITY_ID,
-		activity.PEER_ACTIVITY_TYPE_ID,
-		activity.PEER_ACTIVITY_POINT,
-		correct,
-		userID,
-	)
+	point, score := 0, 0.0
+	if correct {
+		point, score = activity.PEER_ACTIVITY_POINT, 1
+	}
+
+	// The reviews are kept as the answer of the peer activity, they are not
+	// graded by the activity type so they are never regraded
+	submitted, err := json.Marshal(request.Reviews)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrInternalServerError
+	}
+
+	version := activity.ANSWER_VERSION
+
+	updatedPoint, err := s.finishActivityAnswer(content.LearningProgression{
+		UserID:        userID,
+		ActivityID:    activity.PEER_ACTIVITY_ID,
+		IsCorrect:     correct,
+		Point:         point,
+		Answer:        submitted,
+		AnswerVersion: &version,
+	}, user.POINT_REASON_PEER_REVIEW)
 
 	if err != nil {
 		return nil, err
@@ -367,6 +601,7 @@ func (s learningService) CheckPeerReview(userID int, request request.PeerReviewR
 	res := response.AnswerResponse{
 		ActivityID:   activity.PEER_ACTIVITY_ID,
 		IsCorrect:    correct,
+		Score:        score,
 		UpdatedPoint: updatedPoint,
 	}
 
@@ -29,17 +29,17 @@ func (l *activityExamLoader) Load(examActivities []exam.ExamActivity) error {
 	wg.Add(len(examActivities))
 
 	for _, activity := range examActivities {
-		go func(activityID int, typeID int) {
+		go func(examActivity exam.ExamActivity) {
 			defer wg.Done()
-			l.loadActivity(activityID, typeID, &err)
-		}(activity.ActivityID, activity.ActivityTypeID)
+			l.loadActivity(examActivity, &err)
+		}(activity)
 	}
 
 	wg.Wait()
 	return err
 }
 
-func (l *activityExamLoader) loadActivity(activityID int, typeID int, err *error) {
+func (l *activityExamLoader) loadActivity(examActivity exam.ExamActivity, err *error) {
 	var wg sync.WaitGroup
 	var _activity *activity.Activity
 	var choices activity.Choices
@@ -49,7 +49,7 @@ func (l *activityExamLoader) loadActivity(activityID int, typeID int, err *error
 	go func() {
 		defer wg.Done()
 		var e error
-		_activity, e = l.learningRepo.GetActivity(activityID)
+		_activity, e = l.learningRepo.GetActivity(examActivity.ActivityID)
 		if e != nil {
 			*err = e
 		}
@@ -58,7 +58,7 @@ func (l *activityExamLoader) loadActivity(activityID int, typeID int, err *error
 	go func() {
 		defer wg.Done()
 		var e error
-		choices, e = l.learningRepo.GetActivityChoices(activityID, typeID)
+		choices, e = l.learningRepo.GetActivityChoices(examActivity.ActivityID, examActivity.ActivityTypeID)
 		if e != nil {
 			*err = e
 		}
@@ -69,8 +69,9 @@ func (l *activityExamLoader) loadActivity(activityID int, typeID int, err *error
 	l.mutex.Lock()
 
 	l.activities = append(l.activities, exam.Activity{
-		Activity: *_activity,
-		Choices:  choices,
+		Activity:       *_activity,
+		Choices:        choices,
+		ContentGroupID: examActivity.ContentGroupID,
 	})
 
 	l.mutex.Unlock()
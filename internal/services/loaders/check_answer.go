@@ -2,7 +2,6 @@ package loaders
 
 import (
 	"database-camp/internal/models/entities/activity"
-	"database-camp/internal/models/entities/content"
 	"database-camp/internal/repositories"
 	"sync"
 )
@@ -10,9 +9,8 @@ import (
 type checkAnswerLoader struct {
 	learningRepo repositories.LearningRepository
 
-	choices     activity.Choices
-	activity    *activity.Activity
-	progression *content.LearningProgression
+	choices  activity.Choices
+	activity *activity.Activity
 }
 
 func NewCheckAnswerLoader(learningRepo repositories.LearningRepository) *checkAnswerLoader {
@@ -27,18 +25,13 @@ func (c *checkAnswerLoader) GetActivity() *activity.Activity {
 	return c.activity
 }
 
-func (c *checkAnswerLoader) GetProgression() *content.LearningProgression {
-	return c.progression
-}
-
 func (c *checkAnswerLoader) Load(activityID int, activityTypeID int) error {
 	var wg sync.WaitGroup
 	var err error
 	concurrent := Concurrent{Wg: &wg, Err: &err}
-	wg.Add(3)
+	wg.Add(2)
 	go c.loadActivityAsync(&concurrent, activityID)
 	go c.loadChioces(&concurrent, activityID, activityTypeID)
-	go c.loadProgression(&concurrent, activityID)
 	wg.Wait()
 	return err
 }
@@ -60,12 +53,3 @@ func (c *checkAnswerLoader) loadChioces(concurrent *Concurrent, activityID int,
 		*concurrent.Err = err
 	}
 }
-
-func (c *checkAnswerLoader) loadProgression(concurrent *Concurrent, activityID int) {
-	defer concurrent.Wg.Done()
-	var err error
-	c.progression, err = c.learningRepo.GetCorrectProgression(activityID)
-	if err != nil {
-		*concurrent.Err = err
-	}
-}
@@ -2,7 +2,6 @@ package loaders
 
 import (
 	"database-camp/internal/models/entities/activity"
-	"database-camp/internal/models/entities/content"
 	"database-camp/internal/repositories"
 	"sync"
 )
@@ -10,9 +9,8 @@ import (
 type checkPeerReviewLoader struct {
 	learningRepo repositories.LearningRepository
 
-	erAnswer     *activity.ERAnswer
-	erChoice     *activity.ERChoice
-	progresstion *content.LearningProgression
+	erAnswer *activity.ERAnswer
+	erChoice *activity.ERChoice
 }
 
 func NewCheckPeerReviewLoader(learningRepo repositories.LearningRepository) *checkPeerReviewLoader {
@@ -27,18 +25,13 @@ func (l checkPeerReviewLoader) GetERChoice() *activity.ERChoice {
 	return l.erChoice
 }
 
-func (l checkPeerReviewLoader) GetProgression() *content.LearningProgression {
-	return l.progresstion
-}
-
 func (l *checkPeerReviewLoader) Load(erAnswerID int, activityID int) error {
 	var wg sync.WaitGroup
 	var err error
 	concurrent := Concurrent{Wg: &wg, Err: &err}
-	wg.Add(3)
+	wg.Add(2)
 	go l.loadERAnswer(&concurrent, erAnswerID)
 	go l.loadERChoice(&concurrent, activityID)
-	go l.loadProgression(&concurrent, activityID)
 	wg.Wait()
 	return err
 }
@@ -60,12 +53,3 @@ func (l *checkPeerReviewLoader) loadERChoice(concurrent *Concurrent, activityID
 	}
 	l.erChoice = &result
 }
-
-func (l *checkPeerReviewLoader) loadProgression(concurrent *Concurrent, activityID int) {
-	defer concurrent.Wg.Done()
-	var err error
-	l.progresstion, err = l.learningRepo.GetCorrectProgression(activityID)
-	if err != nil {
-		*concurrent.Err = err
-	}
-}
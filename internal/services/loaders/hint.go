@@ -2,7 +2,6 @@ package loaders
 
 import (
 	"database-camp/internal/models/entities/activity"
-	"database-camp/internal/models/entities/user"
 	"database-camp/internal/repositories"
 	"sync"
 )
@@ -13,7 +12,6 @@ type hintLoader struct {
 
 	activityHints []activity.Hint
 	userHints     []activity.UserHint
-	user          *user.User
 }
 
 func NewHintLoader(learningRepo repositories.LearningRepository, userRepo repositories.UserRepository) *hintLoader {
@@ -28,31 +26,17 @@ func (l *hintLoader) GetUserHintsDB() activity.UserHints {
 	return l.userHints
 }
 
-func (l *hintLoader) GetUser() *user.User {
-	return l.user
-}
-
 func (l *hintLoader) Load(userID int, activityID int) error {
 	var wg sync.WaitGroup
 	var err error
 	concurrent := Concurrent{Wg: &wg, Err: &err}
-	wg.Add(3)
+	wg.Add(2)
 	go l.loadActivityHints(&concurrent, activityID)
 	go l.loadUserHintsAsync(&concurrent, userID, activityID)
-	go l.loadUser(&concurrent, userID)
 	wg.Wait()
 	return err
 }
 
-func (l *hintLoader) loadUser(concurrent *Concurrent, userID int) {
-	defer concurrent.Wg.Done()
-	var err error
-	l.user, err = l.userRepo.GetUserByID(userID)
-	if err != nil {
-		*concurrent.Err = err
-	}
-}
-
 func (l *hintLoader) loadUserHintsAsync(concurrent *Concurrent, userID int, activityID int) {
 	defer concurrent.Wg.Done()
 	result, e := l.userRepo.GetUserHint(userID, activityID)
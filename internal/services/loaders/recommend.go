@@ -2,29 +2,27 @@ package loaders
 
 import (
 	"database-camp/internal/models/entities/content"
-	"database-camp/internal/models/entities/user"
 	"database-camp/internal/repositories"
 	"sync"
 )
 
 type recommendLoader struct {
-	userRepo     repositories.UserRepository
 	learningRepo repositories.LearningRepository
 
-	preTestResults user.PreTestResults
-	contentGroups  content.ContentGroups
+	masteries content.Masteries
+	overview  content.OverviewList
 }
 
-func NewRecommendLoader(learningRepo repositories.LearningRepository, userRepo repositories.UserRepository) *recommendLoader {
-	return &recommendLoader{learningRepo: learningRepo, userRepo: userRepo}
+func NewRecommendLoader(learningRepo repositories.LearningRepository) *recommendLoader {
+	return &recommendLoader{learningRepo: learningRepo}
 }
 
-func (l *recommendLoader) GetPreTestResults() user.PreTestResults {
-	return l.preTestResults
+func (l *recommendLoader) GetMasteries() content.Masteries {
+	return l.masteries
 }
 
-func (l *recommendLoader) GetContentGroups() content.ContentGroups {
-	return l.contentGroups
+func (l *recommendLoader) GetOverview() content.OverviewList {
+	return l.overview
 }
 
 func (l *recommendLoader) Load(userID int) error {
@@ -32,25 +30,25 @@ func (l *recommendLoader) Load(userID int) error {
 	var err error
 	concurrent := Concurrent{Wg: &wg, Err: &err}
 	wg.Add(2)
-	go l.loadPreTestResultsAsync(&concurrent, userID)
-	go l.loadContentGroupsAsync(&concurrent)
+	go l.loadMasteriesAsync(&concurrent, userID)
+	go l.loadOverviewAsync(&concurrent)
 	wg.Wait()
 	return err
 }
 
-func (l *recommendLoader) loadPreTestResultsAsync(concurrent *Concurrent, id int) {
+func (l *recommendLoader) loadMasteriesAsync(concurrent *Concurrent, userID int) {
 	defer concurrent.Wg.Done()
 	var err error
-	l.preTestResults, err = l.userRepo.GetPreTestResults(id)
+	l.masteries, err = l.learningRepo.GetMasteries(userID)
 	if err != nil {
 		*concurrent.Err = err
 	}
 }
 
-func (l *recommendLoader) loadContentGroupsAsync(concurrent *Concurrent) {
+func (l *recommendLoader) loadOverviewAsync(concurrent *Concurrent) {
 	defer concurrent.Wg.Done()
 	var err error
-	l.contentGroups, err = l.learningRepo.GetContentGroups()
+	l.overview, err = l.learningRepo.GetOverview()
 	if err != nil {
 		*concurrent.Err = err
 	}
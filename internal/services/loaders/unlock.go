@@ -0,0 +1,141 @@
+package loaders
+
+import (
+	"database-camp/internal/models/entities/badge"
+	"database-camp/internal/models/entities/content"
+	"database-camp/internal/models/entities/exam"
+	"database-camp/internal/repositories"
+	"sync"
+)
+
+type unlockLoader struct {
+	learningRepo repositories.LearningRepository
+	userRepo     repositories.UserRepository
+	examRepo     repositories.ExamRepository
+
+	prerequisites content.Prerequisites
+	overview      content.OverviewList
+	progressions  content.LearningProgressionList
+	contentGroups content.ContentGroups
+	examResults   []exam.ExamResult
+	userBadges    []badge.UserBadge
+
+	overviewLoaded     bool
+	progressionsLoaded bool
+}
+
+func NewUnlockLoader(
+	learningRepo repositories.LearningRepository,
+	userRepo repositories.UserRepository,
+	examRepo repositories.ExamRepository,
+) *unlockLoader {
+	return &unlockLoader{learningRepo: learningRepo, userRepo: userRepo, examRepo: examRepo}
+}
+
+// SetOverview gives the loader an overview the caller already loaded
+func (l *unlockLoader) SetOverview(overview content.OverviewList) {
+	l.overview = overview
+	l.overviewLoaded = true
+}
+
+// SetProgressions gives the loader progressions the caller already loaded
+func (l *unlockLoader) SetProgressions(progressions content.LearningProgressionList) {
+	l.progressions = progressions
+	l.progressionsLoaded = true
+}
+
+// GetLocks returns which contents and content groups the user has unlocked
+func (l *unlockLoader) GetLocks() content.Locks {
+	passedExamIDs := make([]int, 0)
+	for _, result := range l.examResults {
+		if result.IsPassed {
+			passedExamIDs = append(passedExamIDs, result.ExamID)
+		}
+	}
+
+	badgeIDs := make([]int, 0, len(l.userBadges))
+	for _, userBadge := range l.userBadges {
+		badgeIDs = append(badgeIDs, userBadge.BadgeID)
+	}
+
+	return content.NewLocks(l.prerequisites, l.overview, l.progressions, l.contentGroups, passedExamIDs, badgeIDs)
+}
+
+// Load loads the prerequisites first and then only what their rules need, a
+// course without prerequisites locks nothing and needs nothing else
+func (l *unlockLoader) Load(userID int) error {
+	var err error
+	l.prerequisites, err = l.learningRepo.GetPrerequisites()
+	if err != nil || len(l.prerequisites) == 0 {
+		return err
+	}
+
+	rules := l.prerequisites.GetRules()
+
+	var wg sync.WaitGroup
+	concurrent := Concurrent{Wg: &wg, Err: &err}
+	if !l.overviewLoaded {
+		wg.Add(1)
+		go l.loadOverviewAsync(&concurrent)
+	}
+	if rules[content.UNLOCK_RULE_COMPLETE_ACTIVITIES] && !l.progressionsLoaded {
+		wg.Add(1)
+		go l.loadProgressionsAsync(&concurrent, userID)
+	}
+	if rules[content.UNLOCK_RULE_PASS_MINI_EXAM] {
+		wg.Add(2)
+		go l.loadContentGroupsAsync(&concurrent)
+		go l.loadExamResultsAsync(&concurrent, userID)
+	}
+	if rules[content.UNLOCK_RULE_EARN_BADGE] {
+		wg.Add(1)
+		go l.loadUserBadgesAsync(&concurrent, userID)
+	}
+	wg.Wait()
+	return err
+}
+
+func (l *unlockLoader) loadOverviewAsync(concurrent *Concurrent) {
+	defer concurrent.Wg.Done()
+	var err error
+	l.overview, err = l.learningRepo.GetOverview()
+	if err != nil {
+		*concurrent.Err = err
+	}
+}
+
+func (l *unlockLoader) loadProgressionsAsync(concurrent *Concurrent, userID int) {
+	defer concurrent.Wg.Done()
+	var err error
+	l.progressions, err = l.userRepo.GetLearningProgression(userID)
+	if err != nil {
+		*concurrent.Err = err
+	}
+}
+
+func (l *unlockLoader) loadContentGroupsAsync(concurrent *Concurrent) {
+	defer concurrent.Wg.Done()
+	var err error
+	l.contentGroups, err = l.learningRepo.GetContentGroups()
+	if err != nil {
+		*concurrent.Err = err
+	}
+}
+
+func (l *unlockLoader) loadExamResultsAsync(concurrent *Concurrent, userID int) {
+	defer concurrent.Wg.Done()
+	var err error
+	l.examResults, err = l.examRepo.GetExamResults(userID)
+	if err != nil {
+		*concurrent.Err = err
+	}
+}
+
+func (l *unlockLoader) loadUserBadgesAsync(concurrent *Concurrent, userID int) {
+	defer concurrent.Wg.Done()
+	var err error
+	l.userBadges, err = l.userRepo.GetUserBadge(userID)
+	if err != nil {
+		*concurrent.Err = err
+	}
+}
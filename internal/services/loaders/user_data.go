@@ -0,0 +1,247 @@
+package loaders
+
+import (
+	"database-camp/internal/models/entities/activity"
+	"database-camp/internal/models/entities/badge"
+	"database-camp/internal/models/entities/content"
+	"database-camp/internal/models/entities/exam"
+	"database-camp/internal/models/entities/user"
+	"database-camp/internal/repositories"
+	"sync"
+)
+
+type userDataLoader struct {
+	userRepo    repositories.UserRepository
+	examRepo    repositories.ExamRepository
+	sessionRepo repositories.SessionRepository
+	privacyRepo repositories.PrivacyRepository
+
+	user                 *user.User
+	roles                []string
+	identities           []user.Identity
+	sessions             []user.Session
+	learningProgressions []content.LearningProgression
+	reviewSchedules      []content.ReviewSchedule
+	masteries            []content.Mastery
+	attachmentDownloads  []content.AttachmentDownload
+	userHints            []activity.UserHint
+	pointTransactions    []user.PointTransaction
+	userBadges           []badge.UserBadge
+	examResults          []exam.ExamResult
+	examResultActivities []exam.ResultActivity
+	erAnswers            []activity.ERAnswer
+}
+
+func NewUserDataLoader(
+	userRepo repositories.UserRepository,
+	examRepo repositories.ExamRepository,
+	sessionRepo repositories.SessionRepository,
+	privacyRepo repositories.PrivacyRepository,
+) *userDataLoader {
+	return &userDataLoader{userRepo: userRepo, examRepo: examRepo, sessionRepo: sessionRepo, privacyRepo: privacyRepo}
+}
+
+func (l *userDataLoader) GetUser() *user.User {
+	return l.user
+}
+
+func (l *userDataLoader) GetRoles() []string {
+	return l.roles
+}
+
+func (l *userDataLoader) GetIdentities() []user.Identity {
+	return l.identities
+}
+
+func (l *userDataLoader) GetSessions() []user.Session {
+	return l.sessions
+}
+
+func (l *userDataLoader) GetLearningProgressions() []content.LearningProgression {
+	return l.learningProgressions
+}
+
+func (l *userDataLoader) GetReviewSchedules() []content.ReviewSchedule {
+	return l.reviewSchedules
+}
+
+func (l *userDataLoader) GetMasteries() []content.Mastery {
+	return l.masteries
+}
+
+func (l *userDataLoader) GetAttachmentDownloads() []content.AttachmentDownload {
+	return l.attachmentDownloads
+}
+
+func (l *userDataLoader) GetUserHints() []activity.UserHint {
+	return l.userHints
+}
+
+func (l *userDataLoader) GetPointTransactions() []user.PointTransaction {
+	return l.pointTransactions
+}
+
+func (l *userDataLoader) GetUserBadges() []badge.UserBadge {
+	return l.userBadges
+}
+
+func (l *userDataLoader) GetExamResults() []exam.ExamResult {
+	return l.examResults
+}
+
+func (l *userDataLoader) GetExamResultActivities() []exam.ResultActivity {
+	return l.examResultActivities
+}
+
+func (l *userDataLoader) GetERAnswers() []activity.ERAnswer {
+	return l.erAnswers
+}
+
+func (l *userDataLoader) Load(userID int) error {
+	var wg sync.WaitGroup
+	var err error
+	concurrent := Concurrent{Wg: &wg, Err: &err}
+	wg.Add(14)
+	go l.loadUserAsync(&concurrent, userID)
+	go l.loadRolesAsync(&concurrent, userID)
+	go l.loadIdentitiesAsync(&concurrent, userID)
+	go l.loadSessionsAsync(&concurrent, userID)
+	go l.loadLearningProgressionsAsync(&concurrent, userID)
+	go l.loadReviewSchedulesAsync(&concurrent, userID)
+	go l.loadMasteriesAsync(&concurrent, userID)
+	go l.loadAttachmentDownloadsAsync(&concurrent, userID)
+	go l.loadUserHintsAsync(&concurrent, userID)
+	go l.loadPointTransactionsAsync(&concurrent, userID)
+	go l.loadUserBadgesAsync(&concurrent, userID)
+	go l.loadExamResultsAsync(&concurrent, userID)
+	go l.loadExamResultActivitiesAsync(&concurrent, userID)
+	go l.loadERAnswersAsync(&concurrent, userID)
+	wg.Wait()
+	return err
+}
+
+func (l *userDataLoader) loadUserAsync(concurrent *Concurrent, userID int) {
+	defer concurrent.Wg.Done()
+	var err error
+	l.user, err = l.userRepo.GetUserByID(userID)
+	if err != nil {
+		*concurrent.Err = err
+	}
+}
+
+func (l *userDataLoader) loadRolesAsync(concurrent *Concurrent, userID int) {
+	defer concurrent.Wg.Done()
+	var err error
+	l.roles, err = l.userRepo.GetUserRoles(userID)
+	if err != nil {
+		*concurrent.Err = err
+	}
+}
+
+func (l *userDataLoader) loadIdentitiesAsync(concurrent *Concurrent, userID int) {
+	defer concurrent.Wg.Done()
+	var err error
+	l.identities, err = l.privacyRepo.GetIdentities(userID)
+	if err != nil {
+		*concurrent.Err = err
+	}
+}
+
+func (l *userDataLoader) loadSessionsAsync(concurrent *Concurrent, userID int) {
+	defer concurrent.Wg.Done()
+	var err error
+	l.sessions, err = l.sessionRepo.GetUserSessions(userID)
+	if err != nil {
+		*concurrent.Err = err
+	}
+}
+
+func (l *userDataLoader) loadLearningProgressionsAsync(concurrent *Concurrent, userID int) {
+	defer concurrent.Wg.Done()
+	var err error
+	l.learningProgressions, err = l.privacyRepo.GetLearningProgressions(userID)
+	if err != nil {
+		*concurrent.Err = err
+	}
+}
+
+func (l *userDataLoader) loadReviewSchedulesAsync(concurrent *Concurrent, userID int) {
+	defer concurrent.Wg.Done()
+	var err error
+	l.reviewSchedules, err = l.privacyRepo.GetReviewSchedules(userID)
+	if err != nil {
+		*concurrent.Err = err
+	}
+}
+
+func (l *userDataLoader) loadMasteriesAsync(concurrent *Concurrent, userID int) {
+	defer concurrent.Wg.Done()
+	var err error
+	l.masteries, err = l.privacyRepo.GetMasteries(userID)
+	if err != nil {
+		*concurrent.Err = err
+	}
+}
+
+func (l *userDataLoader) loadAttachmentDownloadsAsync(concurrent *Concurrent, userID int) {
+	defer concurrent.Wg.Done()
+	var err error
+	l.attachmentDownloads, err = l.privacyRepo.GetAttachmentDownloads(userID)
+	if err != nil {
+		*concurrent.Err = err
+	}
+}
+
+func (l *userDataLoader) loadUserHintsAsync(concurrent *Concurrent, userID int) {
+	defer concurrent.Wg.Done()
+	var err error
+	l.userHints, err = l.privacyRepo.GetUserHints(userID)
+	if err != nil {
+		*concurrent.Err = err
+	}
+}
+
+func (l *userDataLoader) loadPointTransactionsAsync(concurrent *Concurrent, userID int) {
+	defer concurrent.Wg.Done()
+	var err error
+	l.pointTransactions, err = l.privacyRepo.GetPointTransactions(userID)
+	if err != nil {
+		*concurrent.Err = err
+	}
+}
+
+func (l *userDataLoader) loadUserBadgesAsync(concurrent *Concurrent, userID int) {
+	defer concurrent.Wg.Done()
+	var err error
+	l.userBadges, err = l.privacyRepo.GetUserBadges(userID)
+	if err != nil {
+		*concurrent.Err = err
+	}
+}
+
+func (l *userDataLoader) loadExamResultsAsync(concurrent *Concurrent, userID int) {
+	defer concurrent.Wg.Done()
+	var err error
+	l.examResults, err = l.examRepo.GetExamResults(userID)
+	if err != nil {
+		*concurrent.Err = err
+	}
+}
+
+func (l *userDataLoader) loadExamResultActivitiesAsync(concurrent *Concurrent, userID int) {
+	defer concurrent.Wg.Done()
+	var err error
+	l.examResultActivities, err = l.privacyRepo.GetExamResultActivities(userID)
+	if err != nil {
+		*concurrent.Err = err
+	}
+}
+
+func (l *userDataLoader) loadERAnswersAsync(concurrent *Concurrent, userID int) {
+	defer concurrent.Wg.Done()
+	var err error
+	l.erAnswers, err = l.privacyRepo.GetERAnswers(userID)
+	if err != nil {
+		*concurrent.Err = err
+	}
+}
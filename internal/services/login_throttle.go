@@ -0,0 +1,182 @@
+package services
+
+import (
+	"database-camp/internal/errs"
+	"database-camp/internal/infrastructure/mailer"
+	"database-camp/internal/logs"
+	"database-camp/internal/models/entities/user"
+	"database-camp/internal/models/request"
+	"database-camp/internal/models/response"
+	"database-camp/internal/utils"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Failed logins are counted per email and per client IP within a window. Past
+// a few failures every further failure doubles the delay before the next try,
+// and too many failures on an email lock the account until it expires or the
+// owner follows the unlock link sent by mail.
+const (
+	defaultLoginFailureWindowMinute = 15
+	defaultLoginEmailDelayAfter     = 3
+	defaultLoginIPDelayAfter        = 20
+	defaultLoginMaxDelaySecond      = 60
+	defaultLoginLockoutThreshold    = 10
+	defaultLoginLockoutMinute       = 30
+)
+
+func (s userService) checkLoginAllowed(email string, ip string) error {
+	locked, err := s.loginAttemptRepo.GetLoginLock(email)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return errs.ErrServiceUnavailableError
+	}
+
+	if locked > 0 {
+		return errs.WithRetryAfter(errs.ErrAccountLocked, locked)
+	}
+
+	var retryAfter time.Duration
+	for _, subject := range []string{email, ip} {
+		delay, err := s.loginAttemptRepo.GetLoginDelay(subject)
+		if err != nil {
+			logs.GetInstance().Error(err)
+			return errs.ErrServiceUnavailableError
+		}
+
+		if delay > retryAfter {
+			retryAfter = delay
+		}
+	}
+
+	if retryAfter > 0 {
+		return errs.WithRetryAfter(errs.ErrLoginThrottled, retryAfter)
+	}
+
+	return nil
+}
+
+// recordLoginFailure counts unknown emails too, so the response never tells
+// whether an account exists.
+func (s userService) recordLoginFailure(email string, ip string, _user *user.User) {
+	window := envDuration("LOGIN_FAILURE_WINDOW_MINUTE", defaultLoginFailureWindowMinute) * time.Minute
+
+	failures, err := s.loginAttemptRepo.IncrLoginFailure(email, window)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return
+	}
+
+	if failures >= envInt("LOGIN_LOCKOUT_THRESHOLD", defaultLoginLockoutThreshold) {
+		s.lockLogin(email, _user)
+	} else {
+		s.delayLogin(email, failures, envInt("LOGIN_EMAIL_DELAY_AFTER", defaultLoginEmailDelayAfter))
+	}
+
+	if ip == "" {
+		return
+	}
+
+	failures, err = s.loginAttemptRepo.IncrLoginFailure(ip, window)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return
+	}
+
+	s.delayLogin(ip, failures, envInt("LOGIN_IP_DELAY_AFTER", defaultLoginIPDelayAfter))
+}
+
+func (s userService) delayLogin(subject string, failures int, delayAfter int) {
+	if failures < delayAfter {
+		return
+	}
+
+	maxDelay := envDuration("LOGIN_MAX_DELAY_SECOND", defaultLoginMaxDelaySecond) * time.Second
+
+	delay := maxDelay
+	if shift := failures - delayAfter; shift < 16 {
+		delay = time.Second << uint(shift)
+	}
+
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	err := s.loginAttemptRepo.SetLoginDelay(subject, delay)
+	if err != nil {
+		logs.GetInstance().Error(err)
+	}
+}
+
+func (s userService) lockLogin(email string, _user *user.User) {
+	duration := envDuration("LOGIN_LOCKOUT_MINUTE", defaultLoginLockoutMinute) * time.Minute
+
+	err := s.loginAttemptRepo.SetLoginLock(email, duration)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return
+	}
+
+	if _user == nil || _user.ID == 0 {
+		return
+	}
+
+	unlockToken, err := utils.RandomToken()
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return
+	}
+
+	err = s.loginAttemptRepo.SetUnlockToken(utils.HashToken(unlockToken), _user.Email, duration)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return
+	}
+
+	link := os.Getenv("LOGIN_UNLOCK_URL") + "?token=" + unlockToken
+	mail := mailer.NewAccountLockedMail(_user.Email, _user.Name, link, duration)
+
+	go func() {
+		if err := s.mailer.Send(mail); err != nil {
+			logs.GetInstance().Error(err)
+		}
+	}()
+}
+
+func (s userService) UnlockLogin(request request.LoginUnlockRequest) (*response.MessageResponse, error) {
+	email, err := s.loginAttemptRepo.PopUnlockToken(utils.HashToken(request.Token))
+	if err != nil || email == "" {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUnlockTokenInvalid
+	}
+
+	err = s.loginAttemptRepo.DeleteLoginLock(email)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrServiceUnavailableError
+	}
+
+	response := response.MessageResponse{
+		ThMessage: response.ACCOUNT_UNLOCKED_TH,
+		EnMessage: response.ACCOUNT_UNLOCKED_EN,
+	}
+
+	return &response, nil
+}
+
+func envDuration(key string, defaultValue time.Duration) time.Duration {
+	value := utils.ParseDuration(os.Getenv(key))
+	if value <= 0 {
+		value = defaultValue
+	}
+	return value
+}
+
+func envInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
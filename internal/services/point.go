@@ -0,0 +1,103 @@
+package services
+
+import (
+	"database-camp/internal/errs"
+	"database-camp/internal/logs"
+	"database-camp/internal/models/entities/user"
+	"database-camp/internal/models/request"
+	"database-camp/internal/models/response"
+	"database-camp/internal/repositories"
+	"fmt"
+)
+
+type PointService interface {
+	GetHistory(userID int, request request.PointHistoryRequest) (*response.PointHistoryResponse, error)
+	AdjustPoint(adminID int, userID int, request request.PointAdjustmentRequest) (*user.PointTransaction, error)
+	Reconcile(dryRun bool) ([]user.PointDiscrepancy, error)
+}
+
+type pointService struct {
+	pointRepo repositories.PointRepository
+	userRepo  repositories.UserRepository
+}
+
+func NewPointService(pointRepo repositories.PointRepository, userRepo repositories.UserRepository) *pointService {
+	return &pointService{pointRepo: pointRepo, userRepo: userRepo}
+}
+
+func (s pointService) GetHistory(userID int, request request.PointHistoryRequest) (*response.PointHistoryResponse, error) {
+	_user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || _user.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUserNotFound
+	}
+
+	transactions, err := s.pointRepo.GetPointTransactions(userID, request.Limit, request.Offset)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	return &response.PointHistoryResponse{
+		Point:        _user.Point,
+		Transactions: transactions,
+	}, nil
+}
+
+/**
+ * Give points to or take points from a user, the admin is the reference of
+ * the transaction
+ *
+ * @param adminID 	admin making the adjustment
+ * @param userID 	user to adjust
+ * @param request 	amount and why
+ *
+ * @return the transaction of the adjustment
+ */
+func (s pointService) AdjustPoint(adminID int, userID int, request request.PointAdjustmentRequest) (*user.PointTransaction, error) {
+	_user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || _user.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUserNotFound
+	}
+
+	transaction, ok, err := s.pointRepo.AddPoint(user.PointTransaction{
+		UserID:      userID,
+		Amount:      request.Amount,
+		Reason:      user.POINT_REASON_ADJUSTMENT,
+		ReferenceID: &adminID,
+		Note:        request.Note,
+	})
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUpdateError
+	} else if !ok {
+		return nil, errs.ErrPointsNotEnough
+	}
+
+	return transaction, nil
+}
+
+/**
+ * Find the users whose point is not the sum of their ledger and set their
+ * point to it
+ *
+ * @param dryRun 	only find the users
+ *
+ * @return the users found
+ */
+func (s pointService) Reconcile(dryRun bool) ([]user.PointDiscrepancy, error) {
+	discrepancies, err := s.pointRepo.GetPointDiscrepancies()
+	if err != nil || dryRun {
+		return discrepancies, err
+	}
+
+	for _, discrepancy := range discrepancies {
+		err = s.pointRepo.ReconcilePoint(discrepancy.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("reconcile user %d: %w", discrepancy.UserID, err)
+		}
+	}
+
+	return discrepancies, nil
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"database-camp/internal/errs"
+	"database-camp/internal/logs"
+	"database-camp/internal/models/entities/user"
+	"database-camp/internal/models/request"
+	"database-camp/internal/models/response"
+	"database-camp/internal/repositories"
+	"database-camp/internal/services/loaders"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type PrivacyService interface {
+	ExportData(userID int, request request.DataExportRequest) (*response.FileResponse, error)
+	PurgeDueAccounts() error
+}
+
+type privacyService struct {
+	userRepo    repositories.UserRepository
+	examRepo    repositories.ExamRepository
+	sessionRepo repositories.SessionRepository
+	privacyRepo repositories.PrivacyRepository
+	avatarRepo  repositories.AvatarRepository
+}
+
+func NewPrivacyService(
+	userRepo repositories.UserRepository,
+	examRepo repositories.ExamRepository,
+	sessionRepo repositories.SessionRepository,
+	privacyRepo repositories.PrivacyRepository,
+	avatarRepo repositories.AvatarRepository,
+) *privacyService {
+	return &privacyService{
+		userRepo:    userRepo,
+		examRepo:    examRepo,
+		sessionRepo: sessionRepo,
+		privacyRepo: privacyRepo,
+		avatarRepo:  avatarRepo,
+	}
+}
+
+// ExportData returns everything held about the user, either as one JSON
+// document or as a ZIP archive with a JSON file per section.
+func (s privacyService) ExportData(userID int, request request.DataExportRequest) (*response.FileResponse, error) {
+	userDataLoader := loaders.NewUserDataLoader(s.userRepo, s.examRepo, s.sessionRepo, s.privacyRepo)
+
+	err := userDataLoader.Load(userID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	_user := userDataLoader.GetUser()
+	if _user == nil || _user.ID == 0 {
+		return nil, errs.ErrUserNotFound
+	}
+
+	data := response.DataExportResponse{
+		User: response.ExportedUser{
+			ID:                _user.ID,
+			Name:              _user.Name,
+			Email:             _user.Email,
+			Point:             _user.Point,
+			Bio:               _user.Bio,
+			Language:          _user.Language,
+			ProfileVisibility: _user.ProfileVisibility,
+			HideFromRanking:   _user.HideFromRanking,
+			Roles:             userDataLoader.GetRoles(),
+			VerifiedTimestamp: _user.VerifiedTimestamp,
+			DeletionTimestamp: _user.DeletionTimestamp,
+			CreatedTimestamp:  _user.CreatedTimestamp,
+			UpdatedTimestamp:  _user.UpdatedTimestamp,
+		},
+		Identities:           userDataLoader.GetIdentities(),
+		Sessions:             userDataLoader.GetSessions(),
+		LearningProgressions: userDataLoader.GetLearningProgressions(),
+		ReviewSchedules:      userDataLoader.GetReviewSchedules(),
+		Masteries:            userDataLoader.GetMasteries(),
+		AttachmentDownloads:  userDataLoader.GetAttachmentDownloads(),
+		UserHints:            userDataLoader.GetUserHints(),
+		PointTransactions:    userDataLoader.GetPointTransactions(),
+		UserBadges:           userDataLoader.GetUserBadges(),
+		ExamResults:          userDataLoader.GetExamResults(),
+		ExamResultActivities: userDataLoader.GetExamResultActivities(),
+		ERAnswers:            userDataLoader.GetERAnswers(),
+		ExportedTimestamp:    time.Now().Local(),
+	}
+
+	filename := fmt.Sprintf("database-camp-%d-%s", _user.ID, data.ExportedTimestamp.Format("20060102150405"))
+
+	var file *response.FileResponse
+	if request.IsZip() {
+		file, err = exportZip(filename, data)
+	} else {
+		file, err = exportJSON(filename, data)
+	}
+
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrInternalServerError
+	}
+
+	return file, nil
+}
+
+// PurgeDueAccounts hard deletes every account whose grace period is over.
+func (s privacyService) PurgeDueAccounts() error {
+	userIDs, err := s.privacyRepo.GetDueDeletions(time.Now().Local())
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		_user, err := s.userRepo.GetUserByID(userID)
+		if err != nil {
+			logs.GetInstance().Error(err)
+			continue
+		}
+
+		if _user.Avatar != "" {
+			err = s.avatarRepo.DeleteAvatar(user.AvatarObjectNames(_user.Avatar)...)
+			if err != nil {
+				logs.GetInstance().Error(err)
+				continue
+			}
+		}
+
+		err = s.sessionRepo.DeleteUserSessions(userID)
+		if err != nil {
+			logs.GetInstance().Error(err)
+			continue
+		}
+
+		err = s.privacyRepo.DeleteUser(userID)
+		if err != nil {
+			logs.GetInstance().Error(err)
+			continue
+		}
+
+		logs.GetInstance().Info(fmt.Sprintf("user %d deleted", userID))
+	}
+
+	return nil
+}
+
+func exportJSON(filename string, data response.DataExportResponse) (*response.FileResponse, error) {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.FileResponse{
+		Filename:    filename + ".json",
+		ContentType: "application/json",
+		Data:        content,
+	}, nil
+}
+
+func exportZip(filename string, data response.DataExportResponse) (*response.FileResponse, error) {
+	sections := []struct {
+		name  string
+		value interface{}
+	}{
+		{"user", data.User},
+		{"identities", data.Identities},
+		{"sessions", data.Sessions},
+		{"learning_progressions", data.LearningProgressions},
+		{"review_schedules", data.ReviewSchedules},
+		{"masteries", data.Masteries},
+		{"attachment_downloads", data.AttachmentDownloads},
+		{"user_hints", data.UserHints},
+		{"point_transactions", data.PointTransactions},
+		{"user_badges", data.UserBadges},
+		{"exam_results", data.ExamResults},
+		{"exam_result_activities", data.ExamResultActivities},
+		{"er_answers", data.ERAnswers},
+	}
+
+	buffer := new(bytes.Buffer)
+	archive := zip.NewWriter(buffer)
+
+	for _, section := range sections {
+		content, err := json.MarshalIndent(section.value, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		writer, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     filename + "/" + section.name + ".json",
+			Method:   zip.Deflate,
+			Modified: data.ExportedTimestamp,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = writer.Write(content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := archive.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.FileResponse{
+		Filename:    filename + ".zip",
+		ContentType: "application/zip",
+		Data:        buffer.Bytes(),
+	}, nil
+}
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"database-camp/internal/errs"
+	"database-camp/internal/infrastructure/cache"
+	"database-camp/internal/logs"
+	"database-camp/internal/models/entities/activity"
+	"database-camp/internal/models/entities/content"
+	"database-camp/internal/models/entities/exam"
+	"database-camp/internal/models/entities/review"
+	"database-camp/internal/models/request"
+	"database-camp/internal/models/response"
+	"database-camp/internal/repositories"
+	"database-camp/internal/utils"
+	"encoding/json"
+	"time"
+)
+
+// A regrade job that dies without finishing releases its activity after
+// REGRADE_LOCK_EXPIRATION, the job itself can be looked up for a day
+const (
+	REGRADE_LOCK_EXPIRATION = time.Hour
+	REGRADE_JOB_EXPIRATION  = time.Hour * 24
+)
+
+type ReviewService interface {
+	GetOwnAttempts(userID int, activityID int, request request.AttemptsRequest) (*response.AttemptsResponse, error)
+	GetAttempts(activityID int, request request.AttemptsRequest) (*response.AttemptsResponse, error)
+	GetExamResult(examResultID int) (*response.ExamResultOverviewResponse, error)
+	StartRegrade(activityID int) (*review.RegradeJob, error)
+	GetRegradeJob(jobID string) (*review.RegradeJob, error)
+}
+
+type reviewService struct {
+	reviewRepo   repositories.ReviewRepository
+	learningRepo repositories.LearningRepository
+	examRepo     repositories.ExamRepository
+
+	cache cache.Cache
+}
+
+func NewReviewService(
+	reviewRepo repositories.ReviewRepository,
+	learningRepo repositories.LearningRepository,
+	examRepo repositories.ExamRepository,
+	cache cache.Cache,
+) *reviewService {
+	return &reviewService{
+		reviewRepo:   reviewRepo,
+		learningRepo: learningRepo,
+		examRepo:     examRepo,
+		cache:        cache,
+	}
+}
+
+func (s reviewService) GetOwnAttempts(userID int, activityID int, request request.AttemptsRequest) (*response.AttemptsResponse, error) {
+	request.UserID = &userID
+
+	return s.GetAttempts(activityID, request)
+}
+
+func (s reviewService) GetAttempts(activityID int, request request.AttemptsRequest) (*response.AttemptsResponse, error) {
+	attempts, err := s.reviewRepo.GetAttempts(activityID, request.UserID, request.Limit, request.Offset)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	return &response.AttemptsResponse{
+		ActivityID: activityID,
+		Attempts:   attempts,
+	}, nil
+}
+
+func (s reviewService) GetExamResult(examResultID int) (*response.ExamResultOverviewResponse, error) {
+	examResult, err := s.reviewRepo.GetExamResult(examResultID)
+	if err != nil || examResult.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrExamNotFound
+	}
+
+	resultActivities, err := s.examRepo.GetActivitiesResult(examResultID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	return &response.ExamResultOverviewResponse{
+		ExamResultID:     examResult.ID,
+		ExamID:           examResult.ExamID,
+		ExamType:         examResult.ExamType,
+		ContentGroupName: examResult.ExamContentGroupName,
+		CreatedTimestamp: examResult.CreatedTimestamp,
+		Score:            examResult.Score,
+		IsPassed:         examResult.IsPassed,
+		ActivitiesResult: resultActivities,
+	}, nil
+}
+
+/**
+ * Start grading the stored answers to an activity again with the current
+ * grader and answer key, one job at a time per activity
+ *
+ * @param activityID 	activity to regrade
+ *
+ * @return the job, running in the background
+ */
+func (s reviewService) StartRegrade(activityID int) (*review.RegradeJob, error) {
+	_activity, err := s.learningRepo.GetActivity(activityID)
+	if err != nil || _activity.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrActivitiesNotFound
+	}
+
+	choices, err := s.learningRepo.GetActivityChoices(activityID, _activity.TypeID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	jobID, err := utils.RandomToken()
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrInternalServerError
+	}
+
+	lockKey := "reviewService::Regrade::" + utils.ParseString(activityID)
+
+	locked, err := s.cache.SetNX(lockKey, jobID, REGRADE_LOCK_EXPIRATION)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrServiceUnavailableError
+	} else if !locked {
+		return nil, errs.ErrRegradeJobRunning
+	}
+
+	job := review.RegradeJob{
+		ID:               jobID,
+		ActivityID:       activityID,
+		Status:           review.REGRADE_STATUS_RUNNING,
+		StartedTimestamp: time.Now().Local(),
+	}
+
+	if err = s.saveRegradeJob(job); err != nil {
+		logs.GetInstance().Error(err)
+		s.cache.DeleteIfEqual(lockKey, jobID)
+		return nil, errs.ErrServiceUnavailableError
+	}
+
+	go func() {
+		// A job that outlived its lock leaves the lock of the next job alone
+		defer s.cache.DeleteIfEqual(lockKey, jobID)
+
+		job.Status = review.REGRADE_STATUS_FINISHED
+		if err := s.regrade(&job, *_activity, choices); err != nil {
+			logs.GetInstance().Error(err)
+			job.Status = review.REGRADE_STATUS_FAILED
+		}
+
+		finishedTimestamp := time.Now().Local()
+		job.FinishedTimestamp = &finishedTimestamp
+
+		if err := s.saveRegradeJob(job); err != nil {
+			logs.GetInstance().Error(err)
+		}
+	}()
+
+	return &job, nil
+}
+
+func (s reviewService) GetRegradeJob(jobID string) (*review.RegradeJob, error) {
+	var job review.RegradeJob
+
+	data, err := s.cache.Get("reviewService::RegradeJob::" + jobID)
+	if err != nil || json.Unmarshal([]byte(data), &job) != nil {
+		return nil, errs.ErrRegradeJobNotFound
+	}
+
+	return &job, nil
+}
+
+func (s reviewService) saveRegradeJob(job review.RegradeJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	return s.cache.Set("reviewService::RegradeJob::"+job.ID, string(data), REGRADE_JOB_EXPIRATION)
+}
+
+// regrade grades the answers to the activity again and stores the grades
+// that changed, answers that cannot be read any more are skipped
+func (s reviewService) regrade(job *review.RegradeJob, _activity activity.Activity, choices activity.Choices) error {
+	// The answers to the peer activity are reviews, its type does not grade them
+	if _activity.ID != activity.PEER_ACTIVITY_ID {
+		progressions, err := s.reviewRepo.GetSubmittedProgressions(_activity.ID)
+		if err != nil {
+			return err
+		}
+
+		changed := make([]content.LearningProgression, 0)
+		for _, progression := range progressions {
+			grade, ok := s.gradeStoredAnswer(job, progression.Answer, progression.AnswerVersion, _activity.TypeID, choices)
+			if !ok {
+				continue
+			}
+
+			point := grade.Point(_activity.Point)
+			if point != progression.Point || grade.IsCorrect != progression.IsCorrect {
+				progression.IsCorrect, progression.Point = grade.IsCorrect, point
+				changed = append(changed, progression)
+			}
+		}
+
+		if len(changed) > 0 {
+			if err = s.reviewRepo.RegradeProgressions(_activity.ID, changed); err != nil {
+				return err
+			}
+		}
+		job.Changed += len(changed)
+	}
+
+	resultActivities, err := s.reviewRepo.GetSubmittedResultActivities(_activity.ID)
+	if err != nil {
+		return err
+	}
+
+	changed := make([]exam.ResultActivity, 0)
+	for _, resultActivity := range resultActivities {
+		grade, ok := s.gradeStoredAnswer(job, resultActivity.Answer, resultActivity.AnswerVersion, _activity.TypeID, choices)
+		if !ok {
+			continue
+		}
+
+		if score := grade.Point(_activity.Point); score != resultActivity.Score {
+			resultActivity.Score = score
+			changed = append(changed, resultActivity)
+		}
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	if err = s.reviewRepo.RegradeResultActivities(_activity.ID, changed); err != nil {
+		return err
+	}
+	job.Changed += len(changed)
+
+	for _, resultActivity := range changed {
+		examResult, err := s.reviewRepo.GetExamResult(resultActivity.ExamResultID)
+		if err != nil {
+			logs.GetInstance().Error(err)
+			continue
+		}
+
+		s.cache.Delete("examService::GetExamResult::" + utils.ParseString(examResult.UserID) + "::" + utils.ParseString(examResult.ID))
+	}
+
+	return nil
+}
+
+func (s reviewService) gradeStoredAnswer(job *review.RegradeJob, answer json.RawMessage, version *int, activityTypeID int, choices activity.Choices) (*activity.Grade, bool) {
+	if version == nil {
+		job.Skipped++
+		return nil, false
+	}
+
+	grade, err := activity.GradeStoredAnswer(answer, *version, activityTypeID, choices)
+	if err != nil {
+		job.Skipped++
+		return nil, false
+	}
+
+	job.Regraded++
+	return grade, true
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"database-camp/internal/errs"
+	"database-camp/internal/infrastructure/mailer"
+	"database-camp/internal/infrastructure/oidc"
 	"database-camp/internal/logs"
 	"database-camp/internal/middleware/jwt"
 	"database-camp/internal/models/entities/badge"
@@ -11,27 +13,77 @@ import (
 	"database-camp/internal/repositories"
 	"database-camp/internal/services/loaders"
 	"database-camp/internal/utils"
+	"os"
+	"strings"
 	"time"
 )
 
+const (
+	defaultPasswordResetExpireMinute     = 30
+	defaultEmailVerificationExpireHour   = 24
+	defaultEmailVerificationResendSecond = 60
+	defaultAccountDeletionGraceDay       = 30
+	oidcStateExpire                      = 10 * time.Minute
+)
+
 type UserService interface {
-	Register(request request.UserRequest) (*response.UserResponse, error)
-	Login(request request.UserRequest) (*response.UserResponse, error)
-	GetProfile(userID int) (*response.GetProfileResponse, error)
-	EditProfile(userID int, request request.UserRequest) (*response.EditProfileResponse, error)
+	Register(request request.UserRequest, client request.Client) (*response.UserResponse, error)
+	Login(request request.UserRequest, client request.Client) (*response.UserResponse, error)
+	RefreshToken(request request.RefreshTokenRequest) (*response.TokenResponse, error)
+	GetSessions(userID int, currentSessionID int) (*response.SessionsResponse, error)
+	RevokeSession(userID int, sessionID int) (*response.RevokeSessionResponse, error)
+	GetProfile(viewerID int, userID int) (*response.GetProfileResponse, error)
+	EditProfile(userID int, request request.EditProfileRequest) (*response.EditProfileResponse, error)
 	GetRanking(id int) (*response.RankingResponse, error)
+	RequestPasswordReset(request request.PasswordResetRequest) (*response.MessageResponse, error)
+	ConfirmPasswordReset(request request.PasswordResetConfirmRequest) (*response.MessageResponse, error)
+	VerifyEmail(request request.EmailVerificationRequest) (*response.MessageResponse, error)
+	ResendEmailVerification(userID int) (*response.MessageResponse, error)
+	GetUserRoles(userID int) (*response.UserRolesResponse, error)
+	SetUserRoles(userID int, request request.UserRolesRequest) (*response.UserRolesResponse, error)
+	OIDCLogin(providerName string) (*response.OIDCLoginResponse, error)
+	OIDCCallback(providerName string, request request.OIDCCallbackRequest, client request.Client) (*response.UserResponse, error)
+	UnlockLogin(request request.LoginUnlockRequest) (*response.MessageResponse, error)
+	ChangePassword(userID int, sessionID int, request request.ChangePasswordRequest) (*response.MessageResponse, error)
+	DeleteAccount(userID int, request request.AccountDeletionRequest) (*response.AccountDeletionResponse, error)
+	UploadAvatar(userID int, request request.AvatarRequest) (*response.AvatarResponse, error)
+	DeleteAvatar(userID int) (*response.MessageResponse, error)
 }
 
 type userService struct {
-	userRepo     repositories.UserRepository
-	learningRepo repositories.LearningRepository
+	userRepo         repositories.UserRepository
+	learningRepo     repositories.LearningRepository
+	sessionRepo      repositories.SessionRepository
+	loginAttemptRepo repositories.LoginAttemptRepository
+	avatarRepo       repositories.AvatarRepository
+	jwt              jwt.Jwt
+	mailer           mailer.Mailer
+	providers        oidc.Providers
 }
 
-func NewUserService(userRepo repositories.UserRepository, learningRepo repositories.LearningRepository) *userService {
-	return &userService{userRepo: userRepo, learningRepo: learningRepo}
+func NewUserService(
+	userRepo repositories.UserRepository,
+	learningRepo repositories.LearningRepository,
+	sessionRepo repositories.SessionRepository,
+	loginAttemptRepo repositories.LoginAttemptRepository,
+	avatarRepo repositories.AvatarRepository,
+	jwt jwt.Jwt,
+	mailer mailer.Mailer,
+	providers oidc.Providers,
+) *userService {
+	return &userService{
+		userRepo:         userRepo,
+		learningRepo:     learningRepo,
+		sessionRepo:      sessionRepo,
+		loginAttemptRepo: loginAttemptRepo,
+		avatarRepo:       avatarRepo,
+		jwt:              jwt,
+		mailer:           mailer,
+		providers:        providers,
+	}
 }
 
-func (s userService) Register(request request.UserRequest) (*response.UserResponse, error) {
+func (s userService) Register(request request.UserRequest, client request.Client) (*response.UserResponse, error) {
 	user, err := s.userRepo.InsertUser(user.User{
 		Name:             request.Name,
 		Email:            request.Email,
@@ -51,20 +103,25 @@ func (s userService) Register(request request.UserRequest) (*response.UserRespon
 		}
 	}
 
-	jwt := jwt.New(s.userRepo)
-
-	token, err := jwt.Sign(user.ID)
+	token, err := s.jwt.Sign(user.ID, client)
 	if err != nil {
 		logs.GetInstance().Error(err)
 		return nil, errs.ErrInternalServerError
 	}
 
+	err = s.sendEmailVerification(*user)
+	if err != nil {
+		logs.GetInstance().Error(err)
+	}
+
 	response := response.UserResponse{
 		ID:               user.ID,
 		Name:             user.Name,
 		Email:            user.Email,
 		Point:            user.Point,
-		AccessToken:      token,
+		IsVerified:       user.IsVerified(),
+		AccessToken:      token.AccessToken,
+		RefreshToken:     token.RefreshToken,
 		CreatedTimestamp: user.CreatedTimestamp,
 		UpdatedTimestamp: user.UpdatedTimestamp,
 	}
@@ -72,19 +129,31 @@ func (s userService) Register(request request.UserRequest) (*response.UserRespon
 	return &response, nil
 }
 
-func (s userService) Login(request request.UserRequest) (*response.UserResponse, error) {
+func (s userService) Login(request request.UserRequest, client request.Client) (*response.UserResponse, error) {
+	err := s.checkLoginAllowed(request.Email, client.IPAddress)
+	if err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.GetUserByEmail(request.Email)
 
 	correctPassword := utils.ComparePasswords(user.Password, request.Password)
 
 	if err != nil || !correctPassword {
 		logs.GetInstance().Error(err)
+		s.recordLoginFailure(request.Email, client.IPAddress, user)
 		return nil, errs.ErrEmailOrPasswordNotCorrect
 	}
 
-	jwt := jwt.New(s.userRepo)
+	err = s.loginAttemptRepo.ResetLoginFailure(request.Email)
+	if err != nil {
+		logs.GetInstance().Error(err)
+	}
+
+	s.rehashPassword(user.ID, user.Password, request.Password)
+	s.cancelDeletion(user)
 
-	token, err := jwt.Sign(user.ID)
+	token, err := s.jwt.Sign(user.ID, client)
 	if err != nil {
 		logs.GetInstance().Error(err)
 		return nil, errs.ErrInternalServerError
@@ -95,7 +164,9 @@ func (s userService) Login(request request.UserRequest) (*response.UserResponse,
 		Name:             user.Name,
 		Email:            user.Email,
 		Point:            user.Point,
-		AccessToken:      token,
+		IsVerified:       user.IsVerified(),
+		AccessToken:      token.AccessToken,
+		RefreshToken:     token.RefreshToken,
 		CreatedTimestamp: user.CreatedTimestamp,
 		UpdatedTimestamp: user.UpdatedTimestamp,
 	}
@@ -103,11 +174,13 @@ func (s userService) Login(request request.UserRequest) (*response.UserResponse,
 	return &response, nil
 }
 
-func (s userService) GetProfile(id int) (*response.GetProfileResponse, error) {
+// GetProfile hides a profile the viewer may not see as if it did not exist,
+// viewerID is zero for a visitor who is not logged in.
+func (s userService) GetProfile(viewerID int, userID int) (*response.GetProfileResponse, error) {
 
 	loader := loaders.NewProfileLoader(s.learningRepo, s.userRepo)
 
-	err := loader.Load(id)
+	err := loader.Load(userID)
 	if err != nil {
 		logs.GetInstance().Error(err)
 		return nil, errs.ErrContentNotFound
@@ -119,14 +192,30 @@ func (s userService) GetProfile(id int) (*response.GetProfileResponse, error) {
 	profile := loader.GetProfile()
 	userBadges := loader.GetUserBadges()
 
+	if profile == nil || profile.ID == 0 || !profile.IsVisibleTo(viewerID) {
+		return nil, errs.ErrProfileNotFound
+	}
+
 	spiderDataset.FillContentGroups(contentGroups)
 
 	_badges := badge.NewBadges(badges, userBadges)
 
+	var privacy *response.PrivacyResponse
+	if viewerID == profile.ID {
+		privacy = &response.PrivacyResponse{
+			ProfileVisibility: profile.ProfileVisibility,
+			HideFromRanking:   profile.HideFromRanking,
+		}
+	}
+
 	response := response.GetProfileResponse{
 		ID:               profile.ID,
 		Name:             profile.Name,
 		Point:            profile.Point,
+		Avatar:           s.avatarLinks(profile.Avatar),
+		Bio:              profile.Bio,
+		Language:         profile.Language,
+		Privacy:          privacy,
 		ActivityCount:    profile.ActivityCount,
 		Badges:           _badges,
 		SpiderDataset:    spiderDataset,
@@ -136,35 +225,717 @@ func (s userService) GetProfile(id int) (*response.GetProfileResponse, error) {
 	return &response, nil
 }
 
-func (s userService) EditProfile(userID int, request request.UserRequest) (*response.EditProfileResponse, error) {
-	err := s.userRepo.UpdatesByID(userID, map[string]interface{}{"name": request.Name})
+func (s userService) EditProfile(userID int, request request.EditProfileRequest) (*response.EditProfileResponse, error) {
+	updateData := map[string]interface{}{"updated_timestamp": time.Now().Local()}
+
+	if request.Name != nil {
+		updateData["name"] = strings.TrimSpace(*request.Name)
+	}
+
+	if request.Bio != nil {
+		updateData["bio"] = strings.TrimSpace(*request.Bio)
+	}
+
+	if request.Language != nil {
+		updateData["language"] = *request.Language
+	}
+
+	if request.ProfileVisibility != nil {
+		updateData["profile_visibility"] = *request.ProfileVisibility
+	}
+
+	if request.HideFromRanking != nil {
+		updateData["hide_from_ranking"] = *request.HideFromRanking
+	}
+
+	err := s.userRepo.UpdatesByID(userID, updateData)
 	if err != nil {
 		logs.GetInstance().Error(err)
 		return nil, errs.ErrUpdateError
 	}
 
-	response := response.EditProfileResponse{UpdatedName: request.Name}
+	if request.Name != nil || request.HideFromRanking != nil {
+		s.clearRankingCache(userID)
+	}
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || user.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUserNotFound
+	}
+
+	response := response.EditProfileResponse{
+		UpdatedName:     user.Name,
+		UpdatedBio:      user.Bio,
+		UpdatedLanguage: user.Language,
+		UpdatedPrivacy: response.PrivacyResponse{
+			ProfileVisibility: user.ProfileVisibility,
+			HideFromRanking:   user.HideFromRanking,
+		},
+	}
 
 	return &response, nil
 }
 
 func (s userService) GetRanking(userID int) (*response.RankingResponse, error) {
-	userRankingDB, err := s.userRepo.GetPointRanking(userID)
-	if err != nil || userRankingDB == nil {
+	_user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || _user.ID == 0 {
 		logs.GetInstance().Error(err)
 		return nil, errs.ErrUserNotFound
 	}
 
+	if !_user.IsVerified() {
+		return nil, errs.ErrEmailNotVerified
+	}
+
+	// A user hidden from the ranking still sees the leaderboard, unranked
+	userRankingDB := &user.Ranking{ID: _user.ID, Name: _user.Name, Point: _user.Point, Avatar: _user.Avatar}
+	if !_user.HideFromRanking {
+		userRankingDB, err = s.userRepo.GetPointRanking(userID)
+		if err != nil || userRankingDB == nil {
+			logs.GetInstance().Error(err)
+			return nil, errs.ErrUserNotFound
+		}
+	}
+
 	leaderBoardDB, err := s.userRepo.GetRankingLeaderBoard()
 	if err != nil || leaderBoardDB == nil {
 		logs.GetInstance().Error(err)
 		return nil, errs.ErrLeaderBoardNotFound
 	}
 
+	leaderBoard := make([]response.RankingUserResponse, 0, len(leaderBoardDB))
+	for _, ranking := range leaderBoardDB {
+		leaderBoard = append(leaderBoard, s.rankingUser(ranking))
+	}
+
 	response := response.RankingResponse{
-		UserRanking: *userRankingDB,
-		LeaderBoard: leaderBoardDB,
+		UserRanking: s.rankingUser(*userRankingDB),
+		LeaderBoard: leaderBoard,
+	}
+
+	return &response, nil
+}
+
+func (s userService) RefreshToken(request request.RefreshTokenRequest) (*response.TokenResponse, error) {
+	token, err := s.jwt.Refresh(request.RefreshToken)
+	if err != nil {
+		logs.GetInstance().Error(err)
+
+		if err == errs.ErrRefreshTokenInvalid {
+			return nil, err
+		} else {
+			return nil, errs.ErrInternalServerError
+		}
+	}
+
+	response := response.TokenResponse{
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+	}
+
+	return &response, nil
+}
+
+func (s userService) GetSessions(userID int, currentSessionID int) (*response.SessionsResponse, error) {
+	sessions, err := s.sessionRepo.GetUserSessions(userID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	sessionsResponse := make([]response.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		if !s.sessionRepo.IsSessionActive(userID, session.ID) {
+			continue
+		}
+
+		sessionsResponse = append(sessionsResponse, response.SessionResponse{
+			Session:   session,
+			IsCurrent: session.ID == currentSessionID,
+		})
+	}
+
+	response := response.SessionsResponse{
+		Sessions: sessionsResponse,
+	}
+
+	return &response, nil
+}
+
+func (s userService) RevokeSession(userID int, sessionID int) (*response.RevokeSessionResponse, error) {
+	session, err := s.sessionRepo.GetSession(sessionID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	if session.UserID != userID {
+		return nil, errs.ErrSessionNotFound
+	}
+
+	err = s.sessionRepo.DeleteSession(userID, sessionID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUpdateError
+	}
+
+	response := response.RevokeSessionResponse{
+		SessionID: sessionID,
+	}
+
+	return &response, nil
+}
+
+func (s userService) RequestPasswordReset(request request.PasswordResetRequest) (*response.MessageResponse, error) {
+	response := response.MessageResponse{
+		ThMessage: response.PASSWORD_RESET_REQUESTED_TH,
+		EnMessage: response.PASSWORD_RESET_REQUESTED_EN,
+	}
+
+	user, err := s.userRepo.GetUserByEmail(request.Email)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	if user.ID == 0 {
+		return &response, nil
+	}
+
+	resetToken, err := utils.RandomToken()
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrInternalServerError
+	}
+
+	expiration := passwordResetExpire()
+
+	err = s.userRepo.SetPasswordResetToken(utils.HashToken(resetToken), user.ID, expiration)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrInsertError
+	}
+
+	link := os.Getenv("PASSWORD_RESET_URL") + "?token=" + resetToken
+	mail := mailer.NewPasswordResetMail(user.Email, user.Name, link, expiration)
+
+	go func() {
+		if err := s.mailer.Send(mail); err != nil {
+			logs.GetInstance().Error(err)
+		}
+	}()
+
+	return &response, nil
+}
+
+func (s userService) ConfirmPasswordReset(request request.PasswordResetConfirmRequest) (*response.MessageResponse, error) {
+	userID, err := s.userRepo.PopPasswordResetToken(utils.HashToken(request.Token))
+	if err != nil || userID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrPasswordResetTokenInvalid
+	}
+
+	err = s.userRepo.UpdatesByID(userID, map[string]interface{}{
+		"password":          utils.HashAndSalt(request.Password),
+		"updated_timestamp": time.Now().Local(),
+	})
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUpdateError
+	}
+
+	err = s.sessionRepo.DeleteUserSessions(userID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+	}
+
+	response := response.MessageResponse{
+		ThMessage: response.PASSWORD_RESET_TH,
+		EnMessage: response.PASSWORD_RESET_EN,
+	}
+
+	return &response, nil
+}
+
+func passwordResetExpire() time.Duration {
+	minute := utils.ParseDuration(os.Getenv("PASSWORD_RESET_EXPIRE_MINUTE"))
+	if minute <= 0 {
+		minute = defaultPasswordResetExpireMinute
+	}
+	return time.Minute * minute
+}
+
+func (s userService) VerifyEmail(request request.EmailVerificationRequest) (*response.MessageResponse, error) {
+	verification, err := s.jwt.ParseEmailVerification(request.Token)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrEmailVerificationTokenInvalid
+	}
+
+	verified, err := s.userRepo.VerifyEmail(verification.UserID, verification.Email)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUpdateError
+	}
+
+	if !verified {
+		user, err := s.userRepo.GetUserByID(verification.UserID)
+		if err != nil {
+			logs.GetInstance().Error(err)
+			return nil, errs.ErrLoadError
+		}
+
+		if user.IsVerified() && user.Email == verification.Email {
+			return nil, errs.ErrEmailAlreadyVerified
+		}
+
+		return nil, errs.ErrEmailVerificationTokenInvalid
+	}
+
+	response := response.MessageResponse{
+		ThMessage: response.EMAIL_VERIFIED_TH,
+		EnMessage: response.EMAIL_VERIFIED_EN,
+	}
+
+	return &response, nil
+}
+
+func (s userService) ResendEmailVerification(userID int) (*response.MessageResponse, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || user.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.IsVerified() {
+		return nil, errs.ErrEmailAlreadyVerified
+	}
+
+	allowed, err := s.userRepo.AllowEmailVerificationResend(userID, emailVerificationResendInterval())
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrServiceUnavailableError
+	}
+
+	if !allowed {
+		return nil, errs.ErrEmailVerificationThrottled
+	}
+
+	err = s.sendEmailVerification(*user)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrInternalServerError
+	}
+
+	response := response.MessageResponse{
+		ThMessage: response.EMAIL_VERIFICATION_SENT_TH,
+		EnMessage: response.EMAIL_VERIFICATION_SENT_EN,
+	}
+
+	return &response, nil
+}
+
+func (s userService) GetUserRoles(userID int) (*response.UserRolesResponse, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || user.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUserNotFound
+	}
+
+	roles, err := s.userRepo.GetUserRoles(userID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	response := response.UserRolesResponse{
+		UserID: userID,
+		Roles:  roles,
+	}
+
+	return &response, nil
+}
+
+// SetUserRoles replaces every role of the user. The sessions of the user are
+// revoked, so access tokens carrying the old roles stop working and the user
+// signs in again for the new ones.
+func (s userService) SetUserRoles(userID int, request request.UserRolesRequest) (*response.UserRolesResponse, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || user.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUserNotFound
+	}
+
+	err = s.userRepo.SetUserRoles(userID, request.Roles)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUpdateError
+	}
+
+	err = s.sessionRepo.DeleteUserSessions(userID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUpdateError
+	}
+
+	return s.GetUserRoles(userID)
+}
+
+func (s userService) OIDCLogin(providerName string) (*response.OIDCLoginResponse, error) {
+	provider, err := s.providers.Get(providerName)
+	if err != nil {
+		return nil, errs.ErrOIDCProviderNotFound
+	}
+
+	oidcState := user.OIDCState{Provider: provider.Name()}
+
+	state, err := utils.RandomToken()
+	if err == nil {
+		oidcState.Nonce, err = utils.RandomToken()
+	}
+	if err == nil {
+		oidcState.CodeVerifier, err = utils.RandomToken()
+	}
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrInternalServerError
+	}
+
+	authURL, err := provider.AuthCodeURL(state, oidcState.Nonce, oidcState.CodeVerifier)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrServiceUnavailableError
+	}
+
+	err = s.userRepo.SetOIDCState(state, oidcState, oidcStateExpire)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrInsertError
+	}
+
+	response := response.OIDCLoginResponse{
+		AuthURL: authURL,
+		State:   state,
+	}
+
+	return &response, nil
+}
+
+// OIDCCallback signs in the user linked to the provider account. An account
+// seen for the first time is linked to the user with the same email, or a new
+// user is created, but only when the provider has verified the email. An
+// unverified user loses its password and sessions when it is linked.
+func (s userService) OIDCCallback(providerName string, request request.OIDCCallbackRequest, client request.Client) (*response.UserResponse, error) {
+	provider, err := s.providers.Get(providerName)
+	if err != nil {
+		return nil, errs.ErrOIDCProviderNotFound
+	}
+
+	oidcState, err := s.userRepo.PopOIDCState(request.State)
+	if err != nil || oidcState.Provider != provider.Name() {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrOIDCStateInvalid
+	}
+
+	idToken, err := provider.Exchange(request.Code, oidcState.CodeVerifier, oidcState.Nonce)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrOIDCLoginFailed
+	}
+
+	user, err := s.oidcUser(provider.Name(), idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cancelDeletion(user)
+
+	token, err := s.jwt.Sign(user.ID, client)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrInternalServerError
+	}
+
+	response := response.UserResponse{
+		ID:               user.ID,
+		Name:             user.Name,
+		Email:            user.Email,
+		Point:            user.Point,
+		IsVerified:       user.IsVerified(),
+		AccessToken:      token.AccessToken,
+		RefreshToken:     token.RefreshToken,
+		CreatedTimestamp: user.CreatedTimestamp,
+		UpdatedTimestamp: user.UpdatedTimestamp,
+	}
+
+	return &response, nil
+}
+
+func (s userService) oidcUser(providerName string, idToken *oidc.IDToken) (*user.User, error) {
+	identity, err := s.userRepo.GetIdentity(providerName, idToken.Subject)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	if identity.UserID != 0 {
+		_user, err := s.userRepo.GetUserByID(identity.UserID)
+		if err != nil || _user.ID == 0 {
+			logs.GetInstance().Error(err)
+			return nil, errs.ErrUserNotFound
+		}
+		return _user, nil
+	}
+
+	if !idToken.EmailVerified || !utils.IsEmailValid(idToken.Email) {
+		return nil, errs.ErrOIDCEmailNotVerified
+	}
+
+	_user, err := s.userRepo.GetUserByEmail(idToken.Email)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrLoadError
+	}
+
+	now := time.Now().Local()
+
+	if _user.ID == 0 {
+		name := idToken.Name
+		if name == "" {
+			name = strings.Split(idToken.Email, "@")[0]
+		}
+
+		// Users created here have no password until they reset one
+		_user, err = s.userRepo.InsertUser(user.User{
+			Name:              name,
+			Email:             idToken.Email,
+			Point:             0,
+			VerifiedTimestamp: &now,
+			CreatedTimestamp:  now,
+			UpdatedTimestamp:  now,
+		})
+		if err != nil {
+			logs.GetInstance().Error(err)
+			return nil, errs.ErrInsertError
+		}
+	} else if !_user.IsVerified() {
+		// Anyone can register an email they do not own, so the password and
+		// sessions of an unverified account are dropped before the owner of
+		// the email takes it over
+		err = s.userRepo.UpdatesByID(_user.ID, map[string]interface{}{
+			"password":          "",
+			"updated_timestamp": now,
+		})
+		if err != nil {
+			logs.GetInstance().Error(err)
+			return nil, errs.ErrUpdateError
+		}
+		_user.Password = ""
+
+		err = s.sessionRepo.DeleteUserSessions(_user.ID)
+		if err != nil {
+			logs.GetInstance().Error(err)
+			return nil, errs.ErrUpdateError
+		}
+
+		_, err = s.userRepo.VerifyEmail(_user.ID, _user.Email)
+		if err != nil {
+			logs.GetInstance().Error(err)
+			return nil, errs.ErrUpdateError
+		}
+		_user.VerifiedTimestamp = &now
+	}
+
+	_, err = s.userRepo.InsertIdentity(user.Identity{
+		Provider:         providerName,
+		Subject:          idToken.Subject,
+		UserID:           _user.ID,
+		Email:            idToken.Email,
+		CreatedTimestamp: now,
+	})
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrInsertError
+	}
+
+	return _user, nil
+}
+
+// ChangePassword signs out every other session of the user. Wrong old passwords
+// count as failed logins, so a stolen token cannot be used to guess them.
+func (s userService) ChangePassword(userID int, sessionID int, request request.ChangePasswordRequest) (*response.MessageResponse, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || user.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUserNotFound
+	}
+
+	err = s.checkLoginAllowed(user.Email, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if !utils.ComparePasswords(user.Password, request.OldPassword) {
+		s.recordLoginFailure(user.Email, "", user)
+		return nil, errs.ErrPasswordNotCorrect
+	}
+
+	err = s.userRepo.UpdatesByID(userID, map[string]interface{}{
+		"password":          utils.HashAndSalt(request.NewPassword),
+		"updated_timestamp": time.Now().Local(),
+	})
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUpdateError
+	}
+
+	sessions, err := s.sessionRepo.GetUserSessions(userID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+	}
+
+	for _, session := range sessions {
+		if session.ID == sessionID {
+			continue
+		}
+
+		err = s.sessionRepo.DeleteSession(userID, session.ID)
+		if err != nil {
+			logs.GetInstance().Error(err)
+		}
+	}
+
+	response := response.MessageResponse{
+		ThMessage: response.PASSWORD_CHANGED_TH,
+		EnMessage: response.PASSWORD_CHANGED_EN,
 	}
 
 	return &response, nil
 }
+
+// DeleteAccount schedules the account for deletion after a grace period and
+// signs out every session. Logging in again before then cancels the deletion.
+func (s userService) DeleteAccount(userID int, request request.AccountDeletionRequest) (*response.AccountDeletionResponse, error) {
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil || user.ID == 0 {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUserNotFound
+	}
+
+	if user.IsDeletionScheduled() {
+		return nil, errs.ErrAccountDeletionScheduled
+	}
+
+	// Accounts created through OpenID Connect may have no password to confirm
+	if user.Password != "" {
+		err = s.checkLoginAllowed(user.Email, "")
+		if err != nil {
+			return nil, err
+		}
+
+		if !utils.ComparePasswords(user.Password, request.Password) {
+			s.recordLoginFailure(user.Email, "", user)
+			return nil, errs.ErrPasswordNotCorrect
+		}
+	}
+
+	day := utils.ParseDuration(os.Getenv("ACCOUNT_DELETION_GRACE_DAY"))
+	if day <= 0 {
+		day = defaultAccountDeletionGraceDay
+	}
+
+	deletionTimestamp := time.Now().Local().Add(24 * time.Hour * day)
+
+	err = s.userRepo.SetDeletionTimestamp(userID, &deletionTimestamp)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return nil, errs.ErrUpdateError
+	}
+
+	err = s.sessionRepo.DeleteUserSessions(userID)
+	if err != nil {
+		logs.GetInstance().Error(err)
+	}
+
+	mail := mailer.NewAccountDeletionMail(user.Email, user.Name, deletionTimestamp)
+
+	go func() {
+		if err := s.mailer.Send(mail); err != nil {
+			logs.GetInstance().Error(err)
+		}
+	}()
+
+	response := response.AccountDeletionResponse{
+		ThMessage:         response.ACCOUNT_DELETION_SCHEDULED_TH,
+		EnMessage:         response.ACCOUNT_DELETION_SCHEDULED_EN,
+		DeletionTimestamp: deletionTimestamp,
+	}
+
+	return &response, nil
+}
+
+// cancelDeletion keeps an account scheduled for deletion whose owner logged in
+// again within the grace period.
+func (s userService) cancelDeletion(user *user.User) {
+	if !user.IsDeletionScheduled() {
+		return
+	}
+
+	err := s.userRepo.SetDeletionTimestamp(user.ID, nil)
+	if err != nil {
+		logs.GetInstance().Error(err)
+		return
+	}
+
+	user.DeletionTimestamp = nil
+}
+
+// rehashPassword upgrades a hash made under an older hashing policy, it can
+// only be done at login as that is the only time the plain password is known.
+func (s userService) rehashPassword(userID int, hashedPassword string, password string) {
+	if !utils.NeedsRehash(hashedPassword) {
+		return
+	}
+
+	err := s.userRepo.UpdatesByID(userID, map[string]interface{}{"password": utils.HashAndSalt(password)})
+	if err != nil {
+		logs.GetInstance().Error(err)
+	}
+}
+
+func (s userService) sendEmailVerification(user user.User) error {
+	expiration := emailVerificationExpire()
+
+	verificationToken, err := s.jwt.SignEmailVerification(user.ID, user.Email, expiration)
+	if err != nil {
+		return err
+	}
+
+	link := os.Getenv("EMAIL_VERIFICATION_URL") + "?token=" + verificationToken
+	mail := mailer.NewEmailVerificationMail(user.Email, user.Name, link, expiration)
+
+	go func() {
+		if err := s.mailer.Send(mail); err != nil {
+			logs.GetInstance().Error(err)
+		}
+	}()
+
+	return nil
+}
+
+func emailVerificationExpire() time.Duration {
+	hour := utils.ParseDuration(os.Getenv("EMAIL_VERIFICATION_EXPIRE_HOUR"))
+	if hour <= 0 {
+		hour = defaultEmailVerificationExpireHour
+	}
+	return time.Hour * hour
+}
+
+func emailVerificationResendInterval() time.Duration {
+	second := utils.ParseDuration(os.Getenv("EMAIL_VERIFICATION_RESEND_SECOND"))
+	if second <= 0 {
+		second = defaultEmailVerificationResendSecond
+	}
+	return time.Second * second
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"database-camp/internal/infrastructure/oidc"
+	"database-camp/internal/middleware/jwt"
+	"database-camp/internal/models/entities/user"
+	"database-camp/internal/models/request"
+	"database-camp/internal/repositories"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt"
+)
+
+// mockProvider is an OpenID Connect provider that answers every code with an
+// ID token for the claims set on it
+type mockProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	claims jwtgo.MapClaims
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &mockProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.server.URL,
+			"authorization_endpoint": p.server.URL + "/auth",
+			"token_endpoint":         p.server.URL + "/token",
+			"jwks_uri":               p.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		claims := jwtgo.MapClaims{
+			"iss":   p.server.URL,
+			"aud":   "client",
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"nonce": "nonce",
+		}
+		for k, v := range p.claims {
+			claims[k] = v
+		}
+
+		token := jwtgo.NewWithClaims(jwtgo.SigningMethodRS256, claims)
+		token.Header["kid"] = "test"
+
+		idToken, err := token.SignedString(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"id_token":     idToken,
+		})
+	})
+
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+
+	return p
+}
+
+func (p *mockProvider) providers() oidc.Providers {
+	return oidc.Providers{
+		"mock": oidc.NewProvider(oidc.ProviderConfig{
+			Name:     "mock",
+			Issuer:   p.server.URL,
+			ClientID: "client",
+		}),
+	}
+}
+
+// oidcUserRepo keeps users and identities in memory, methods the flow does
+// not use are left to the embedded nil interface
+type oidcUserRepo struct {
+	repositories.UserRepository
+
+	users      map[int]*user.User
+	identities map[string]user.Identity
+}
+
+func (r *oidcUserRepo) PopOIDCState(state string) (*user.OIDCState, error) {
+	return &user.OIDCState{Provider: "mock", Nonce: "nonce", CodeVerifier: "verifier"}, nil
+}
+
+func (r *oidcUserRepo) GetIdentity(provider string, subject string) (*user.Identity, error) {
+	identity := r.identities[provider+"::"+subject]
+	return &identity, nil
+}
+
+func (r *oidcUserRepo) GetUserByID(id int) (*user.User, error) {
+	if _user, ok := r.users[id]; ok {
+		copied := *_user
+		return &copied, nil
+	}
+	return &user.User{}, nil
+}
+
+func (r *oidcUserRepo) GetUserByEmail(email string) (*user.User, error) {
+	for _, _user := range r.users {
+		if _user.Email == email {
+			copied := *_user
+			return &copied, nil
+		}
+	}
+	return &user.User{}, nil
+}
+
+func (r *oidcUserRepo) InsertUser(_user user.User) (*user.User, error) {
+	_user.ID = len(r.users) + 1
+	r.users[_user.ID] = &_user
+	copied := _user
+	return &copied, nil
+}
+
+func (r *oidcUserRepo) InsertIdentity(identity user.Identity) (*user.Identity, error) {
+	r.identities[identity.Provider+"::"+identity.Subject] = identity
+	return &identity, nil
+}
+
+func (r *oidcUserRepo) UpdatesByID(id int, updateData map[string]interface{}) error {
+	if password, ok := updateData["password"].(string); ok {
+		r.users[id].Password = password
+	}
+	return nil
+}
+
+func (r *oidcUserRepo) VerifyEmail(userID int, email string) (bool, error) {
+	now := time.Now()
+	r.users[userID].VerifiedTimestamp = &now
+	return true, nil
+}
+
+type oidcSessionRepo struct {
+	repositories.SessionRepository
+
+	deleted []int
+}
+
+func (r *oidcSessionRepo) DeleteUserSessions(userID int) error {
+	r.deleted = append(r.deleted, userID)
+	return nil
+}
+
+type oidcJwt struct {
+	jwt.Jwt
+}
+
+func (j oidcJwt) Sign(userID int, client request.Client) (*jwt.Token, error) {
+	return &jwt.Token{AccessToken: "access", RefreshToken: "refresh"}, nil
+}
+
+func TestOIDCCallback(t *testing.T) {
+	verified := time.Now()
+
+	tests := []struct {
+		name       string
+		users      map[int]*user.User
+		identities map[string]user.Identity
+		claims     jwtgo.MapClaims
+		wantID     int
+		wantErr    bool
+		wantRevoke bool
+	}{
+		{
+			name:   "new user",
+			users:  map[int]*user.User{},
+			claims: jwtgo.MapClaims{"sub": "s1", "email": "new@example.com", "email_verified": true, "name": "New"},
+			wantID: 1,
+		},
+		{
+			name: "linked identity",
+			users: map[int]*user.User{
+				1: {ID: 1, Email: "old@example.com", Password: "hash", VerifiedTimestamp: &verified},
+			},
+			identities: map[string]user.Identity{
+				"mock::s1": {Provider: "mock", Subject: "s1", UserID: 1},
+			},
+			claims: jwtgo.MapClaims{"sub": "s1", "email": "other@example.com", "email_verified": true},
+			wantID: 1,
+		},
+		{
+			name: "existing unverified email",
+			users: map[int]*user.User{
+				1: {ID: 1, Email: "victim@example.com", Password: "attacker"},
+			},
+			claims:     jwtgo.MapClaims{"sub": "s1", "email": "victim@example.com", "email_verified": true},
+			wantID:     1,
+			wantRevoke: true,
+		},
+		{
+			name:    "email not verified by provider",
+			users:   map[int]*user.User{},
+			claims:  jwtgo.MapClaims{"sub": "s1", "email": "new@example.com", "email_verified": false},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			provider := newMockProvider(t)
+			provider.claims = test.claims
+
+			identities := test.identities
+			if identities == nil {
+				identities = map[string]user.Identity{}
+			}
+
+			userRepo := &oidcUserRepo{users: test.users, identities: identities}
+			sessionRepo := &oidcSessionRepo{}
+
+			s := userService{
+				userRepo:    userRepo,
+				sessionRepo: sessionRepo,
+				jwt:         oidcJwt{},
+				providers:   provider.providers(),
+			}
+
+			response, err := s.OIDCCallback("mock", request.OIDCCallbackRequest{Code: "code", State: "state"}, request.Client{})
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got user %d", response.ID)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if response.ID != test.wantID {
+				t.Errorf("user = %d, want %d", response.ID, test.wantID)
+			}
+			if !response.IsVerified {
+				t.Error("user is not verified")
+			}
+
+			if _, ok := identities["mock::s1"]; !ok {
+				t.Error("identity is not linked")
+			}
+
+			if test.wantRevoke {
+				if userRepo.users[test.wantID].Password != "" {
+					t.Error("password of the unverified account is kept")
+				}
+				if len(sessionRepo.deleted) != 1 || sessionRepo.deleted[0] != test.wantID {
+					t.Errorf("revoked sessions of %v, want [%d]", sessionRepo.deleted, test.wantID)
+				}
+			} else if len(sessionRepo.deleted) > 0 {
+				t.Errorf("revoked sessions of %v, want none", sessionRepo.deleted)
+			}
+		})
+	}
+}
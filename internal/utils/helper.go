@@ -1,29 +1,29 @@
 package utils
 
 import (
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"math/rand"
 	"net/mail"
 	"reflect"
 
 	m "github.com/go-sql-driver/mysql"
-	"golang.org/x/crypto/bcrypt"
 )
 
-func HashAndSalt(pwd string) string {
-	bytePassword := []byte(pwd)
-	hash, err := bcrypt.GenerateFromPassword(bytePassword, 4)
+func RandomToken() (string, error) {
+	b := make([]byte, 32)
+	_, err := crand.Read(b)
 	if err != nil {
-		panic(err.Error())
+		return "", err
 	}
-	return string(hash)
+	return hex.EncodeToString(b), nil
 }
 
-func ComparePasswords(hashedPwd string, plainPwd string) bool {
-	bytePlainPassword := []byte(plainPwd)
-	byteHash := []byte(hashedPwd)
-	err := bcrypt.CompareHashAndPassword(byteHash, bytePlainPassword)
-	return err == nil
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
 }
 
 func IsEmailValid(email string) bool {
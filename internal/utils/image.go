@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"net/http"
+
+	_ "image/gif"
+	_ "image/png"
+)
+
+var (
+	ErrImageFormat     = errors.New("image: format not supported")
+	ErrImageDimensions = errors.New("image: dimensions out of range")
+)
+
+var imageContentTypes = []string{"image/jpeg", "image/png", "image/gif"}
+
+/**
+ * Decode an uploaded JPEG, PNG or GIF image, the header is checked before
+ * decoding so oversized images are rejected without being loaded
+ *
+ * @param data 		content of the file
+ * @param maxPixel 	largest width or height allowed
+ *
+ * @return the decoded image
+ */
+func DecodeImage(data []byte, maxPixel int) (image.Image, error) {
+	contentType := http.DetectContentType(data)
+
+	supported := false
+	for _, t := range imageContentTypes {
+		if t == contentType {
+			supported = true
+		}
+	}
+
+	if !supported {
+		return nil, ErrImageFormat
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Width <= 0 || config.Height <= 0 || config.Width > maxPixel || config.Height > maxPixel {
+		return nil, ErrImageDimensions
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+/**
+ * Crop the center square of the image and scale it to size x size, every
+ * target pixel is the average of the source pixels it covers
+ *
+ * @param img 	source image
+ * @param size 	width and height of the result
+ *
+ * @return the resized image
+ */
+func ResizeSquare(img image.Image, size int) *image.RGBA {
+	bounds := img.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+
+	left := bounds.Min.X + (bounds.Dx()-side)/2
+	top := bounds.Min.Y + (bounds.Dy()-side)/2
+
+	// Draw over white first so transparent pixels do not turn black in JPEG
+	src := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(src, src.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(src, src.Bounds(), img, image.Point{X: left, Y: top}, draw.Over)
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	for y := 0; y < size; y++ {
+		y0, y1 := y*side/size, (y+1)*side/size
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+
+		for x := 0; x < size; x++ {
+			x0, x1 := x*side/size, (x+1)*side/size
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					i := src.PixOffset(sx, sy)
+					r += uint32(src.Pix[i])
+					g += uint32(src.Pix[i+1])
+					b += uint32(src.Pix[i+2])
+					a += uint32(src.Pix[i+3])
+					n++
+				}
+			}
+
+			dst.SetRGBA(x, y, color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(b / n), A: uint8(a / n)})
+		}
+	}
+
+	return dst
+}
+
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	err := jpeg.Encode(buffer, img, &jpeg.Options{Quality: 85})
+	return buffer.Bytes(), err
+}
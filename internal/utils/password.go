@@ -0,0 +1,201 @@
+package utils
+
+import (
+	crand "crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	PASSWORD_HASH_BCRYPT   = "bcrypt"
+	PASSWORD_HASH_ARGON2ID = "argon2id"
+)
+
+const (
+	defaultPasswordMinLength = 8
+	bcryptMaxBytes           = 72
+	defaultBcryptCost        = 12
+	defaultArgon2Memory      = 64 * 1024
+	defaultArgon2Time        = 3
+	defaultArgon2Threads     = 2
+	argon2SaltLength         = 16
+	argon2KeyLength          = 32
+	argon2PHCFormat          = "$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s"
+	argon2PHCFormatFields    = 6
+)
+
+// PasswordHashing is the policy new hashes are made with, read from
+// PASSWORD_HASH_ALGORITHM, PASSWORD_BCRYPT_COST and PASSWORD_ARGON2_*.
+type PasswordHashing struct {
+	Algorithm     string
+	BcryptCost    int
+	Argon2Memory  uint32
+	Argon2Time    uint32
+	Argon2Threads uint8
+}
+
+func GetPasswordHashing() PasswordHashing {
+	hashing := PasswordHashing{
+		Algorithm:     strings.ToLower(os.Getenv("PASSWORD_HASH_ALGORITHM")),
+		BcryptCost:    ParseInt(os.Getenv("PASSWORD_BCRYPT_COST")),
+		Argon2Memory:  uint32(ParseInt(os.Getenv("PASSWORD_ARGON2_MEMORY_KB"))),
+		Argon2Time:    uint32(ParseInt(os.Getenv("PASSWORD_ARGON2_TIME"))),
+		Argon2Threads: uint8(ParseInt(os.Getenv("PASSWORD_ARGON2_THREADS"))),
+	}
+
+	if hashing.Algorithm != PASSWORD_HASH_ARGON2ID {
+		hashing.Algorithm = PASSWORD_HASH_BCRYPT
+	}
+	if hashing.BcryptCost < bcrypt.MinCost || hashing.BcryptCost > bcrypt.MaxCost {
+		hashing.BcryptCost = defaultBcryptCost
+	}
+	if hashing.Argon2Memory == 0 {
+		hashing.Argon2Memory = defaultArgon2Memory
+	}
+	if hashing.Argon2Time == 0 {
+		hashing.Argon2Time = defaultArgon2Time
+	}
+	if hashing.Argon2Threads == 0 {
+		hashing.Argon2Threads = defaultArgon2Threads
+	}
+
+	return hashing
+}
+
+// PasswordPolicy is what a new password must satisfy, read from
+// PASSWORD_MIN_LENGTH, PASSWORD_MAX_BYTES and PASSWORD_REQUIRE_*. The byte
+// limit keeps passwords within what bcrypt hashes.
+type PasswordPolicy struct {
+	MinLength     int
+	MaxBytes      int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+func GetPasswordPolicy() PasswordPolicy {
+	policy := PasswordPolicy{
+		MinLength:     ParseInt(os.Getenv("PASSWORD_MIN_LENGTH")),
+		MaxBytes:      ParseInt(os.Getenv("PASSWORD_MAX_BYTES")),
+		RequireUpper:  os.Getenv("PASSWORD_REQUIRE_UPPER") == "true",
+		RequireLower:  os.Getenv("PASSWORD_REQUIRE_LOWER") == "true",
+		RequireDigit:  os.Getenv("PASSWORD_REQUIRE_DIGIT") == "true",
+		RequireSymbol: os.Getenv("PASSWORD_REQUIRE_SYMBOL") == "true",
+	}
+
+	if policy.MinLength <= 0 {
+		policy.MinLength = defaultPasswordMinLength
+	}
+	if policy.MaxBytes <= 0 || policy.MaxBytes > bcryptMaxBytes {
+		policy.MaxBytes = bcryptMaxBytes
+	}
+
+	return policy
+}
+
+func HashAndSalt(pwd string) string {
+	hashing := GetPasswordHashing()
+
+	if hashing.Algorithm == PASSWORD_HASH_ARGON2ID {
+		salt := make([]byte, argon2SaltLength)
+		if _, err := crand.Read(salt); err != nil {
+			panic(err.Error())
+		}
+
+		key := argon2.IDKey([]byte(pwd), salt, hashing.Argon2Time, hashing.Argon2Memory, hashing.Argon2Threads, argon2KeyLength)
+
+		return fmt.Sprintf(argon2PHCFormat,
+			argon2.Version,
+			hashing.Argon2Memory,
+			hashing.Argon2Time,
+			hashing.Argon2Threads,
+			base64.RawStdEncoding.EncodeToString(salt),
+			base64.RawStdEncoding.EncodeToString(key),
+		)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(pwd), hashing.BcryptCost)
+	if err != nil {
+		panic(err.Error())
+	}
+	return string(hash)
+}
+
+func ComparePasswords(hashedPwd string, plainPwd string) bool {
+	if strings.HasPrefix(hashedPwd, "$argon2id$") {
+		params, salt, key, err := decodeArgon2(hashedPwd)
+		if err != nil {
+			return false
+		}
+
+		otherKey := argon2.IDKey([]byte(plainPwd), salt, params.Argon2Time, params.Argon2Memory, params.Argon2Threads, uint32(len(key)))
+
+		return subtle.ConstantTimeCompare(key, otherKey) == 1
+	}
+
+	bytePlainPassword := []byte(plainPwd)
+	byteHash := []byte(hashedPwd)
+	err := bcrypt.CompareHashAndPassword(byteHash, bytePlainPassword)
+	return err == nil
+}
+
+// NeedsRehash reports whether the hash was made with another algorithm or
+// weaker parameters than the current policy.
+func NeedsRehash(hashedPwd string) bool {
+	hashing := GetPasswordHashing()
+
+	if hashing.Algorithm == PASSWORD_HASH_ARGON2ID {
+		params, _, _, err := decodeArgon2(hashedPwd)
+		return err != nil ||
+			params.Argon2Memory < hashing.Argon2Memory ||
+			params.Argon2Time < hashing.Argon2Time ||
+			params.Argon2Threads < hashing.Argon2Threads
+	}
+
+	cost, err := bcrypt.Cost([]byte(hashedPwd))
+	return err != nil || cost < hashing.BcryptCost
+}
+
+func decodeArgon2(hashedPwd string) (*PasswordHashing, []byte, []byte, error) {
+	fields := strings.Split(hashedPwd, "$")
+	if len(fields) != argon2PHCFormatFields {
+		return nil, nil, nil, fmt.Errorf("argon2: invalid hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(fields[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, nil, nil, fmt.Errorf("argon2: unsupported version")
+	}
+
+	params := PasswordHashing{Algorithm: PASSWORD_HASH_ARGON2ID}
+	if _, err := fmt.Sscanf(fields[3], "m=%d,t=%d,p=%d", &params.Argon2Memory, &params.Argon2Time, &params.Argon2Threads); err != nil {
+		return nil, nil, nil, err
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(fields[4])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(fields[5])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// An empty key matches every password and argon2 panics on zero time or
+	// threads, so such a hash is never compared
+	if len(salt) == 0 || len(key) == 0 ||
+		params.Argon2Time == 0 || params.Argon2Threads == 0 ||
+		params.Argon2Memory < 8*uint32(params.Argon2Threads) {
+		return nil, nil, nil, fmt.Errorf("argon2: invalid parameters")
+	}
+
+	return &params, salt, key, nil
+}
@@ -0,0 +1,30 @@
+--
+-- Table structure for table `Session`
+--
+
+CREATE TABLE `Session` (
+  `session_id` int(11) NOT NULL,
+  `user_id` int(11) NOT NULL,
+  `refresh_token` varchar(64) NOT NULL,
+  `user_agent` text NOT NULL,
+  `ip_address` varchar(45) NOT NULL,
+  `expired_timestamp` timestamp NULL DEFAULT NULL,
+  `created_timestamp` timestamp NOT NULL DEFAULT current_timestamp(),
+  `updated_timestamp` timestamp NOT NULL DEFAULT current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE `Session`
+  ADD PRIMARY KEY (`session_id`),
+  ADD UNIQUE KEY `refresh_token` (`refresh_token`),
+  ADD KEY `user_id` (`user_id`);
+
+ALTER TABLE `Session`
+  MODIFY `session_id` int(11) NOT NULL AUTO_INCREMENT;
+
+--
+-- Tokens are now tracked per session instead of on the `User` row
+--
+
+ALTER TABLE `User`
+  DROP COLUMN `access_token`,
+  DROP COLUMN `expired_token_timestamp`;
@@ -0,0 +1,10 @@
+--
+-- Refresh tokens and session expiry are kept in Redis, the `Session` table
+-- only records the device a session was opened from
+--
+
+ALTER TABLE `Session`
+  DROP INDEX `refresh_token`,
+  DROP COLUMN `refresh_token`,
+  DROP COLUMN `expired_timestamp`,
+  DROP COLUMN `updated_timestamp`;
@@ -0,0 +1,18 @@
+--
+-- Users start unverified until they follow the link sent to their email,
+-- accounts created before verification existed are treated as verified
+--
+
+ALTER TABLE `User`
+  ADD `verified_timestamp` timestamp NULL DEFAULT NULL AFTER `point`;
+
+UPDATE `User` SET `verified_timestamp` = `created_timestamp`;
+
+--
+-- Unverified users do not appear in the ranking
+--
+
+CREATE OR REPLACE ALGORITHM=UNDEFINED SQL SECURITY DEFINER VIEW `Ranking` AS
+SELECT `User`.`user_id` AS `user_id`, `User`.`name` AS `name`, `User`.`email` AS `email`, `User`.`point` AS `point`, row_number() over ( order by `User`.`point` desc) AS `ranking`
+FROM `User`
+WHERE `User`.`verified_timestamp` IS NOT NULL;
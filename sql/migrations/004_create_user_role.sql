@@ -0,0 +1,23 @@
+--
+-- Table structure for table `UserRole`
+--
+
+CREATE TABLE `UserRole` (
+  `user_id` int(11) NOT NULL,
+  `role` varchar(20) NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE `UserRole`
+  ADD PRIMARY KEY (`user_id`,`role`);
+
+--
+-- Every existing user starts as a student
+--
+
+INSERT INTO `UserRole` (`user_id`, `role`)
+SELECT `user_id`, 'student' FROM `User`;
+
+--
+-- The first admin has to be granted by hand, e.g.
+-- INSERT INTO `UserRole` (`user_id`, `role`) VALUES (1, 'admin');
+--
@@ -0,0 +1,15 @@
+--
+-- Table structure for table `UserIdentity`
+--
+
+CREATE TABLE `UserIdentity` (
+  `provider` varchar(50) NOT NULL,
+  `subject` varchar(255) NOT NULL,
+  `user_id` int(11) NOT NULL,
+  `email` varchar(50) NOT NULL,
+  `created_timestamp` timestamp NOT NULL DEFAULT current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE `UserIdentity`
+  ADD PRIMARY KEY (`provider`,`subject`),
+  ADD KEY `user_id` (`user_id`);
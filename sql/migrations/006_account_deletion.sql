@@ -0,0 +1,17 @@
+--
+-- A user who asks to delete the account keeps it until `deletion_timestamp`,
+-- logging in before then cancels the deletion
+--
+
+ALTER TABLE `User`
+  ADD `deletion_timestamp` timestamp NULL DEFAULT NULL AFTER `verified_timestamp`,
+  ADD KEY `deletion_timestamp` (`deletion_timestamp`);
+
+--
+-- Accounts waiting for deletion do not appear in the ranking
+--
+
+CREATE OR REPLACE ALGORITHM=UNDEFINED SQL SECURITY DEFINER VIEW `Ranking` AS
+SELECT `User`.`user_id` AS `user_id`, `User`.`name` AS `name`, `User`.`email` AS `email`, `User`.`point` AS `point`, row_number() over ( order by `User`.`point` desc) AS `ranking`
+FROM `User`
+WHERE `User`.`verified_timestamp` IS NOT NULL AND `User`.`deletion_timestamp` IS NULL;
@@ -0,0 +1,23 @@
+--
+-- Profile fields of the user, `avatar` is the object name prefix of the
+-- resized pictures in the cloud storage bucket
+--
+
+ALTER TABLE `User`
+  ADD `avatar` varchar(255) NOT NULL DEFAULT '' AFTER `point`,
+  ADD `bio` varchar(500) NOT NULL DEFAULT '' AFTER `avatar`,
+  ADD `language` varchar(2) NOT NULL DEFAULT 'th' AFTER `bio`;
+
+--
+-- Profile and Ranking carry the avatar and bio
+--
+
+CREATE OR REPLACE ALGORITHM=UNDEFINED SQL SECURITY DEFINER VIEW `Profile` AS
+SELECT `User`.`user_id` AS `user_id`, `User`.`name` AS `name`, `User`.`point` AS `point`, `User`.`avatar` AS `avatar`, `User`.`bio` AS `bio`, `User`.`language` AS `language`, `User`.`created_timestamp` AS `created_timestamp`, count(`LearningProgression`.`activity_id`) AS `activity_count`
+FROM (`User` left join `LearningProgression` on(`User`.`user_id` = `LearningProgression`.`user_id`))
+GROUP BY `User`.`user_id`;
+
+CREATE OR REPLACE ALGORITHM=UNDEFINED SQL SECURITY DEFINER VIEW `Ranking` AS
+SELECT `User`.`user_id` AS `user_id`, `User`.`name` AS `name`, `User`.`email` AS `email`, `User`.`point` AS `point`, `User`.`avatar` AS `avatar`, row_number() over ( order by `User`.`point` desc) AS `ranking`
+FROM `User`
+WHERE `User`.`verified_timestamp` IS NOT NULL AND `User`.`deletion_timestamp` IS NULL;
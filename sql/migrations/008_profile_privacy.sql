@@ -0,0 +1,24 @@
+--
+-- Who can see the profile of the user, one of 'public', 'logged_in' or
+-- 'private'. Existing profiles stay visible to logged in users only, as they
+-- were before
+--
+
+ALTER TABLE `User`
+  ADD `profile_visibility` varchar(20) NOT NULL DEFAULT 'logged_in' AFTER `language`,
+  ADD `hide_from_ranking` tinyint(1) NOT NULL DEFAULT 0 AFTER `profile_visibility`;
+
+--
+-- Profile carries the visibility, Ranking leaves out users who opted out and
+-- no longer exposes the email
+--
+
+CREATE OR REPLACE ALGORITHM=UNDEFINED SQL SECURITY DEFINER VIEW `Profile` AS
+SELECT `User`.`user_id` AS `user_id`, `User`.`name` AS `name`, `User`.`point` AS `point`, `User`.`avatar` AS `avatar`, `User`.`bio` AS `bio`, `User`.`language` AS `language`, `User`.`profile_visibility` AS `profile_visibility`, `User`.`hide_from_ranking` AS `hide_from_ranking`, `User`.`created_timestamp` AS `created_timestamp`, count(`LearningProgression`.`activity_id`) AS `activity_count`
+FROM (`User` left join `LearningProgression` on(`User`.`user_id` = `LearningProgression`.`user_id`))
+GROUP BY `User`.`user_id`;
+
+CREATE OR REPLACE ALGORITHM=UNDEFINED SQL SECURITY DEFINER VIEW `Ranking` AS
+SELECT `User`.`user_id` AS `user_id`, `User`.`name` AS `name`, `User`.`point` AS `point`, `User`.`avatar` AS `avatar`, row_number() over ( order by `User`.`point` desc) AS `ranking`
+FROM `User`
+WHERE `User`.`verified_timestamp` IS NOT NULL AND `User`.`deletion_timestamp` IS NULL AND `User`.`hide_from_ranking` = 0;
@@ -0,0 +1,16 @@
+--
+-- SQL query exercises, the learner writes a SELECT statement that is run
+-- against the dataset of the activity and compared with the reference query
+--
+
+INSERT INTO `ActivityType` (`activity_type_id`, `name`) VALUES (8, 'sql_query');
+
+CREATE TABLE `SQLQueryChoice` (
+  `activity_id` int(11) NOT NULL,
+  `dataset` text NOT NULL,
+  `reference_query` text NOT NULL,
+  `order_sensitive` tinyint(1) NOT NULL DEFAULT 0
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE `SQLQueryChoice`
+  ADD PRIMARY KEY (`activity_id`);
@@ -0,0 +1,18 @@
+--
+-- Normalization exercises, the learner decomposes a relation into
+-- sub-relations in a target normal form. The functional dependencies of the
+-- relation are kept in `DependencyChoice`, `Dependency` and `Determinant`
+--
+
+INSERT INTO `ActivityType` (`activity_type_id`, `name`) VALUES (9, 'normalization');
+
+CREATE TABLE `NormalizationChoice` (
+  `activity_id` int(11) NOT NULL,
+  `relation` varchar(50) NOT NULL,
+  `attributes` text NOT NULL,
+  `target_form` varchar(4) NOT NULL,
+  `allow_dependency_loss` tinyint(1) NOT NULL DEFAULT 0
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE `NormalizationChoice`
+  ADD PRIMARY KEY (`activity_id`);
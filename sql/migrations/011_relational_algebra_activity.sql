@@ -0,0 +1,16 @@
+--
+-- Relational algebra exercises, the learner writes an expression over the
+-- sample relations of the activity, kept as JSON, and it is compared with
+-- the reference expression
+--
+
+INSERT INTO `ActivityType` (`activity_type_id`, `name`) VALUES (10, 'relational_algebra');
+
+CREATE TABLE `RelationalAlgebraChoice` (
+  `activity_id` int(11) NOT NULL,
+  `relations` text NOT NULL,
+  `reference_expression` text NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE `RelationalAlgebraChoice`
+  ADD PRIMARY KEY (`activity_id`);
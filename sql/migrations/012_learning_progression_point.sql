@@ -0,0 +1,16 @@
+--
+-- Points an answer earned, answers get partial credit and a user is awarded
+-- only what an answer earns over the best earlier answer to the activity
+--
+
+ALTER TABLE `LearningProgression`
+  ADD `point` int(11) NOT NULL DEFAULT 0 AFTER `is_correct`;
+
+--
+-- Correct answers given before partial credit earned the whole point
+--
+
+UPDATE `LearningProgression`
+  JOIN `Activity` ON `Activity`.`activity_id` = `LearningProgression`.`activity_id`
+  SET `LearningProgression`.`point` = `Activity`.`point`
+  WHERE `LearningProgression`.`is_correct` = 1;
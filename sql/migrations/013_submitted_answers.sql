@@ -0,0 +1,15 @@
+--
+-- The answer a learner submitted, kept as JSON in the format of
+-- `answer_version` so that it can be reviewed and graded again when the
+-- answer key of the activity is fixed
+--
+
+ALTER TABLE `LearningProgression`
+  ADD `answer` mediumtext NULL DEFAULT NULL AFTER `point`,
+  ADD `answer_version` int(11) NULL DEFAULT NULL AFTER `answer`,
+  ADD KEY `activity_id_answer_version` (`activity_id`, `answer_version`);
+
+ALTER TABLE `ExamResultActivity`
+  ADD `answer` mediumtext NULL DEFAULT NULL AFTER `score`,
+  ADD `answer_version` int(11) NULL DEFAULT NULL AFTER `answer`,
+  ADD KEY `activity_id_answer_version` (`activity_id`, `answer_version`);
@@ -0,0 +1,31 @@
+--
+-- Every change of the point of a user, `User`.`point` is the sum of the
+-- amounts of the user and `balance` the point after the change
+--
+
+CREATE TABLE `PointTransaction` (
+  `point_transaction_id` int(11) NOT NULL,
+  `user_id` int(11) NOT NULL,
+  `amount` int(11) NOT NULL,
+  `balance` int(11) NOT NULL,
+  `reason` varchar(32) NOT NULL,
+  `reference_id` int(11) NULL DEFAULT NULL,
+  `note` varchar(255) NOT NULL DEFAULT '',
+  `created_timestamp` timestamp NOT NULL DEFAULT current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE `PointTransaction`
+  ADD PRIMARY KEY (`point_transaction_id`),
+  ADD KEY `user_id_created_timestamp` (`user_id`, `created_timestamp`);
+
+ALTER TABLE `PointTransaction`
+  MODIFY `point_transaction_id` int(11) NOT NULL AUTO_INCREMENT;
+
+--
+-- The point each user already has when the ledger opens
+--
+
+INSERT INTO `PointTransaction` (`user_id`, `amount`, `balance`, `reason`)
+SELECT `user_id`, `point`, `point`, 'opening_balance'
+FROM `User`
+WHERE `point` <> 0;
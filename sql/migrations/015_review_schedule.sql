@@ -0,0 +1,31 @@
+--
+-- When each learner should answer an activity again, scheduled with SM-2
+-- from the answers to the activity after the first correct one
+--
+
+CREATE TABLE `ReviewSchedule` (
+  `user_id` int(11) NOT NULL,
+  `activity_id` int(11) NOT NULL,
+  `repetition` int(11) NOT NULL DEFAULT 0,
+  `interval_day` int(11) NOT NULL DEFAULT 0,
+  `ease_factor` double NOT NULL DEFAULT 2.5,
+  `due_timestamp` timestamp NOT NULL DEFAULT current_timestamp(),
+  `reviewed_timestamp` timestamp NULL DEFAULT NULL,
+  `created_timestamp` timestamp NOT NULL DEFAULT current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE `ReviewSchedule`
+  ADD PRIMARY KEY (`user_id`, `activity_id`),
+  ADD KEY `user_id_due_timestamp` (`user_id`, `due_timestamp`);
+
+--
+-- Activities already answered correctly are due a day after the last
+-- correct answer, the peer activity (10) is answered by reviewing others
+-- and is not scheduled
+--
+
+INSERT INTO `ReviewSchedule` (`user_id`, `activity_id`, `repetition`, `interval_day`, `due_timestamp`, `reviewed_timestamp`, `created_timestamp`)
+SELECT `user_id`, `activity_id`, 1, 1, MAX(`created_timestamp`) + INTERVAL 1 DAY, MAX(`created_timestamp`), MIN(`created_timestamp`)
+FROM `LearningProgression`
+WHERE `is_correct` = 1 AND `activity_id` <> 10
+GROUP BY `user_id`, `activity_id`;
@@ -0,0 +1,16 @@
+--
+-- How likely each learner has learned each content, updated with Bayesian
+-- Knowledge Tracing by every checked answer and exam. A learner without a
+-- row for a content has not answered any of its activities yet.
+--
+
+CREATE TABLE `ContentMastery` (
+  `user_id` int(11) NOT NULL,
+  `content_id` int(11) NOT NULL,
+  `probability` double NOT NULL,
+  `attempts` int(11) NOT NULL DEFAULT 0,
+  `updated_timestamp` timestamp NOT NULL DEFAULT current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE `ContentMastery`
+  ADD PRIMARY KEY (`user_id`, `content_id`);
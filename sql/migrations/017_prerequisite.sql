@@ -0,0 +1,22 @@
+--
+-- Rules a content or content group is locked behind. `required_id` is a
+-- content for `complete_activities` (`count` of its activities answered
+-- correctly, 0 for all), a content group for `pass_mini_exam` and a badge
+-- for `earn_badge`
+--
+
+CREATE TABLE `Prerequisite` (
+  `prerequisite_id` int(11) NOT NULL,
+  `target_type` varchar(32) NOT NULL,
+  `target_id` int(11) NOT NULL,
+  `rule` varchar(32) NOT NULL,
+  `required_id` int(11) NOT NULL,
+  `count` int(11) NOT NULL DEFAULT 0
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE `Prerequisite`
+  ADD PRIMARY KEY (`prerequisite_id`),
+  ADD KEY `target_type_target_id` (`target_type`, `target_id`);
+
+ALTER TABLE `Prerequisite`
+  MODIFY `prerequisite_id` int(11) NOT NULL AUTO_INCREMENT;
@@ -0,0 +1,53 @@
+--
+-- Files learners download alongside a content: slides, sample databases and
+-- cheat sheets. `size` is in bytes and `object_name` is where the file is kept
+-- in the cloud storage bucket
+--
+
+CREATE TABLE `Attachment` (
+  `attachment_id` int(11) NOT NULL,
+  `content_id` int(11) NOT NULL,
+  `type` varchar(32) NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `file_name` varchar(255) NOT NULL,
+  `object_name` varchar(255) NOT NULL,
+  `content_type` varchar(255) NOT NULL,
+  `size` bigint(20) NOT NULL DEFAULT 0,
+  `created_timestamp` timestamp NOT NULL DEFAULT current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE `Attachment`
+  ADD PRIMARY KEY (`attachment_id`),
+  ADD KEY `content_id` (`content_id`);
+
+ALTER TABLE `Attachment`
+  MODIFY `attachment_id` int(11) NOT NULL AUTO_INCREMENT;
+
+--
+-- Every signed download link handed out, for analytics
+--
+
+CREATE TABLE `AttachmentDownload` (
+  `attachment_download_id` int(11) NOT NULL,
+  `attachment_id` int(11) NOT NULL,
+  `user_id` int(11) NOT NULL,
+  `created_timestamp` timestamp NOT NULL DEFAULT current_timestamp()
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE `AttachmentDownload`
+  ADD PRIMARY KEY (`attachment_download_id`),
+  ADD KEY `attachment_id` (`attachment_id`),
+  ADD KEY `user_id` (`user_id`);
+
+ALTER TABLE `AttachmentDownload`
+  MODIFY `attachment_download_id` int(11) NOT NULL AUTO_INCREMENT;
+
+--
+-- The slides already on `Content`.`slide_path` become slide attachments, their
+-- size and type were never recorded
+--
+
+INSERT INTO `Attachment` (`content_id`, `type`, `name`, `file_name`, `object_name`, `content_type`, `size`)
+SELECT `content_id`, 'slide', `name`, SUBSTRING_INDEX(`slide_path`, '/', -1), `slide_path`, 'application/octet-stream', 0
+FROM `Content`
+WHERE `slide_path` IS NOT NULL AND `slide_path` <> '';